@@ -2,7 +2,9 @@ package databases
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"go-backend/internal/authz"
 	"go-backend/internal/models"
 )
 
@@ -11,15 +13,46 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Department{},
+		&models.DepartmentManager{},
 		&models.Employee{},
 		&models.Attendance{},
 		&models.AuditLog{},
 		&models.LeaveRequest{},
 		&models.Payslip{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.LoginThrottle{},
+		&models.PasswordResetToken{},
+		&models.MFAFactor{},
+		&models.MFARecoveryCode{},
+		&models.SigningKey{},
+		&models.UserIdentity{},
+		&models.OIDCLoginState{},
+		&models.ServiceAccount{},
+		&models.APIKey{},
+		&models.PasswordHistory{},
+		&models.Session{},
+		&models.Invitation{},
+		&models.Permission{},
+		&models.Role{},
+		&models.RolePermission{},
+		&models.PermissionGrant{},
+		&models.EmploymentEvent{},
+		&models.Offboarding{},
+		&models.OffboardingTask{},
+		&models.OffboardingTaskTemplate{},
 	); err != nil {
 		return err
 	}
 
+	if err := seedRoles(db); err != nil {
+		return err
+	}
+
+	if err := backfillEmploymentHistory(db); err != nil {
+		return err
+	}
+
 	// Attendance indexes
 	db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_attendance_employee_date
@@ -44,3 +77,68 @@ func Migrate(db *gorm.DB) error {
 
 	return nil
 }
+
+// backfillEmploymentHistory opens the history of employees created before
+// it was tracked with a hire event holding their current record, so that
+// replaying events reproduces it.
+func backfillEmploymentHistory(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO employment_events
+			(id, created_at, updated_at, employee_id, type, effective_date,
+			 department_id, role, status, job_title, reason, applied_at)
+		SELECT gen_random_uuid(), NOW(), NOW(), e.id, ?, e.hire_date::date,
+			e.department_id, u.role, e.status, NULLIF(e.job_title, ''),
+			'backfilled from the employee record', NOW()
+		FROM employees e
+		JOIN users u ON u.id = e.user_id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM employment_events ev WHERE ev.employee_id = e.id
+		)
+	`, models.EmploymentEventHire).Error
+}
+
+// seedRoles mirrors the authz catalog into the permissions table and creates
+// the system roles. Default grants are only written for a role or permission
+// seen for the first time, so edits made by admins survive restarts.
+func seedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		newPermissions := map[string]bool{}
+		for _, name := range authz.Permissions() {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Permission{
+				Name:        name,
+				Description: authz.PermissionDescription(name),
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			newPermissions[name] = result.RowsAffected > 0
+		}
+
+		for _, name := range authz.SystemRoles() {
+			role := models.Role{Name: name, IsSystem: true}
+			result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&role)
+			if result.Error != nil {
+				return result.Error
+			}
+			roleCreated := result.RowsAffected > 0
+			if !roleCreated {
+				if err := tx.First(&role, "name = ?", name).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, permission := range authz.DefaultPermissionsForRole(name) {
+				if !roleCreated && !newPermissions[permission] {
+					continue
+				}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Permission").Create(&models.RolePermission{
+					RoleID:         role.ID,
+					PermissionName: permission,
+				}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
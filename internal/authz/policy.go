@@ -1,9 +1,15 @@
 package authz
 
+import "sync"
+
 const (
 	RoleAdmin    = "admin"
 	RoleManager  = "manager"
 	RoleEmployee = "employee"
+
+	// RoleServiceAccount marks integration principals. The role grants
+	// nothing by itself; each API key carries its own permissions.
+	RoleServiceAccount = "service_account"
 )
 
 const (
@@ -22,8 +28,54 @@ const (
 	PermUpdateProfile     = "update_profile"
 	PermManagePayslips    = "manage_payslips"
 	PermViewOwnPayslips   = "view_own_payslips"
+	PermManageUsers       = "manage_users"
+	PermImpersonateUsers  = "impersonate_users"
+	PermManageRoles       = "manage_roles"
+
+	// PermManageAllDepartments lifts department scoping: without it, employee,
+	// leave and payslip management only reaches departments the user manages.
+	PermManageAllDepartments = "manage_all_departments"
+	PermManageGrants         = "manage_permission_grants"
+
+	// PermViewCompensation reveals pay amounts of other people and
+	// PermViewPersonalData their contact and employment details. Everyone
+	// always sees their own.
+	PermViewCompensation = "view_compensation"
+	PermViewPersonalData = "view_personal_data"
+	PermViewOrgChart     = "view_org_chart"
 )
 
+// permissionCatalog lists every permission the code checks, with the
+// description shown to admins composing roles.
+var permissionCatalog = []struct {
+	Name        string
+	Description string
+}{
+	{PermManageEmployees, "Create, update, deactivate, terminate and rehire employees"},
+	{PermManageDepartments, "Create departments and their offboarding checklists"},
+	{PermViewDepartments, "List departments"},
+	{PermViewAnalytics, "View attendance analytics"},
+	{PermExportReports, "Export attendance reports"},
+	{PermViewAuditLogs, "View and export audit logs"},
+	{PermReviewLeaves, "Approve or reject leave requests"},
+	{PermRequestLeave, "Request leave"},
+	{PermViewOwnLeaves, "View and cancel own leave requests"},
+	{PermClockAttendance, "Clock in and out"},
+	{PermViewNotifications, "View notifications"},
+	{PermViewProfile, "View own profile"},
+	{PermUpdateProfile, "Update own profile and MFA"},
+	{PermManagePayslips, "Generate and view all payslips"},
+	{PermViewOwnPayslips, "View own payslips"},
+	{PermManageUsers, "Administer user accounts, sessions and service accounts"},
+	{PermImpersonateUsers, "View the application as another user"},
+	{PermManageRoles, "Define roles and their permissions"},
+	{PermManageAllDepartments, "Manage employees, leave and payslips in every department"},
+	{PermManageGrants, "Grant permissions temporarily and delegate between users"},
+	{PermViewCompensation, "See other people's pay amounts"},
+	{PermViewPersonalData, "See other people's email addresses and hire dates"},
+	{PermViewOrgChart, "View reporting lines and the org chart"},
+}
+
 var rolePermissions = map[string][]string{
 	RoleAdmin: {
 		PermManageEmployees,
@@ -41,6 +93,14 @@ var rolePermissions = map[string][]string{
 		PermUpdateProfile,
 		PermManagePayslips,
 		PermViewOwnPayslips,
+		PermManageUsers,
+		PermImpersonateUsers,
+		PermManageRoles,
+		PermManageAllDepartments,
+		PermManageGrants,
+		PermViewCompensation,
+		PermViewPersonalData,
+		PermViewOrgChart,
 	},
 	RoleManager: {
 		PermManageEmployees,
@@ -56,6 +116,8 @@ var rolePermissions = map[string][]string{
 		PermUpdateProfile,
 		PermManagePayslips,
 		PermViewOwnPayslips,
+		PermViewPersonalData,
+		PermViewOrgChart,
 	},
 	RoleEmployee: {
 		PermRequestLeave,
@@ -65,16 +127,104 @@ var rolePermissions = map[string][]string{
 		PermViewProfile,
 		PermUpdateProfile,
 		PermViewOwnPayslips,
+		PermViewOrgChart,
 	},
 }
 
+// RoleStore resolves roles managed at runtime. ok is false when the store
+// cannot answer, in which case the built-in defaults apply.
+type RoleStore interface {
+	RolePermissions(role string) (permissions []string, ok bool)
+}
+
+var (
+	roleStoreMu sync.RWMutex
+	roleStore   RoleStore
+)
+
+// SetRoleStore makes PermissionsForRole consult store before the defaults.
+func SetRoleStore(store RoleStore) {
+	roleStoreMu.Lock()
+	roleStore = store
+	roleStoreMu.Unlock()
+}
+
+func currentRoleStore() RoleStore {
+	roleStoreMu.RLock()
+	defer roleStoreMu.RUnlock()
+	return roleStore
+}
+
 func PermissionsForRole(role string) []string {
+	store := currentRoleStore()
+
 	permissions := rolePermissions[role]
+	if store != nil {
+		if stored, ok := store.RolePermissions(role); ok {
+			permissions = stored
+		}
+	}
+
 	out := make([]string, len(permissions))
 	copy(out, permissions)
 	return out
 }
 
+// RoleExists reports whether role is defined in the role store or is one of
+// the system roles.
+func RoleExists(role string) bool {
+	if store := currentRoleStore(); store != nil {
+		if _, ok := store.RolePermissions(role); ok {
+			return true
+		}
+	}
+	return IsSystemRole(role)
+}
+
+// DefaultPermissionsForRole returns the built-in permissions of a system
+// role, ignoring the role store. They seed the roles table.
+func DefaultPermissionsForRole(role string) []string {
+	permissions := rolePermissions[role]
+	out := make([]string, len(permissions))
+	copy(out, permissions)
+	return out
+}
+
+// SystemRoles are the roles the code refers to by name. They always exist
+// and cannot be deleted.
+func SystemRoles() []string {
+	return []string{RoleAdmin, RoleManager, RoleEmployee, RoleServiceAccount}
+}
+
+// IsSystemRole reports whether role is one of SystemRoles.
+func IsSystemRole(role string) bool {
+	return HasRole(role, SystemRoles()...)
+}
+
+// Permissions returns every known permission name.
+func Permissions() []string {
+	out := make([]string, 0, len(permissionCatalog))
+	for _, entry := range permissionCatalog {
+		out = append(out, entry.Name)
+	}
+	return out
+}
+
+// PermissionDescription returns the human-readable description of permission.
+func PermissionDescription(permission string) string {
+	for _, entry := range permissionCatalog {
+		if entry.Name == permission {
+			return entry.Description
+		}
+	}
+	return ""
+}
+
+// IsPermission reports whether permission is one any role can be granted.
+func IsPermission(permission string) bool {
+	return HasPermission(Permissions(), permission)
+}
+
 func HasRole(role string, allowed ...string) bool {
 	for _, candidate := range allowed {
 		if role == candidate {
@@ -92,3 +242,34 @@ func HasPermission(permissions []string, required string) bool {
 	}
 	return false
 }
+
+// ungrantablePermissions control who holds which permission, so they can
+// only come from a role and never from a temporary grant or delegation.
+var ungrantablePermissions = []string{
+	PermManageRoles,
+	PermManageGrants,
+	PermImpersonateUsers,
+}
+
+// IsGrantable reports whether permission may be granted temporarily.
+func IsGrantable(permission string) bool {
+	return IsPermission(permission) && !HasPermission(ungrantablePermissions, permission)
+}
+
+// mfaSensitivePermissions guard payroll and audit data; users holding any of
+// them, through their role or a temporary grant, can be required to use a
+// second factor.
+var mfaSensitivePermissions = []string{
+	PermManagePayslips,
+	PermViewAuditLogs,
+}
+
+// IsMFASensitive reports whether permissions include an MFA-sensitive one.
+func IsMFASensitive(permissions []string) bool {
+	for _, sensitive := range mfaSensitivePermissions {
+		if HasPermission(permissions, sensitive) {
+			return true
+		}
+	}
+	return false
+}
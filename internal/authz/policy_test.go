@@ -0,0 +1,37 @@
+package authz
+
+import "testing"
+
+type stubRoleStore map[string][]string
+
+func (s stubRoleStore) RolePermissions(role string) ([]string, bool) {
+	if s == nil {
+		return nil, false
+	}
+	permissions, ok := s[role]
+	return permissions, ok
+}
+
+func TestPermissionsForRoleConsultsStore(t *testing.T) {
+	t.Cleanup(func() { SetRoleStore(nil) })
+
+	SetRoleStore(stubRoleStore{
+		"payroll_officer": {PermManagePayslips, PermViewOwnPayslips},
+		RoleEmployee:      {PermViewProfile},
+	})
+	if !HasPermission(PermissionsForRole("payroll_officer"), PermManagePayslips) || !RoleExists("payroll_officer") {
+		t.Fatal("expected custom role to resolve from the store")
+	}
+	if HasPermission(PermissionsForRole(RoleEmployee), PermRequestLeave) {
+		t.Fatal("expected the stored definition to override the default")
+	}
+	if RoleExists("hr_auditor") || len(PermissionsForRole("hr_auditor")) != 0 {
+		t.Fatal("expected an unknown role to grant nothing")
+	}
+
+	// A store that cannot answer falls back to the built-in roles.
+	SetRoleStore(stubRoleStore(nil))
+	if !HasPermission(PermissionsForRole(RoleEmployee), PermRequestLeave) || !RoleExists(RoleManager) {
+		t.Fatal("expected defaults when the store is unavailable")
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+)
+
+// RoutePermission describes what a registered route requires.
+type RoutePermission struct {
+	Method           string   `json:"method"`
+	Path             string   `json:"path"`
+	Public           bool     `json:"public"`
+	Permissions      []string `json:"permissions"`
+	DepartmentScoped bool     `json:"department_scoped"`
+}
+
+// AccessHandler lets clients discover what the caller may do and what each
+// route requires, instead of hardcoding it.
+type AccessHandler struct {
+	routes []RoutePermission
+}
+
+func NewAccessHandler() *AccessHandler {
+	return &AccessHandler{}
+}
+
+// SetRoutes installs the route catalog once the route table is built.
+func (h *AccessHandler) SetRoutes(routes []RoutePermission) {
+	h.routes = routes
+}
+
+// GET /me/permissions
+func (h *AccessHandler) MyPermissions(c *gin.Context) {
+	role := c.GetString("role")
+	permissions := append([]string{}, requestPermissions(c)...)
+	sort.Strings(permissions)
+
+	rolePermissions := authz.PermissionsForRole(role)
+	granted := make([]string, 0)
+	for _, permission := range permissions {
+		if !authz.HasPermission(rolePermissions, permission) {
+			granted = append(granted, permission)
+		}
+	}
+
+	scope := requestScope(c)
+	departmentIDs := append([]uuid.UUID{}, scope.DepartmentIDs...)
+	reportIDs := append([]uuid.UUID{}, scope.ReportIDs...)
+	onBehalfOf := append([]uuid.UUID{}, scope.OnBehalfOf...)
+
+	response := gin.H{
+		"user_id":             c.GetString("user_id"),
+		"role":                role,
+		"permissions":         permissions,
+		"granted_permissions": granted,
+		"scope": gin.H{
+			"all_departments": scope.Global,
+			"department_ids":  departmentIDs,
+			"report_ids":      reportIDs,
+			"on_behalf_of":    onBehalfOf,
+		},
+	}
+	if impersonatorID := c.GetString("impersonator_id"); impersonatorID != "" {
+		response["impersonator_id"] = impersonatorID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /admin/route-permissions
+func (h *AccessHandler) RoutePermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.routes)
+}
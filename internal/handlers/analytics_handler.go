@@ -106,11 +106,11 @@ func (h *AnalyticsHandler) Absentees(c *gin.Context) {
 
 	date = date.UTC()
 
-	names, err := h.service.Absentees(date)
+	absentees, err := h.service.Absentees(date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"absentees": names})
+	c.JSON(http.StatusOK, gin.H{"absentees": newAbsenteeResponses(absentees, requestFieldAccess(c))})
 }
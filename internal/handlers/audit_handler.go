@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jung-kurt/gofpdf"
 
+	"go-backend/internal/models"
 	"go-backend/internal/services"
 )
 
@@ -53,7 +54,12 @@ func (h *AuditHandler) List(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, logs)
+	access := requestFieldAccess(c)
+	response := make([]auditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		response = append(response, newAuditLogResponse(log, access))
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *AuditHandler) ExportCSV(c *gin.Context) {
@@ -66,6 +72,7 @@ func (h *AuditHandler) ExportCSV(c *gin.Context) {
 		return
 	}
 
+	access := requestFieldAccess(c)
 	c.Header("Content-Disposition", "attachment; filename=audit_logs.csv")
 	c.Header("Content-Type", "text/csv")
 
@@ -74,10 +81,7 @@ func (h *AuditHandler) ExportCSV(c *gin.Context) {
 
 	_ = w.Write([]string{"CreatedAt", "UserEmail", "Action", "Entity", "EntityID"})
 	for _, log := range logs {
-		userEmail := ""
-		if log.User != nil {
-			userEmail = log.User.Email
-		}
+		userEmail := auditActorEmail(log, access)
 		entityID := ""
 		if log.EntityID != nil {
 			entityID = log.EntityID.String()
@@ -102,6 +106,7 @@ func (h *AuditHandler) ExportPDF(c *gin.Context) {
 		return
 	}
 
+	access := requestFieldAccess(c)
 	c.Header("Content-Disposition", "attachment; filename=audit_logs.pdf")
 	c.Header("Content-Type", "application/pdf")
 
@@ -120,10 +125,7 @@ func (h *AuditHandler) ExportPDF(c *gin.Context) {
 
 	pdf.SetFont("Arial", "", 8)
 	for _, log := range logs {
-		userEmail := ""
-		if log.User != nil {
-			userEmail = log.User.Email
-		}
+		userEmail := auditActorEmail(log, access)
 		entityID := ""
 		if log.EntityID != nil {
 			entityID = log.EntityID.String()
@@ -139,6 +141,16 @@ func (h *AuditHandler) ExportPDF(c *gin.Context) {
 	_ = pdf.Output(c.Writer)
 }
 
+func auditActorEmail(log models.AuditLog, access services.FieldAccess) string {
+	if log.User == nil {
+		return ""
+	}
+	if !access.PersonalDataOf(log.User.ID) {
+		return services.RedactedValue
+	}
+	return log.User.Email
+}
+
 func parseAuditFilters(c *gin.Context) (*time.Time, *time.Time, *string, string) {
 	fromStr := c.Query("from")
 	toStr := c.Query("to")
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"go-backend/internal/services"
+	"go-backend/pkg/utils"
 )
 
 type AuthHandler struct {
@@ -27,16 +33,95 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := h.authService.Login(req.Email, req.Password)
+	result, err := h.authService.Login(req.Email, req.Password, c.Request.UserAgent(), c.ClientIP())
+	if respondThrottled(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrAccountDisabled) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "account disabled"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"access_token":  access,
-		"refresh_token": refresh,
-	})
+	c.JSON(http.StatusOK, result)
+}
+
+// POST /auth/mfa/enroll
+func (h *AuthHandler) MFAEnroll(c *gin.Context) {
+	var req struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	enrollment, err := h.authService.BeginMFAEnrollment(req.MFAToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// POST /auth/mfa/verify
+func (h *AuthHandler) MFAVerify(c *gin.Context) {
+	var req struct {
+		MFAToken     string `json:"mfa_token" binding:"required"`
+		Code         string `json:"code" binding:"required_without=RecoveryCode"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.VerifyMFA(req.MFAToken, req.Code, req.RecoveryCode, c.Request.UserAgent(), c.ClientIP())
+	if respondThrottled(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// POST /auth/password/change
+// Completes a login that was stopped because the password expired.
+func (h *AuthHandler) ChangeExpiredPassword(c *gin.Context) {
+	var req struct {
+		PasswordChangeToken string `json:"password_change_token" binding:"required"`
+		NewPassword         string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.ChangeExpiredPassword(req.PasswordChangeToken, req.NewPassword, c.Request.UserAgent(), c.ClientIP())
+	var policyErr *utils.PasswordPolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "violations": policyErr.Violations})
+		return
+	case errors.Is(err, services.ErrPasswordReused):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
 }
 
 func (h *AuthHandler) Refresh(c *gin.Context) {
@@ -49,7 +134,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := h.authService.Refresh(req.RefreshToken)
+	access, refresh, err := h.authService.Refresh(req.RefreshToken, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
 		return
@@ -60,3 +145,55 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		"refresh_token": refresh,
 	})
 }
+
+// POST /auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req struct {
+		Everywhere bool `json:"everywhere"`
+	}
+
+	// The body is optional; an empty request logs out the current session.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user"})
+		return
+	}
+	expiresAt, _ := c.Get("token_expires_at")
+	tokenExpiry, _ := expiresAt.(time.Time)
+
+	// An impersonation token may only end itself, never the user's sessions.
+	if c.GetString("impersonator_id") != "" {
+		req.Everywhere = false
+	}
+
+	if err := h.authService.Logout(userID, c.GetString("token_id"), c.GetString("session_id"), tokenExpiry, req.Everywhere); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "logout failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// respondThrottled writes a 429 with Retry-After when err is a login
+// throttling error and reports whether it did.
+func respondThrottled(c *gin.Context, err error) bool {
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":               throttled.Error(),
+		"retry_after_seconds": retryAfter,
+	})
+	return true
+}
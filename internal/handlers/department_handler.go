@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
 	"go-backend/internal/services"
 )
 
@@ -31,7 +35,7 @@ func (h *DepartmentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dept)
+	c.JSON(http.StatusCreated, newDepartmentResponse(*dept))
 }
 
 func (h *DepartmentHandler) List(c *gin.Context) {
@@ -41,5 +45,75 @@ func (h *DepartmentHandler) List(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, depts)
+	response := make([]departmentResponse, 0, len(depts))
+	for _, dept := range depts {
+		response = append(response, newDepartmentResponse(dept))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /departments/:id/managers
+func (h *DepartmentHandler) ListManagers(c *gin.Context) {
+	departmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department id"})
+		return
+	}
+
+	managers, err := h.service.ListManagers(departmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(managers))
+	for _, manager := range managers {
+		response = append(response, gin.H{
+			"user_id":     manager.UserID,
+			"email":       manager.User.Email,
+			"role":        manager.User.Role,
+			"assigned_by": manager.AssignedBy,
+			"assigned_at": manager.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// PUT /departments/:id/managers
+func (h *DepartmentHandler) SetManagers(c *gin.Context) {
+	departmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department id"})
+		return
+	}
+
+	var req struct {
+		UserIDs []uuid.UUID `json:"user_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user"})
+		return
+	}
+
+	err = h.service.SetManagers(departmentID, req.UserIDs, adminID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "department managers updated"})
 }
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"go-backend/internal/services"
 )
@@ -23,8 +27,7 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 		FirstName    string `json:"first_name" binding:"required"`
 		LastName     string `json:"last_name" binding:"required"`
 		Email        string `json:"email" binding:"required,email"`
-		Password     string `json:"password" binding:"required,min=8"`
-		Role         string `json:"role" binding:"required,oneof=employee manager"`
+		Role         string `json:"role" binding:"required"`
 		DepartmentID string `json:"department_id" binding:"required,uuid"`
 	}
 
@@ -41,12 +44,17 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 		req.LastName,
 		req.Email,
 		req.Role,
-		req.Password,
 		deptID,
 		adminID,
+		requestScope(c),
 	)
 
-	if err != nil {
+	inviteSent := true
+	if errors.Is(err, services.ErrInviteNotSent) {
+		inviteSent = false
+	} else if respondOutOfScope(c, err) {
+		return
+	} else if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -57,21 +65,76 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 		"email":       req.Email,
 		"first_name":  employee.FirstName,
 		"last_name":   employee.LastName,
+		"status":      employee.Status,
+		"invite_sent": inviteSent,
 	})
 }
 
-// List Employee
+// GET /employees?page=&page_size=&department_id=&status=&role=&hired_from=&hired_to=&q=&sort=
 func (h *EmployeeHandler) ListEmployees(c *gin.Context) {
-	employees, err := h.service.ListEmployees()
+	query := services.EmployeeQuery{
+		Status: c.Query("status"),
+		Role:   c.Query("role"),
+		Search: c.Query("q"),
+		Sort:   c.Query("sort"),
+	}
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+
+	if v := c.Query("department_id"); v != "" {
+		departmentID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department_id"})
+			return
+		}
+		query.DepartmentID = &departmentID
+	}
+	var ok bool
+	if query.HiredFrom, ok = dateQuery(c, "hired_from"); !ok {
+		return
+	}
+	if query.HiredTo, ok = dateQuery(c, "hired_to"); !ok {
+		return
+	}
+
+	page, err := h.service.ListEmployees(query, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrInvalidEmployeeQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, employees)
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":     newEmployeeResponses(page.Employees, requestFieldAccess(c)),
+		"total":     page.Total,
+		"page":      page.Page,
+		"page_size": page.PageSize,
+	})
+}
+
+// dateQuery parses an optional YYYY-MM-DD query parameter, answering 400
+// itself when it is malformed.
+func dateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return nil, false
+	}
+	return &date, true
 }
 
 func (h *EmployeeHandler) CountEmployees(c *gin.Context) {
-	count, err := h.service.CountEmployees()
+	count, err := h.service.CountEmployees(requestScope(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -87,7 +150,7 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 		FirstName    string `json:"first_name" binding:"required"`
 		LastName     string `json:"last_name" binding:"required"`
 		DepartmentID string `json:"department_id" binding:"required,uuid"`
-		Role         string `json:"role" binding:"required,oneof=employee manager"`
+		Role         string `json:"role" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -98,7 +161,10 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 	deptID, _ := uuid.Parse(req.DepartmentID)
 	adminID, _ := uuid.Parse(c.GetString("user_id"))
 
-	employee, err := h.service.UpdateEmployee(employeeID, req.FirstName, req.LastName, deptID, req.Role, adminID)
+	employee, err := h.service.UpdateEmployee(employeeID, req.FirstName, req.LastName, deptID, req.Role, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -112,12 +178,64 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 	})
 }
 
+// PUT /employees/:id/manager
+// Body: {"manager_id": "<employee id>"}, or null to remove the manager.
+func (h *EmployeeHandler) SetManager(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	var req struct {
+		ManagerID *string `json:"manager_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var managerID *uuid.UUID
+	if req.ManagerID != nil {
+		id, err := uuid.Parse(*req.ManagerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manager_id"})
+			return
+		}
+		managerID = &id
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	employee, err := h.service.SetManager(employeeID, managerID, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if errors.Is(err, services.ErrReportingCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(*employee, requestFieldAccess(c)))
+}
+
 // DELETE /employees/:id
 func (h *EmployeeHandler) DeactivateEmployee(c *gin.Context) {
 	employeeID, _ := uuid.Parse(c.Param("id"))
 	adminID, _ := uuid.Parse(c.GetString("user_id"))
 
-	if err := h.service.DeactivateEmployee(employeeID, adminID); err != nil {
+	err := h.service.DeactivateEmployee(employeeID, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
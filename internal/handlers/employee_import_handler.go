@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/services"
+	"go-backend/pkg/utils"
+)
+
+type EmployeeImportHandler struct {
+	service  *services.EmployeeImportService
+	maxBytes int64
+}
+
+func NewEmployeeImportHandler(service *services.EmployeeImportService) *EmployeeImportHandler {
+	return &EmployeeImportHandler{
+		service:  service,
+		maxBytes: int64(utils.GetEnvInt("IMPORT_MAX_BYTES", 5<<20)),
+	}
+}
+
+// POST /employees/import?commit=true
+// Multipart form: file (.csv or .xlsx) and an optional mapping, a JSON
+// object from field name to column header. Without commit it is a dry run.
+func (h *EmployeeImportHandler) Import(c *gin.Context) {
+	adminID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user"})
+		return
+	}
+
+	commit, err := strconv.ParseBool(c.DefaultQuery("commit", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid commit"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+(1<<20))
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > h.maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+
+	opts := services.ImportOptions{Commit: commit}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of field to column"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+		return
+	}
+
+	report, err := h.service.Import(header.Filename, data, opts, adminID, requestScope(c))
+	switch {
+	case errors.Is(err, services.ErrImportHasErrors):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "report": report})
+	case errors.Is(err, services.ErrInvalidImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case report.Committed:
+		c.JSON(http.StatusCreated, report)
+	default:
+		c.JSON(http.StatusOK, report)
+	}
+}
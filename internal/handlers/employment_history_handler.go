@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/services"
+)
+
+type EmploymentHistoryHandler struct {
+	service services.EmploymentHistoryService
+}
+
+func NewEmploymentHistoryHandler(service services.EmploymentHistoryService) *EmploymentHistoryHandler {
+	return &EmploymentHistoryHandler{service: service}
+}
+
+// GET /employees/:id/history
+func (h *EmploymentHistoryHandler) History(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	events, err := h.service.History(employeeID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"employee_id": employeeID, "events": newEmploymentEventResponses(events)})
+}
+
+// POST /employees/:id/events
+func (h *EmploymentHistoryHandler) Record(c *gin.Context) {
+	h.record(c, "")
+}
+
+// POST /employees/:id/rehire
+//
+// Brings back a terminated or deactivated employee under their existing
+// user and history, optionally into a new department, role or job title.
+func (h *EmploymentHistoryHandler) Rehire(c *gin.Context) {
+	h.record(c, models.EmploymentEventRehire)
+}
+
+// record stores the change in the request body, of eventType or of the
+// type the body names when eventType is empty.
+func (h *EmploymentHistoryHandler) record(c *gin.Context, eventType string) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	var req struct {
+		Type          string  `json:"type"`
+		EffectiveDate string  `json:"effective_date"`
+		DepartmentID  *string `json:"department_id"`
+		Role          string  `json:"role"`
+		Status        string  `json:"status"`
+		JobTitle      string  `json:"job_title"`
+		Reason        string  `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if eventType == "" {
+		if req.Type == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "type is required"})
+			return
+		}
+		eventType = req.Type
+	}
+
+	change := services.EmploymentChange{
+		Type:     eventType,
+		Role:     req.Role,
+		Status:   req.Status,
+		JobTitle: req.JobTitle,
+		Reason:   req.Reason,
+	}
+	if req.EffectiveDate != "" {
+		change.EffectiveDate, err = time.Parse("2006-01-02", req.EffectiveDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effective_date must be YYYY-MM-DD"})
+			return
+		}
+	}
+	if req.DepartmentID != nil {
+		id, err := uuid.Parse(*req.DepartmentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department_id"})
+			return
+		}
+		change.DepartmentID = &id
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	event, err := h.service.Record(employeeID, change, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newEmploymentEventResponse(*event))
+}
+
+// DELETE /employees/:id/events/:event_id
+func (h *EmploymentHistoryHandler) Cancel(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+	eventID, err := uuid.Parse(c.Param("event_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	err = h.service.Cancel(employeeID, eventID, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event cancelled"})
+}
+
+// GET /employees/:id/state?as_of=YYYY-MM-DD
+func (h *EmploymentHistoryHandler) StateAsOf(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+	asOf, ok := parseAsOf(c, "as_of")
+	if !ok {
+		return
+	}
+
+	state, err := h.service.StateAsOf(employeeID, asOf, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// GET /employees/as-of?date=YYYY-MM-DD&department_id=<id>&page=&page_size=
+func (h *EmploymentHistoryHandler) DirectoryAsOf(c *gin.Context) {
+	asOf, ok := parseAsOf(c, "date")
+	if !ok {
+		return
+	}
+	query := services.DirectoryQuery{Date: asOf}
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+	if v := c.Query("department_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department_id"})
+			return
+		}
+		query.DepartmentID = &id
+	}
+
+	page, err := h.service.DirectoryAsOf(query, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"as_of":     asOf.Format("2006-01-02"),
+		"employees": page.Employees,
+		"total":     page.Total,
+		"page":      page.Page,
+		"page_size": page.PageSize,
+	})
+}
+
+// parseAsOf reads a YYYY-MM-DD query parameter, defaulting to today, and
+// writes a 400 when it is malformed.
+func parseAsOf(c *gin.Context, name string) (time.Time, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return time.Now().UTC(), true
+	}
+	asOf, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be YYYY-MM-DD"})
+		return time.Time{}, false
+	}
+	return asOf, true
+}
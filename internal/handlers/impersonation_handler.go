@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/services"
+)
+
+type ImpersonationHandler struct {
+	service services.ImpersonationService
+}
+
+func NewImpersonationHandler(service services.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{service: service}
+}
+
+// POST /admin/users/:id/impersonate
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Impersonation does not nest.
+	if c.GetString("impersonator_id") != "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "already impersonating"})
+		return
+	}
+
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	token, err := h.service.Start(actorID, targetID, req.Reason, c.ClientIP())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// DELETE /impersonation
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+	actorID, err := uuid.Parse(c.GetString("impersonator_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "not impersonating"})
+		return
+	}
+	targetID, _ := uuid.Parse(c.GetString("user_id"))
+	expiresAt, _ := c.Get("token_expires_at")
+	tokenExpiry, _ := expiresAt.(time.Time)
+
+	if err := h.service.Stop(actorID, targetID, c.GetString("token_id"), tokenExpiry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "impersonation ended"})
+}
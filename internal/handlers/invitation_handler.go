@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/services"
+	"go-backend/pkg/utils"
+)
+
+type InvitationHandler struct {
+	service services.InvitationService
+}
+
+func NewInvitationHandler(service services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: service}
+}
+
+// POST /auth/accept-invite
+func (h *InvitationHandler) Accept(c *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.Accept(req.Token, req.Password)
+	var policyErr *utils.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "violations": policyErr.Violations})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation accepted, you can now log in"})
+}
+
+// GET /employees/invites
+func (h *InvitationHandler) List(c *gin.Context) {
+	invites, err := h.service.List(requestScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+// GET /employees/:id/invite
+func (h *InvitationHandler) Status(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	status, err := h.service.Status(employeeID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no invitation found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /employees/:id/invite
+func (h *InvitationHandler) Resend(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	err = h.service.Resend(employeeID, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation sent"})
+}
+
+// DELETE /employees/:id/invite
+func (h *InvitationHandler) Revoke(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	err = h.service.Revoke(employeeID, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation revoked"})
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend/internal/services"
+)
+
+type JWKSHandler struct {
+	service services.TokenKeyService
+}
+
+func NewJWKSHandler(service services.TokenKeyService) *JWKSHandler {
+	return &JWKSHandler{service: service}
+}
+
+// GET /.well-known/jwks.json
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.service.JWKS())
+}
@@ -58,7 +58,7 @@ func (h *LeaveHandler) Request(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, leave)
+	c.JSON(http.StatusCreated, newLeaveResponse(*leave, requestFieldAccess(c)))
 }
 
 func (h *LeaveHandler) Mine(c *gin.Context) {
@@ -75,20 +75,41 @@ func (h *LeaveHandler) Mine(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, leaves)
+	c.JSON(http.StatusOK, newLeaveResponses(leaves, requestFieldAccess(c)))
 }
 
 func (h *LeaveHandler) List(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	status := c.Query("status")
 
-	leaves, err := h.service.ListAll(status, limit)
+	leaves, err := h.service.ListAll(status, limit, requestScope(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, leaves)
+	c.JSON(http.StatusOK, newLeaveResponses(leaves, requestFieldAccess(c)))
+}
+
+// GET /leaves/assigned?status=&limit=
+// Requests routed to the caller through reporting lines.
+func (h *LeaveHandler) Assigned(c *gin.Context) {
+	reviewerID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid reviewer"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	status := c.DefaultQuery("status", "pending")
+
+	leaves, err := h.service.ListAssigned(reviewerID, status, limit, requestScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newLeaveResponses(leaves, requestFieldAccess(c)))
 }
 
 func (h *LeaveHandler) Review(c *gin.Context) {
@@ -111,13 +132,16 @@ func (h *LeaveHandler) Review(c *gin.Context) {
 		return
 	}
 
-	leave, err := h.service.ReviewLeave(leaveID, reviewerID, req.Status)
+	leave, err := h.service.ReviewLeave(leaveID, reviewerID, req.Status, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, leave)
+	c.JSON(http.StatusOK, newLeaveResponse(*leave, requestFieldAccess(c)))
 }
 
 func (h *LeaveHandler) CancelMine(c *gin.Context) {
@@ -137,5 +161,5 @@ func (h *LeaveHandler) CancelMine(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, leave)
+	c.JSON(http.StatusOK, newLeaveResponse(*leave, requestFieldAccess(c)))
 }
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/services"
+)
+
+// MFAHandler serves self-service MFA management under /profile/mfa.
+type MFAHandler struct {
+	service services.MFAService
+}
+
+func NewMFAHandler(service services.MFAService) *MFAHandler {
+	return &MFAHandler{service: service}
+}
+
+// GET /profile/mfa
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	enabled, err := h.service.IsEnabled(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  enabled,
+		"required": h.service.IsRequired(requestPermissions(c)),
+	})
+}
+
+// POST /profile/mfa/enroll
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	enrollment, err := h.service.BeginEnrollment(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// POST /profile/mfa/confirm
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.service.ConfirmEnrollment(userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// POST /profile/mfa/recovery-codes
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// DELETE /profile/mfa
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Disable(userID, requestPermissions(c), req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "mfa disabled"})
+}
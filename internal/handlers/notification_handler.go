@@ -24,8 +24,7 @@ func (h *NotificationHandler) List(c *gin.Context) {
 		return
 	}
 
-	role := c.GetString("role")
-	payload, err := h.service.GetNotifications(userID, role)
+	payload, err := h.service.GetNotifications(userID, requestPermissions(c), requestScope(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
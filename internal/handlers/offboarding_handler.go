@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/services"
+)
+
+type OffboardingHandler struct {
+	service services.OffboardingService
+}
+
+func NewOffboardingHandler(service services.OffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{service: service}
+}
+
+// POST /employees/:id/termination
+// Body: {"last_working_day": "YYYY-MM-DD", "reason_code", "notes"}. The
+// employee loses access at 00:00 UTC on the day after last_working_day, or
+// at once when that has passed. Pending changes dated on or after it are
+// cancelled.
+func (h *OffboardingHandler) Terminate(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	var req struct {
+		LastWorkingDay string `json:"last_working_day" binding:"required"`
+		ReasonCode     string `json:"reason_code" binding:"required"`
+		Notes          string `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lastWorkingDay, err := time.Parse("2006-01-02", req.LastWorkingDay)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "last_working_day must be YYYY-MM-DD"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	offboarding, err := h.service.Terminate(employeeID, services.Termination{
+		LastWorkingDay: lastWorkingDay,
+		ReasonCode:     req.ReasonCode,
+		Notes:          req.Notes,
+	}, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newOffboardingResponse(*offboarding))
+}
+
+// GET /employees/:id/termination
+func (h *OffboardingHandler) Get(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	offboarding, err := h.service.Get(employeeID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no termination found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newOffboardingResponse(*offboarding))
+}
+
+// DELETE /employees/:id/termination
+// Only possible before the termination takes effect at 00:00 UTC.
+func (h *OffboardingHandler) Cancel(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	err = h.service.CancelTermination(employeeID, adminID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Termination cancelled"})
+}
+
+// GET /offboarding/tasks?include_completed=true
+func (h *OffboardingHandler) ListTasks(c *gin.Context) {
+	includeCompleted, err := strconv.ParseBool(c.DefaultQuery("include_completed", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include_completed"})
+		return
+	}
+
+	tasks, err := h.service.ListTasks(requestScope(c), includeCompleted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": newOffboardingTaskResponses(tasks)})
+}
+
+// POST /offboarding/tasks/:id/complete
+func (h *OffboardingHandler) CompleteTask(c *gin.Context) {
+	taskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return
+	}
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	task, err := h.service.CompleteTask(taskID, userID, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newOffboardingTaskResponse(*task))
+}
+
+// GET /offboarding/templates
+func (h *OffboardingHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.service.ListTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": newOffboardingTemplateResponses(templates)})
+}
+
+// POST /offboarding/templates
+func (h *OffboardingHandler) CreateTemplate(c *gin.Context) {
+	var req struct {
+		DepartmentID string `json:"department_id" binding:"required"`
+		Title        string `json:"title" binding:"required"`
+		Position     int    `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	departmentID, err := uuid.Parse(req.DepartmentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department_id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	template, err := h.service.CreateTemplate(departmentID, req.Title, req.Position, adminID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newOffboardingTemplateResponse(*template))
+}
+
+// DELETE /offboarding/templates/:id
+func (h *OffboardingHandler) DeleteTemplate(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	err = h.service.DeleteTemplate(templateID, adminID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/services"
+)
+
+type OrgChartHandler struct {
+	service *services.OrgChartService
+}
+
+func NewOrgChartHandler(service *services.OrgChartService) *OrgChartHandler {
+	return &OrgChartHandler{service: service}
+}
+
+// GET /org-chart?root=<employee id>&format=json|pdf
+func (h *OrgChartHandler) Get(c *gin.Context) {
+	var rootID *uuid.UUID
+	if v := c.Query("root"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid root"})
+			return
+		}
+		rootID = &id
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		roots, err := h.service.Tree(rootID, requestFieldAccess(c))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"roots": roots})
+	case "pdf":
+		var buf bytes.Buffer
+		err := h.service.ExportPDF(&buf, rootID, requestFieldAccess(c))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename=org-chart.pdf")
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or pdf"})
+	}
+}
+
+// GET /employees/:id/reports?indirect=true
+func (h *OrgChartHandler) Reports(c *gin.Context) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+	indirect, err := strconv.ParseBool(c.DefaultQuery("indirect", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid indirect"})
+		return
+	}
+
+	reports, err := h.service.Reports(employeeID, indirect, requestFieldAccess(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"employee_id": employeeID, "reports": reports})
+}
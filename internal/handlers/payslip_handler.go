@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/jung-kurt/gofpdf"
 
 	"go-backend/internal/authz"
+	"go-backend/internal/models"
 	"go-backend/internal/services"
 )
 
@@ -43,12 +45,15 @@ func (h *PayslipHandler) Generate(c *gin.Context) {
 	}
 
 	employeeID, _ := uuid.Parse(req.EmployeeID)
-	payslip, err := h.service.Generate(employeeID, req.Month, req.Year, req.BasicPay, req.Allowances, req.Deductions, req.Currency, generatedBy)
+	payslip, err := h.service.Generate(employeeID, req.Month, req.Year, req.BasicPay, req.Allowances, req.Deductions, req.Currency, generatedBy, requestScope(c))
+	if respondOutOfScope(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, payslip)
+	c.JSON(http.StatusOK, newPayslipResponse(*payslip, requestFieldAccess(c)))
 }
 
 func (h *PayslipHandler) Mine(c *gin.Context) {
@@ -63,7 +68,7 @@ func (h *PayslipHandler) Mine(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, payslips)
+	c.JSON(http.StatusOK, newPayslipResponses(payslips, requestFieldAccess(c)))
 }
 
 func (h *PayslipHandler) List(c *gin.Context) {
@@ -86,12 +91,12 @@ func (h *PayslipHandler) List(c *gin.Context) {
 			year = &parsed
 		}
 	}
-	payslips, err := h.service.ListAll(limit, employeeID, month, year)
+	payslips, err := h.service.ListAll(limit, employeeID, month, year, requestScope(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, payslips)
+	c.JSON(http.StatusOK, newPayslipResponses(payslips, requestFieldAccess(c)))
 }
 
 func (h *PayslipHandler) GetByID(c *gin.Context) {
@@ -101,25 +106,43 @@ func (h *PayslipHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	payslip, err := h.service.GetByID(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "payslip not found"})
+	payslip, ok := h.findVisible(c, id)
+	if !ok {
 		return
 	}
 
-	role := c.GetString("role")
+	c.JSON(http.StatusOK, newPayslipResponse(*payslip, requestFieldAccess(c)))
+}
+
+// findVisible loads a payslip the caller may read: their own, or any within
+// their department scope when they manage payslips. It writes the error
+// response itself and reports whether the caller may continue.
+func (h *PayslipHandler) findVisible(c *gin.Context, id uuid.UUID) (*models.Payslip, bool) {
 	userID, _ := uuid.Parse(c.GetString("user_id"))
-	if !authz.HasPermission(authz.PermissionsForRole(role), authz.PermManagePayslips) && payslip.UserID != userID {
+	var scope *services.Scope
+	if authz.HasPermission(requestPermissions(c), authz.PermManagePayslips) {
+		resolved := requestScope(c)
+		scope = &resolved
+	}
+
+	payslip, err := h.service.GetByID(id, userID, scope)
+	if errors.Is(err, services.ErrOutOfScope) && scope == nil {
 		c.JSON(http.StatusForbidden, gin.H{
 			"error":                "forbidden",
 			"code":                 "FORBIDDEN",
 			"required_roles":       []string{},
 			"required_permissions": []string{authz.PermViewOwnPayslips},
 		})
-		return
+		return nil, false
 	}
-
-	c.JSON(http.StatusOK, payslip)
+	if respondOutOfScope(c, err) {
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payslip not found"})
+		return nil, false
+	}
+	return payslip, true
 }
 
 func (h *PayslipHandler) DownloadPDF(c *gin.Context) {
@@ -129,22 +152,8 @@ func (h *PayslipHandler) DownloadPDF(c *gin.Context) {
 		return
 	}
 
-	payslip, err := h.service.GetByID(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "payslip not found"})
-		return
-	}
-
-	role := c.GetString("role")
-	userID, _ := uuid.Parse(c.GetString("user_id"))
-	canManage := authz.HasPermission(authz.PermissionsForRole(role), authz.PermManagePayslips)
-	if !canManage && payslip.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":                "forbidden",
-			"code":                 "FORBIDDEN",
-			"required_roles":       []string{},
-			"required_permissions": []string{authz.PermViewOwnPayslips},
-		})
+	payslip, ok := h.findVisible(c, id)
+	if !ok {
 		return
 	}
 
@@ -184,6 +193,13 @@ func (h *PayslipHandler) DownloadPDF(c *gin.Context) {
 	pdf.Text(100, 66, "Payslip ID: "+id.String())
 
 	currency := payslip.Currency
+	showAmounts := requestFieldAccess(c).CompensationOf(payslip.UserID)
+	amount := func(value float64) string {
+		if !showAmounts {
+			return services.RedactedValue
+		}
+		return formatCurrency(value)
+	}
 	pdf.SetY(80)
 	pdf.SetFont("Arial", "B", 11)
 	pdf.SetFillColor(226, 232, 240)
@@ -192,17 +208,17 @@ func (h *PayslipHandler) DownloadPDF(c *gin.Context) {
 
 	pdf.SetFont("Arial", "", 11)
 	pdf.CellFormat(120, 8, "Basic Pay", "1", 0, "L", false, 0, "")
-	pdf.CellFormat(60, 8, formatCurrency(payslip.BasicPay), "1", 1, "R", false, 0, "")
+	pdf.CellFormat(60, 8, amount(payslip.BasicPay), "1", 1, "R", false, 0, "")
 	pdf.CellFormat(120, 8, "Allowances", "1", 0, "L", false, 0, "")
-	pdf.CellFormat(60, 8, formatCurrency(payslip.Allowances), "1", 1, "R", false, 0, "")
+	pdf.CellFormat(60, 8, amount(payslip.Allowances), "1", 1, "R", false, 0, "")
 	pdf.CellFormat(120, 8, "Deductions", "1", 0, "L", false, 0, "")
-	pdf.CellFormat(60, 8, formatCurrency(payslip.Deductions), "1", 1, "R", false, 0, "")
+	pdf.CellFormat(60, 8, amount(payslip.Deductions), "1", 1, "R", false, 0, "")
 
 	// Net pay emphasis
 	pdf.SetFont("Arial", "B", 12)
 	pdf.SetFillColor(220, 252, 231)
 	pdf.CellFormat(120, 10, "Net Pay", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(60, 10, formatCurrency(payslip.NetPay), "1", 1, "R", true, 0, "")
+	pdf.CellFormat(60, 10, amount(payslip.NetPay), "1", 1, "R", true, 0, "")
 
 	// Footer note
 	pdf.Ln(8)
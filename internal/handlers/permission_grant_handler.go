@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/services"
+)
+
+// PermissionGrantHandler serves temporary grants and delegations under
+// /admin/grants.
+type PermissionGrantHandler struct {
+	service services.PermissionGrantService
+}
+
+func NewPermissionGrantHandler(service services.PermissionGrantService) *PermissionGrantHandler {
+	return &PermissionGrantHandler{service: service}
+}
+
+func permissionGrantResponse(grant models.PermissionGrant) gin.H {
+	now := time.Now()
+	return gin.H{
+		"id":           grant.ID,
+		"user_id":      grant.GranteeID,
+		"permission":   grant.Permission,
+		"delegator_id": grant.DelegatorID,
+		"starts_at":    grant.StartsAt,
+		"ends_at":      grant.EndsAt,
+		"reason":       grant.Reason,
+		"granted_by":   grant.GrantedBy,
+		"created_at":   grant.CreatedAt,
+		"revoked_at":   grant.RevokedAt,
+		"revoked_by":   grant.RevokedBy,
+		"active":       grant.RevokedAt == nil && !grant.StartsAt.After(now) && grant.EndsAt.After(now),
+	}
+}
+
+// POST /admin/grants
+func (h *PermissionGrantHandler) Create(c *gin.Context) {
+	var req struct {
+		UserID      uuid.UUID  `json:"user_id" binding:"required"`
+		Permission  string     `json:"permission"`
+		DelegatorID *uuid.UUID `json:"delegator_id"`
+		StartsAt    time.Time  `json:"starts_at"`
+		EndsAt      time.Time  `json:"ends_at" binding:"required"`
+		Reason      string     `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	grant, err := h.service.Grant(services.GrantRequest{
+		GranteeID:   req.UserID,
+		Permission:  strings.TrimSpace(req.Permission),
+		DelegatorID: req.DelegatorID,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Reason:      req.Reason,
+	}, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, permissionGrantResponse(*grant))
+}
+
+// GET /admin/grants?user_id=&active=true
+func (h *PermissionGrantHandler) List(c *gin.Context) {
+	var granteeID *uuid.UUID
+	if v := c.Query("user_id"); v != "" {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		granteeID = &parsed
+	}
+
+	grants, err := h.service.List(granteeID, strings.EqualFold(c.Query("active"), "true"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(grants))
+	for _, grant := range grants {
+		response = append(response, permissionGrantResponse(grant))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /admin/grants/:id
+func (h *PermissionGrantHandler) Revoke(c *gin.Context) {
+	grantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid grant id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	err = h.service.Revoke(grantID, adminID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "grant not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "grant revoked"})
+}
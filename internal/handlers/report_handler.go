@@ -23,7 +23,7 @@ func (h *ReportHandler) ExportCSV(c *gin.Context) {
 	c.Header("Content-Disposition", "attachment; filename=attendance.csv")
 	c.Header("Content-Type", "text/csv")
 
-	h.reportService.ExportCSV(c.Writer, from, to) // <- fixed field name
+	h.reportService.ExportCSV(c.Writer, from, to, requestFieldAccess(c)) // <- fixed field name
 }
 
 func (h *ReportHandler) ExportPDF(c *gin.Context) {
@@ -33,5 +33,5 @@ func (h *ReportHandler) ExportPDF(c *gin.Context) {
 	c.Header("Content-Disposition", "attachment; filename=attendance.pdf")
 	c.Header("Content-Type", "application/pdf")
 
-	h.reportService.ExportPDF(c.Writer, from, to) // <- fixed field name
+	h.reportService.ExportPDF(c.Writer, from, to, requestFieldAccess(c)) // <- fixed field name
 }
@@ -0,0 +1,450 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/internal/services"
+)
+
+// Handlers map models to these response types instead of serializing GORM
+// models, so the wire format stays independent of the database schema.
+// Compensation and personal fields about other people are redacted per
+// services.FieldAccess: hidden fields are null and named in redacted_fields.
+
+type employeeResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Email          *string    `json:"email"`
+	Role           string     `json:"role"`
+	IsActive       bool       `json:"is_active"`
+	DepartmentID   *uuid.UUID `json:"department_id"`
+	DepartmentName string     `json:"department_name,omitempty"`
+	ManagerID      *uuid.UUID `json:"manager_id"`
+	JobTitle       string     `json:"job_title"`
+	Status         string     `json:"status"`
+	HireDate       *time.Time `json:"hire_date"`
+	CreatedAt      time.Time  `json:"created_at"`
+	RedactedFields []string   `json:"redacted_fields,omitempty"`
+}
+
+func newEmployeeResponse(employee models.Employee, access services.FieldAccess) employeeResponse {
+	response := employeeResponse{
+		ID:           employee.ID,
+		UserID:       employee.UserID,
+		FirstName:    employee.FirstName,
+		LastName:     employee.LastName,
+		Role:         employee.User.Role,
+		IsActive:     employee.User.IsActive,
+		DepartmentID: employee.DepartmentID,
+		ManagerID:    employee.ManagerID,
+		JobTitle:     employee.JobTitle,
+		Status:       employee.Status,
+		CreatedAt:    employee.CreatedAt,
+	}
+	if employee.Department != nil {
+		response.DepartmentName = employee.Department.Name
+	}
+
+	if access.PersonalDataOf(employee.UserID) {
+		email := employee.User.Email
+		hireDate := employee.HireDate
+		response.Email = &email
+		response.HireDate = &hireDate
+	} else {
+		response.RedactedFields = []string{"email", "hire_date"}
+	}
+	return response
+}
+
+func newEmployeeResponses(employees []*models.Employee, access services.FieldAccess) []employeeResponse {
+	responses := make([]employeeResponse, 0, len(employees))
+	for _, employee := range employees {
+		responses = append(responses, newEmployeeResponse(*employee, access))
+	}
+	return responses
+}
+
+type leaveResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	EmployeeID     uuid.UUID  `json:"employee_id"`
+	EmployeeName   string     `json:"employee_name"`
+	EmployeeEmail  *string    `json:"employee_email"`
+	StartDate      time.Time  `json:"start_date"`
+	EndDate        time.Time  `json:"end_date"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	ApproverID     *uuid.UUID `json:"approver_id"`
+	ReviewedBy     *uuid.UUID `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	RedactedFields []string   `json:"redacted_fields,omitempty"`
+}
+
+func newLeaveResponse(leave models.LeaveRequest, access services.FieldAccess) leaveResponse {
+	response := leaveResponse{
+		ID:           leave.ID,
+		UserID:       leave.UserID,
+		EmployeeID:   leave.EmployeeID,
+		EmployeeName: leave.Employee.FirstName + " " + leave.Employee.LastName,
+		StartDate:    leave.StartDate,
+		EndDate:      leave.EndDate,
+		Reason:       leave.Reason,
+		Status:       leave.Status,
+		ApproverID:   leave.ApproverID,
+		ReviewedBy:   leave.ReviewedBy,
+		ReviewedAt:   leave.ReviewedAt,
+		CreatedAt:    leave.CreatedAt,
+	}
+	if response.EmployeeName == " " {
+		response.EmployeeName = ""
+	}
+
+	if !access.PersonalDataOf(leave.UserID) {
+		response.RedactedFields = []string{"employee_email"}
+	} else if leave.User.Email != "" {
+		email := leave.User.Email
+		response.EmployeeEmail = &email
+	}
+	return response
+}
+
+func newLeaveResponses(leaves []models.LeaveRequest, access services.FieldAccess) []leaveResponse {
+	responses := make([]leaveResponse, 0, len(leaves))
+	for _, leave := range leaves {
+		responses = append(responses, newLeaveResponse(leave, access))
+	}
+	return responses
+}
+
+type payslipResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	EmployeeID     uuid.UUID  `json:"employee_id"`
+	EmployeeName   string     `json:"employee_name"`
+	Month          int        `json:"month"`
+	Year           int        `json:"year"`
+	BasicPay       *float64   `json:"basic_pay"`
+	Allowances     *float64   `json:"allowances"`
+	Deductions     *float64   `json:"deductions"`
+	NetPay         *float64   `json:"net_pay"`
+	Currency       string     `json:"currency"`
+	GeneratedBy    *uuid.UUID `json:"generated_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	RedactedFields []string   `json:"redacted_fields,omitempty"`
+}
+
+// payslipAmountFields are hidden together: any one of them lets the others
+// be worked out.
+var payslipAmountFields = []string{"basic_pay", "allowances", "deductions", "net_pay"}
+
+func newPayslipResponse(payslip models.Payslip, access services.FieldAccess) payslipResponse {
+	response := payslipResponse{
+		ID:           payslip.ID,
+		UserID:       payslip.UserID,
+		EmployeeID:   payslip.EmployeeID,
+		EmployeeName: payslip.Employee.FirstName + " " + payslip.Employee.LastName,
+		Month:        payslip.Month,
+		Year:         payslip.Year,
+		Currency:     payslip.Currency,
+		GeneratedBy:  payslip.GeneratedBy,
+		CreatedAt:    payslip.CreatedAt,
+		UpdatedAt:    payslip.UpdatedAt,
+	}
+	if response.EmployeeName == " " {
+		response.EmployeeName = ""
+	}
+
+	if access.CompensationOf(payslip.UserID) {
+		response.BasicPay = &payslip.BasicPay
+		response.Allowances = &payslip.Allowances
+		response.Deductions = &payslip.Deductions
+		response.NetPay = &payslip.NetPay
+	} else {
+		response.RedactedFields = payslipAmountFields
+	}
+	return response
+}
+
+func newPayslipResponses(payslips []models.Payslip, access services.FieldAccess) []payslipResponse {
+	responses := make([]payslipResponse, 0, len(payslips))
+	for _, payslip := range payslips {
+		responses = append(responses, newPayslipResponse(payslip, access))
+	}
+	return responses
+}
+
+type employmentEventResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	EmployeeID    uuid.UUID  `json:"employee_id"`
+	Type          string     `json:"type"`
+	EffectiveDate string     `json:"effective_date"`
+	DepartmentID  *uuid.UUID `json:"department_id"`
+	Role          *string    `json:"role"`
+	Status        *string    `json:"status"`
+	JobTitle      *string    `json:"job_title"`
+	Reason        string     `json:"reason"`
+	RecordedBy    *uuid.UUID `json:"recorded_by"`
+	Pending       bool       `json:"pending"`
+	AppliedAt     *time.Time `json:"applied_at"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
+	CancelledBy   *uuid.UUID `json:"cancelled_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func newEmploymentEventResponse(event models.EmploymentEvent) employmentEventResponse {
+	return employmentEventResponse{
+		ID:            event.ID,
+		EmployeeID:    event.EmployeeID,
+		Type:          event.Type,
+		EffectiveDate: event.EffectiveDate.Format("2006-01-02"),
+		DepartmentID:  event.DepartmentID,
+		Role:          event.Role,
+		Status:        event.Status,
+		JobTitle:      event.JobTitle,
+		Reason:        event.Reason,
+		RecordedBy:    event.RecordedBy,
+		Pending:       event.Pending(),
+		AppliedAt:     event.AppliedAt,
+		CancelledAt:   event.CancelledAt,
+		CancelledBy:   event.CancelledBy,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+func newEmploymentEventResponses(events []models.EmploymentEvent) []employmentEventResponse {
+	responses := make([]employmentEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, newEmploymentEventResponse(event))
+	}
+	return responses
+}
+
+type offboardingResponse struct {
+	ID             uuid.UUID                 `json:"id"`
+	EmployeeID     uuid.UUID                 `json:"employee_id"`
+	EventID        uuid.UUID                 `json:"event_id"`
+	LastWorkingDay string                    `json:"last_working_day"`
+	AccessEndsAt   time.Time                 `json:"access_ends_at"`
+	ReasonCode     string                    `json:"reason_code"`
+	Notes          string                    `json:"notes"`
+	InitiatedBy    *uuid.UUID                `json:"initiated_by"`
+	Status         string                    `json:"status"`
+	Tasks          []offboardingTaskResponse `json:"tasks"`
+	CreatedAt      time.Time                 `json:"created_at"`
+}
+
+// newOffboardingResponse reports the offboarding as scheduled until its
+// termination takes effect and as terminated afterwards.
+func newOffboardingResponse(offboarding models.Offboarding) offboardingResponse {
+	response := offboardingResponse{
+		ID:             offboarding.ID,
+		EmployeeID:     offboarding.EmployeeID,
+		EventID:        offboarding.EventID,
+		LastWorkingDay: offboarding.LastWorkingDay.Format("2006-01-02"),
+		AccessEndsAt:   offboarding.Event.EffectiveDate,
+		ReasonCode:     offboarding.ReasonCode,
+		Notes:          offboarding.Notes,
+		InitiatedBy:    offboarding.InitiatedBy,
+		Status:         "scheduled",
+		Tasks:          make([]offboardingTaskResponse, 0, len(offboarding.Tasks)),
+		CreatedAt:      offboarding.CreatedAt,
+	}
+	if offboarding.Event.AppliedAt != nil {
+		response.Status = "terminated"
+	}
+	for _, task := range offboarding.Tasks {
+		response.Tasks = append(response.Tasks, newOffboardingTaskResponse(task))
+	}
+	return response
+}
+
+type offboardingTaskResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	OffboardingID  uuid.UUID  `json:"offboarding_id"`
+	EmployeeID     *uuid.UUID `json:"employee_id,omitempty"`
+	EmployeeName   string     `json:"employee_name,omitempty"`
+	LastWorkingDay string     `json:"last_working_day,omitempty"`
+	DepartmentID   uuid.UUID  `json:"department_id"`
+	DepartmentName string     `json:"department_name,omitempty"`
+	Title          string     `json:"title"`
+	Position       int        `json:"position"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CompletedBy    *uuid.UUID `json:"completed_by"`
+}
+
+func newOffboardingTaskResponse(task models.OffboardingTask) offboardingTaskResponse {
+	response := offboardingTaskResponse{
+		ID:             task.ID,
+		OffboardingID:  task.OffboardingID,
+		DepartmentID:   task.DepartmentID,
+		DepartmentName: task.Department.Name,
+		Title:          task.Title,
+		Position:       task.Position,
+		CompletedAt:    task.CompletedAt,
+		CompletedBy:    task.CompletedBy,
+	}
+	if task.Offboarding != nil {
+		response.EmployeeID = &task.Offboarding.EmployeeID
+		response.EmployeeName = strings.TrimSpace(task.Offboarding.Employee.FirstName + " " + task.Offboarding.Employee.LastName)
+		response.LastWorkingDay = task.Offboarding.LastWorkingDay.Format("2006-01-02")
+	}
+	return response
+}
+
+func newOffboardingTaskResponses(tasks []models.OffboardingTask) []offboardingTaskResponse {
+	responses := make([]offboardingTaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		responses = append(responses, newOffboardingTaskResponse(task))
+	}
+	return responses
+}
+
+type offboardingTemplateResponse struct {
+	ID             uuid.UUID `json:"id"`
+	DepartmentID   uuid.UUID `json:"department_id"`
+	DepartmentName string    `json:"department_name,omitempty"`
+	Title          string    `json:"title"`
+	Position       int       `json:"position"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newOffboardingTemplateResponse(template models.OffboardingTaskTemplate) offboardingTemplateResponse {
+	return offboardingTemplateResponse{
+		ID:             template.ID,
+		DepartmentID:   template.DepartmentID,
+		DepartmentName: template.Department.Name,
+		Title:          template.Title,
+		Position:       template.Position,
+		CreatedAt:      template.CreatedAt,
+	}
+}
+
+func newOffboardingTemplateResponses(templates []models.OffboardingTaskTemplate) []offboardingTemplateResponse {
+	responses := make([]offboardingTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		responses = append(responses, newOffboardingTemplateResponse(template))
+	}
+	return responses
+}
+
+type departmentResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newDepartmentResponse(department models.Department) departmentResponse {
+	return departmentResponse{
+		ID:        department.ID,
+		Name:      department.Name,
+		CreatedAt: department.CreatedAt,
+	}
+}
+
+type auditLogResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	UserID         *uuid.UUID      `json:"user_id"`
+	UserEmail      *string         `json:"user_email"`
+	Action         string          `json:"action"`
+	Entity         string          `json:"entity"`
+	EntityID       *uuid.UUID      `json:"entity_id"`
+	Metadata       json.RawMessage `json:"metadata"`
+	CreatedAt      time.Time       `json:"created_at"`
+	RedactedFields []string        `json:"redacted_fields,omitempty"`
+}
+
+func newAuditLogResponse(log models.AuditLog, access services.FieldAccess) auditLogResponse {
+	response := auditLogResponse{
+		ID:        log.ID,
+		UserID:    log.UserID,
+		Action:    log.Action,
+		Entity:    log.Entity,
+		EntityID:  log.EntityID,
+		Metadata:  json.RawMessage(log.Metadata),
+		CreatedAt: log.CreatedAt,
+	}
+	if len(response.Metadata) == 0 {
+		response.Metadata = json.RawMessage("null")
+	}
+
+	if log.User != nil {
+		if access.PersonalDataOf(log.User.ID) {
+			email := log.User.Email
+			response.UserEmail = &email
+		} else {
+			response.RedactedFields = []string{"user_email"}
+		}
+	}
+	return response
+}
+
+type apiKeyResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	ServiceAccountID uuid.UUID  `json:"service_account_id"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	Permissions      []string   `json:"permissions"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	LastUsedAt       *time.Time `json:"last_used_at"`
+	LastUsedIP       string     `json:"last_used_ip"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedBy        *uuid.UUID `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func newAPIKeyResponse(key models.APIKey) apiKeyResponse {
+	permissions := key.Permissions.Data()
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return apiKeyResponse{
+		ID:               key.ID,
+		ServiceAccountID: key.ServiceAccountID,
+		Name:             key.Name,
+		Prefix:           key.Prefix,
+		Permissions:      permissions,
+		ExpiresAt:        key.ExpiresAt,
+		LastUsedAt:       key.LastUsedAt,
+		LastUsedIP:       key.LastUsedIP,
+		RevokedAt:        key.RevokedAt,
+		CreatedBy:        key.CreatedBy,
+		CreatedAt:        key.CreatedAt,
+	}
+}
+
+type absenteeResponse struct {
+	UserID         uuid.UUID `json:"user_id"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	Email          *string   `json:"email"`
+	RedactedFields []string  `json:"redacted_fields,omitempty"`
+}
+
+func newAbsenteeResponses(rows []repositories.AbsenteeRow, access services.FieldAccess) []absenteeResponse {
+	responses := make([]absenteeResponse, 0, len(rows))
+	for _, row := range rows {
+		response := absenteeResponse{
+			UserID:    row.UserID,
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+		}
+		if access.PersonalDataOf(row.UserID) {
+			email := row.Email
+			response.Email = &email
+		} else {
+			response.RedactedFields = []string{"email"}
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
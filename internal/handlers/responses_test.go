@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/internal/services"
+)
+
+// jsonKeys marshals value and returns its top-level keys, sorted.
+func jsonKeys(t *testing.T, value interface{}) (map[string]interface{}, []string) {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return object, keys
+}
+
+func TestAbsenteeResponseShape(t *testing.T) {
+	viewerID, otherID := uuid.New(), uuid.New()
+	rows := []repositories.AbsenteeRow{
+		{UserID: viewerID, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"},
+		{UserID: otherID, FirstName: "Bob", LastName: "Builder", Email: "bob@example.com"},
+	}
+
+	responses := newAbsenteeResponses(rows, services.FieldAccess{ViewerID: viewerID})
+
+	own, keys := jsonKeys(t, responses[0])
+	if want := []string{"email", "first_name", "last_name", "user_id"}; !slices.Equal(keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	if own["email"] != "ada@example.com" {
+		t.Fatalf("expected the viewer to see their own email, got %v", own["email"])
+	}
+
+	other, keys := jsonKeys(t, responses[1])
+	if want := []string{"email", "first_name", "last_name", "redacted_fields", "user_id"}; !slices.Equal(keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	if other["email"] != nil {
+		t.Fatalf("expected another user's email to be redacted, got %v", other["email"])
+	}
+
+	full := newAbsenteeResponses(rows, services.FieldAccess{ViewerID: viewerID, PersonalData: true})
+	if object, _ := jsonKeys(t, full[1]); object["email"] != "bob@example.com" {
+		t.Fatalf("expected view_personal_data to reveal the email, got %v", object["email"])
+	}
+}
+
+func TestAPIKeyResponseOmitsHash(t *testing.T) {
+	key := models.APIKey{
+		Name:        "ci",
+		Prefix:      "sp_abc",
+		KeyHash:     "s3cr3t-hash",
+		Permissions: datatypes.NewJSONType([]string{"view_profile"}),
+	}
+
+	_, keys := jsonKeys(t, newAPIKeyResponse(key))
+	want := []string{
+		"created_at", "created_by", "expires_at", "id", "last_used_at", "last_used_ip",
+		"name", "permissions", "prefix", "revoked_at", "service_account_id",
+	}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/services"
+)
+
+// requestScope returns the scope set by middleware.ResolveScope. Without
+// one the caller reaches nobody.
+func requestScope(c *gin.Context) services.Scope {
+	value, _ := c.Get("scope")
+	scope, _ := value.(services.Scope)
+	return scope
+}
+
+// requestPermissions returns the caller's effective permissions as resolved
+// by the auth middleware, including temporary grants.
+func requestPermissions(c *gin.Context) []string {
+	value, _ := c.Get("permissions")
+	permissions, _ := value.([]string)
+	return permissions
+}
+
+// requestFieldAccess returns which sensitive fields the caller may read.
+func requestFieldAccess(c *gin.Context) services.FieldAccess {
+	viewerID, _ := uuid.Parse(c.GetString("user_id"))
+	return services.FieldAccessFor(viewerID, requestPermissions(c))
+}
+
+// respondOutOfScope writes the FORBIDDEN payload of the permission
+// middleware when err is services.ErrOutOfScope and reports whether it did.
+func respondOutOfScope(c *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrOutOfScope) {
+		return false
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":                "forbidden",
+		"code":                 "FORBIDDEN",
+		"required_roles":       []string{},
+		"required_permissions": []string{authz.PermManageAllDepartments},
+	})
+	return true
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/services"
+)
+
+// ServiceAccountHandler serves service account and API key administration
+// under /admin/service-accounts.
+type ServiceAccountHandler struct {
+	service services.APIKeyService
+}
+
+func NewServiceAccountHandler(service services.APIKeyService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{service: service}
+}
+
+func serviceAccountResponse(account models.ServiceAccount) gin.H {
+	return gin.H{
+		"id":          account.ID,
+		"name":        account.Name,
+		"description": account.Description,
+		"user_id":     account.UserID,
+		"is_active":   account.User.IsActive,
+		"created_at":  account.CreatedAt,
+	}
+}
+
+// POST /admin/service-accounts
+func (h *ServiceAccountHandler) Create(c *gin.Context) {
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	account, err := h.service.CreateServiceAccount(req.Name, req.Description, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, serviceAccountResponse(*account))
+}
+
+// GET /admin/service-accounts
+func (h *ServiceAccountHandler) List(c *gin.Context) {
+	accounts, err := h.service.ListServiceAccounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(accounts))
+	for _, account := range accounts {
+		response = append(response, serviceAccountResponse(account))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /admin/service-accounts/:id
+func (h *ServiceAccountHandler) Deactivate(c *gin.Context) {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	if err := h.service.DeactivateServiceAccount(accountID, adminID); err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "service account deactivated"})
+}
+
+// POST /admin/service-accounts/:id/keys
+// The plaintext key is only returned by this call.
+func (h *ServiceAccountHandler) CreateKey(c *gin.Context) {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account id"})
+		return
+	}
+
+	var req struct {
+		Name        string     `json:"name" binding:"required"`
+		Permissions []string   `json:"permissions" binding:"required"`
+		ExpiresAt   *time.Time `json:"expires_at"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	created, err := h.service.CreateKey(accountID, req.Name, req.Permissions, req.ExpiresAt, adminID, requestPermissions(c))
+	if err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"key":     created.Key,
+		"api_key": newAPIKeyResponse(*created.APIKey),
+	})
+}
+
+// GET /admin/service-accounts/:id/keys
+func (h *ServiceAccountHandler) ListKeys(c *gin.Context) {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account id"})
+		return
+	}
+
+	keys, err := h.service.ListKeys(accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]apiKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, newAPIKeyResponse(key))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /admin/service-accounts/:id/keys/:keyId
+func (h *ServiceAccountHandler) RevokeKey(c *gin.Context) {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account id"})
+		return
+	}
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid key id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	if err := h.service.RevokeKey(accountID, keyID, adminID); err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "api key revoked"})
+}
+
+func respondServiceAccountError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	if errors.Is(err, services.ErrAPIKeyExceedsCaller) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/services"
+)
+
+type SessionHandler struct {
+	service services.SessionService
+}
+
+func NewSessionHandler(service services.SessionService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+func sessionResponse(sessions []models.Session, currentSessionID string) []gin.H {
+	response := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, gin.H{
+			"id":           session.ID,
+			"device":       session.Device,
+			"ip_address":   session.IPAddress,
+			"user_agent":   session.UserAgent,
+			"created_at":   session.CreatedAt,
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.ID.String() == currentSessionID,
+		})
+	}
+	return response
+}
+
+// GET /profile/sessions
+func (h *SessionHandler) ListMine(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	sessions, err := h.service.ListForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionResponse(sessions, c.GetString("session_id")))
+}
+
+// DELETE /profile/sessions/:id
+func (h *SessionHandler) RevokeMine(c *gin.Context) {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	if err := h.service.Revoke(userID, sessionID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+}
+
+// GET /admin/users/:id/sessions
+func (h *SessionHandler) ListForUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	sessions, err := h.service.ListForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionResponse(sessions, c.GetString("session_id")))
+}
+
+// DELETE /admin/users/:id/sessions/:sessionId
+func (h *SessionHandler) RevokeForUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	if err := h.service.Revoke(userID, sessionID, adminID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+}
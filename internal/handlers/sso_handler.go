@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend/internal/services"
+)
+
+type SSOHandler struct {
+	service services.SSOService
+}
+
+func NewSSOHandler(service services.SSOService) *SSOHandler {
+	return &SSOHandler{service: service}
+}
+
+// GET /auth/oidc/authorize
+// Returns the IdP authorization URL, or redirects to it with ?redirect=true.
+func (h *SSOHandler) Authorize(c *gin.Context) {
+	authURL, err := h.service.AuthorizationURL()
+	if errors.Is(err, services.ErrSSODisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "identity provider unavailable"})
+		return
+	}
+
+	if c.Query("redirect") == "true" {
+		c.Redirect(http.StatusFound, authURL)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"authorization_url": authURL})
+}
+
+// POST /auth/oidc/callback
+// The frontend forwards the code and state it received on the redirect URI.
+func (h *SSOHandler) Callback(c *gin.Context) {
+	var req struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Callback(req.Code, req.State, c.Request.UserAgent(), c.ClientIP())
+	switch {
+	case errors.Is(err, services.ErrSSODisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrAccountDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "account disabled"})
+		return
+	case errors.Is(err, services.ErrSSONoAccount):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
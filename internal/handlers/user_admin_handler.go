@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/services"
+)
+
+// UserAdminHandler serves account administration endpoints under /admin/users.
+type UserAdminHandler struct {
+	throttleService services.LoginThrottleService
+	mfaService      services.MFAService
+}
+
+func NewUserAdminHandler(throttleService services.LoginThrottleService, mfaService services.MFAService) *UserAdminHandler {
+	return &UserAdminHandler{throttleService: throttleService, mfaService: mfaService}
+}
+
+// POST /admin/users/:id/unlock
+func (h *UserAdminHandler) Unlock(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	var req struct {
+		IP string `json:"ip"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.throttleService.Unlock(userID, adminID, req.IP); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account unlocked"})
+}
+
+// DELETE /admin/users/:id/mfa
+func (h *UserAdminHandler) ResetMFA(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	adminID, _ := uuid.Parse(c.GetString("user_id"))
+
+	if err := h.mfaService.Reset(userID, adminID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "mfa reset"})
+}
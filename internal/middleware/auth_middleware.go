@@ -3,15 +3,28 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"go-backend/internal/authz"
+	"go-backend/internal/services"
+	"go-backend/pkg/utils"
 )
 
-func AuthMiddleware(secret string) gin.HandlerFunc {
+// AuthMiddleware validates the bearer access token. When a denylist is
+// supplied, revoked tokens are rejected even if their signature is valid.
+// When apiKeys is supplied, an X-API-Key header authenticates a service
+// account instead. When grants is supplied, temporary grants and delegations
+// in force are added to the role's permissions.
+func AuthMiddleware(verifier utils.TokenVerifier, denylist services.TokenDenylist, apiKeys services.APIKeyService, grants services.PermissionGrantService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if rawKey := c.GetHeader("X-API-Key"); rawKey != "" && apiKeys != nil {
+			authenticateAPIKey(c, apiKeys, rawKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
@@ -25,56 +38,154 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims := jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(
-			tokenStr,
-			claims,
-			func(t *jwt.Token) (interface{}, error) {
-				return []byte(secret), nil
-			},
-			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
-		)
-
-		if err != nil || !token.Valid {
+		claims, err := verifier.Verify(tokenStr)
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			return
 		}
 
-		userID, ok := claims["user_id"].(string)
-		if !ok || userID == "" {
+		userID := claims.UserID
+		if userID == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
 			return
 		}
 
-		role, _ := claims["role"].(string)
-		employeeID, _ := claims["employee_id"].(string)
-		permissions := extractPermissions(claims["permissions"])
-		if len(permissions) == 0 {
-			permissions = authz.PermissionsForRole(role)
+		// Refresh and MFA challenge tokens are only accepted by their own endpoints.
+		if claims.TokenType != "" && claims.TokenType != utils.TokenTypeAccess {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		var issuedAt, expiresAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if claims.ExpiresAt != nil {
+			expiresAt = claims.ExpiresAt.Time
+		}
+
+		if denylist != nil {
+			parsedUserID, err := uuid.Parse(userID)
+			if err != nil || denylist.IsRevoked(claims.ID, parsedUserID, issuedAt) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+				return
+			}
+
+			if claims.SessionID != "" {
+				sessionID, err := uuid.Parse(claims.SessionID)
+				if err != nil || denylist.IsSessionRevoked(sessionID) {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session revoked"})
+					return
+				}
+			}
+		}
+
+		role := claims.Role
+
+		// A role change since the token was issued takes effect now: the
+		// current role replaces the one in the token. Impersonation tokens
+		// are rejected instead so they can never pick up extra privilege.
+		if denylist != nil {
+			parsedUserID, _ := uuid.Parse(userID)
+			current, ok := denylist.PermissionState(parsedUserID)
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+				return
+			}
+			if current.Version != claims.PermissionVersion {
+				if claims.ImpersonatorID != "" {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "permissions changed"})
+					return
+				}
+				role = current.Role
+			}
+		}
+
+		// An impersonation token lives only as long as the impersonator's own
+		// access: deactivating them, signing them out everywhere or taking
+		// impersonate_users from their role ends it. The permission cannot be
+		// granted temporarily, so the role alone decides.
+		if denylist != nil && claims.ImpersonatorID != "" {
+			impersonatorID, err := uuid.Parse(claims.ImpersonatorID)
+			if err != nil || denylist.IsRevoked("", impersonatorID, issuedAt) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+				return
+			}
+			impersonator, ok := denylist.PermissionState(impersonatorID)
+			if !ok || !authz.HasPermission(authz.PermissionsForRole(impersonator.Role), authz.PermImpersonateUsers) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "permissions changed"})
+				return
+			}
+		}
+
+		// Permissions are resolved from the role on every request so that
+		// edits to a role apply to tokens already issued.
+		permissions := authz.PermissionsForRole(role)
+
+		// A failed grant lookup leaves the caller with their role alone.
+		var delegatorIDs []uuid.UUID
+		if grants != nil {
+			parsedUserID, _ := uuid.Parse(userID)
+			if effective, err := grants.Effective(parsedUserID); err == nil {
+				permissions = mergePermissions(permissions, effective.Permissions)
+				delegatorIDs = effective.DelegatorIDs
+			}
 		}
 
 		c.Set("user_id", userID)
 		c.Set("role", role)
-		c.Set("employee_id", employeeID)
+		c.Set("employee_id", claims.EmployeeID)
 		c.Set("permissions", permissions)
+		c.Set("token_id", claims.ID)
+		c.Set("session_id", claims.SessionID)
+		c.Set("token_expires_at", expiresAt)
+		if claims.ImpersonatorID != "" {
+			c.Set("impersonator_id", claims.ImpersonatorID)
+		}
+		if len(delegatorIDs) > 0 {
+			c.Set("delegator_ids", delegatorIDs)
+		}
 
 		c.Next()
 	}
 }
 
-func extractPermissions(raw interface{}) []string {
-	switch value := raw.(type) {
-	case []string:
-		return value
-	case []interface{}:
-		out := make([]string, 0, len(value))
-		for _, v := range value {
-			if s, ok := v.(string); ok && s != "" {
-				out = append(out, s)
-			}
+// mergePermissions returns the role's permissions plus any extra ones,
+// without modifying the role's slice.
+func mergePermissions(role, extra []string) []string {
+	if len(extra) == 0 {
+		return role
+	}
+	merged := append([]string{}, role...)
+	for _, permission := range extra {
+		if !authz.HasPermission(merged, permission) {
+			merged = append(merged, permission)
 		}
-		return out
-	default:
-		return nil
 	}
+	return merged
+}
+
+// authenticateAPIKey sets the same context keys as a token login, scoped to
+// the key's permissions, and records the call against the key once the
+// handler has run.
+func authenticateAPIKey(c *gin.Context, apiKeys services.APIKeyService, rawKey string) {
+	principal, err := apiKeys.Authenticate(rawKey, c.ClientIP())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+		return
+	}
+
+	c.Set("user_id", principal.UserID.String())
+	c.Set("role", authz.RoleServiceAccount)
+	c.Set("employee_id", "")
+	c.Set("permissions", principal.Permissions)
+	c.Set("api_key_id", principal.KeyID.String())
+
+	c.Next()
+
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	apiKeys.RecordRequest(principal, c.Request.Method, path, c.Writer.Status(), c.ClientIP())
 }
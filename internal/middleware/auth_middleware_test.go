@@ -0,0 +1,410 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/services"
+	"go-backend/pkg/utils"
+)
+
+type fakeDenylist struct {
+	revoked map[string]bool
+	states  map[uuid.UUID]services.PermissionState
+}
+
+func (f *fakeDenylist) RevokeToken(jti string, userID uuid.UUID, expiresAt time.Time) error {
+	f.revoked[jti] = true
+	return nil
+}
+
+func (f *fakeDenylist) RevokeAllForUser(userID uuid.UUID) error {
+	return nil
+}
+
+// IsRevoked also treats a user ID in revoked as "logout everywhere".
+func (f *fakeDenylist) IsRevoked(jti string, userID uuid.UUID, issuedAt time.Time) bool {
+	return f.revoked[jti] || f.revoked[userID.String()]
+}
+
+func (f *fakeDenylist) RevokeSession(sessionID uuid.UUID, revokedBy *uuid.UUID) error {
+	f.revoked[sessionID.String()] = true
+	return nil
+}
+
+func (f *fakeDenylist) IsSessionRevoked(sessionID uuid.UUID) bool {
+	return f.revoked[sessionID.String()]
+}
+
+func (f *fakeDenylist) PermissionState(userID uuid.UUID) (services.PermissionState, bool) {
+	return f.states[userID], true
+}
+
+func signTestToken(t *testing.T, tokenType string) (string, string) {
+	t.Helper()
+	claims := &utils.JWTClaims{
+		UserID:    uuid.NewString(),
+		Role:      "employee",
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := utils.SignToken(claims, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token, claims.ID
+}
+
+func TestAuthMiddlewareRejectsRevokedAndRefreshTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	denylist := &fakeDenylist{revoked: map[string]bool{}}
+	r := gin.New()
+	r.GET("/me", AuthMiddleware(utils.HMACKey(testSecret), denylist, nil, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	access, jti := signTestToken(t, utils.TokenTypeAccess)
+	if code := call(access); code != http.StatusOK {
+		t.Fatalf("expected 200 for valid token, got %d", code)
+	}
+
+	_ = denylist.RevokeToken(jti, uuid.Nil, time.Now().Add(time.Hour))
+	if code := call(access); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for revoked token, got %d", code)
+	}
+
+	refresh, _ := signTestToken(t, utils.TokenTypeRefresh)
+	if code := call(refresh); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for refresh token, got %d", code)
+	}
+}
+
+type fakeAPIKeys struct {
+	services.APIKeyService
+	principal *services.APIKeyPrincipal
+	recorded  []int
+}
+
+func (f *fakeAPIKeys) Authenticate(rawKey, ipAddress string) (*services.APIKeyPrincipal, error) {
+	if rawKey != "sp_valid" {
+		return nil, services.ErrInvalidAPIKey
+	}
+	return f.principal, nil
+}
+
+func (f *fakeAPIKeys) RecordRequest(principal *services.APIKeyPrincipal, method, path string, status int, ipAddress string) {
+	f.recorded = append(f.recorded, status)
+}
+
+func TestAuthMiddlewareAPIKeyIsScopedAndAudited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	apiKeys := &fakeAPIKeys{principal: &services.APIKeyPrincipal{
+		KeyID:       uuid.New(),
+		UserID:      uuid.New(),
+		Permissions: []string{authz.PermClockAttendance},
+	}}
+	r := gin.New()
+	api := r.Group("/api", AuthMiddleware(utils.HMACKey(testSecret), nil, apiKeys, nil))
+	api.POST("/attendance", RequirePermissions(authz.PermClockAttendance), func(c *gin.Context) { c.Status(http.StatusOK) })
+	api.GET("/employees", RequirePermissions(authz.PermManageEmployees), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func(method, path, key string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call(http.MethodPost, "/api/attendance", "sp_valid"); code != http.StatusOK {
+		t.Fatalf("expected 200 within key scope, got %d", code)
+	}
+	if code := call(http.MethodGet, "/api/employees", "sp_valid"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 outside key scope, got %d", code)
+	}
+	if code := call(http.MethodGet, "/api/employees", "sp_unknown"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for unknown key, got %d", code)
+	}
+
+	if len(apiKeys.recorded) != 2 || apiKeys.recorded[0] != http.StatusOK || apiKeys.recorded[1] != http.StatusForbidden {
+		t.Fatalf("expected both authenticated calls to be recorded, got %v", apiKeys.recorded)
+	}
+}
+
+func TestAuthMiddlewareRejectsRevokedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	denylist := &fakeDenylist{revoked: map[string]bool{}}
+	r := gin.New()
+	r.GET("/me", AuthMiddleware(utils.HMACKey(testSecret), denylist, nil, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	sessionID := uuid.New()
+	token, err := utils.SignToken(&utils.JWTClaims{
+		UserID:    uuid.NewString(),
+		Role:      "employee",
+		TokenType: utils.TokenTypeAccess,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	call := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call(); code != http.StatusOK {
+		t.Fatalf("expected 200 for an active session, got %d", code)
+	}
+
+	_ = denylist.RevokeSession(sessionID, nil)
+	if code := call(); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after the session was revoked, got %d", code)
+	}
+}
+
+type fakeImpersonation struct {
+	services.ImpersonationService
+	actors []uuid.UUID
+}
+
+func (f *fakeImpersonation) ReadOnly() bool {
+	return true
+}
+
+func (f *fakeImpersonation) RecordRequest(actorID, targetUserID uuid.UUID, method, path string, status int, ipAddress string) {
+	f.actors = append(f.actors, actorID)
+}
+
+func TestImpersonationGuardBlocksWritesAndTagsActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	impersonation := &fakeImpersonation{}
+	r := gin.New()
+	api := r.Group("/api", AuthMiddleware(utils.HMACKey(testSecret), nil, nil, nil), ImpersonationGuard(impersonation))
+	api.GET("/payslips/mine", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api.POST("/leaves", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	actorID := uuid.New()
+	claims := &utils.JWTClaims{
+		UserID:         uuid.NewString(),
+		Role:           authz.RoleEmployee,
+		TokenType:      utils.TokenTypeAccess,
+		ImpersonatorID: actorID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	token, err := utils.SignToken(claims, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	plainToken, _ := signTestToken(t, utils.TokenTypeAccess)
+
+	call := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call(http.MethodGet, "/api/payslips/mine", token); code != http.StatusOK {
+		t.Fatalf("expected 200 for a read under impersonation, got %d", code)
+	}
+	if code := call(http.MethodPost, "/api/leaves", token); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a write under impersonation, got %d", code)
+	}
+	if code := call(http.MethodPost, "/api/leaves", plainToken); code != http.StatusOK {
+		t.Fatalf("expected 200 for a write without impersonation, got %d", code)
+	}
+	if len(impersonation.actors) != 2 || impersonation.actors[0] != actorID || impersonation.actors[1] != actorID {
+		t.Fatalf("expected both impersonated requests recorded against the actor, got %v", impersonation.actors)
+	}
+}
+
+func TestAuthMiddlewareAppliesRoleChangeImmediately(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	denylist := &fakeDenylist{revoked: map[string]bool{}, states: map[uuid.UUID]services.PermissionState{
+		userID: {Role: authz.RoleEmployee, Version: 2},
+	}}
+	r := gin.New()
+	api := r.Group("/api", AuthMiddleware(utils.HMACKey(testSecret), denylist, nil, nil))
+	api.GET("/employees", RequirePermissions(authz.PermManageEmployees), func(c *gin.Context) { c.Status(http.StatusOK) })
+	api.GET("/leaves/mine", RequirePermissions(authz.PermViewOwnLeaves), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Issued while the user was still a manager
+	claims := &utils.JWTClaims{
+		UserID:            userID.String(),
+		Role:              authz.RoleManager,
+		Permissions:       authz.PermissionsForRole(authz.RoleManager),
+		TokenType:         utils.TokenTypeAccess,
+		PermissionVersion: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	token, err := utils.SignToken(claims, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	call := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call("/api/employees"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 after demotion, got %d", code)
+	}
+	if code := call("/api/leaves/mine"); code != http.StatusOK {
+		t.Fatalf("expected 200 for a permission the new role keeps, got %d", code)
+	}
+}
+
+func TestAuthMiddlewareRechecksImpersonator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	targetID, actorID := uuid.New(), uuid.New()
+	denylist := &fakeDenylist{revoked: map[string]bool{}, states: map[uuid.UUID]services.PermissionState{
+		targetID: {Role: authz.RoleEmployee, Version: 1},
+		actorID:  {Role: authz.RoleAdmin, Version: 1},
+	}}
+	r := gin.New()
+	api := r.Group("/api", AuthMiddleware(utils.HMACKey(testSecret), denylist, nil, nil))
+	api.GET("/profile", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	claims := &utils.JWTClaims{
+		UserID:            targetID.String(),
+		Role:              authz.RoleEmployee,
+		TokenType:         utils.TokenTypeAccess,
+		PermissionVersion: 1,
+		ImpersonatorID:    actorID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	token, err := utils.SignToken(claims, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	call := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call(); code != http.StatusOK {
+		t.Fatalf("expected 200 while the impersonator keeps access, got %d", code)
+	}
+
+	denylist.states[actorID] = services.PermissionState{Role: authz.RoleManager, Version: 2}
+	if code := call(); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 once the impersonator lost impersonate_users, got %d", code)
+	}
+
+	denylist.states[actorID] = services.PermissionState{Role: authz.RoleAdmin, Version: 3}
+	denylist.revoked[actorID.String()] = true
+	if code := call(); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 once the impersonator was revoked, got %d", code)
+	}
+}
+
+type fakeGrants struct {
+	effective map[uuid.UUID]services.EffectiveGrants
+}
+
+func (f *fakeGrants) Grant(req services.GrantRequest, adminID uuid.UUID) (*models.PermissionGrant, error) {
+	return nil, nil
+}
+
+func (f *fakeGrants) List(granteeID *uuid.UUID, activeOnly bool) ([]models.PermissionGrant, error) {
+	return nil, nil
+}
+
+func (f *fakeGrants) Revoke(id, adminID uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeGrants) Effective(userID uuid.UUID) (services.EffectiveGrants, error) {
+	return f.effective[userID], nil
+}
+
+func TestAuthMiddlewareHonoursTemporaryGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	delegatorID := uuid.New()
+	grants := &fakeGrants{effective: map[uuid.UUID]services.EffectiveGrants{
+		userID: {Permissions: []string{authz.PermReviewLeaves}, DelegatorIDs: []uuid.UUID{delegatorID}},
+	}}
+	r := gin.New()
+	api := r.Group("/api", AuthMiddleware(utils.HMACKey(testSecret), nil, nil, grants))
+	api.GET("/leaves", RequirePermissions(authz.PermReviewLeaves), func(c *gin.Context) {
+		delegators, _ := c.Get("delegator_ids")
+		if ids, _ := delegators.([]uuid.UUID); len(ids) != 1 || ids[0] != delegatorID {
+			t.Errorf("expected the delegator in context, got %v", delegators)
+		}
+		c.Status(http.StatusOK)
+	})
+	api.GET("/employees", RequirePermissions(authz.PermManageEmployees), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func(path string, user uuid.UUID) int {
+		claims := &utils.JWTClaims{
+			UserID:    user.String(),
+			Role:      authz.RoleEmployee,
+			TokenType: utils.TokenTypeAccess,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+		token, err := utils.SignToken(claims, testSecret)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call("/api/leaves", userID); code != http.StatusOK {
+		t.Fatalf("expected 200 with a review grant, got %d", code)
+	}
+	if code := call("/api/employees", userID); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a permission not granted, got %d", code)
+	}
+	if code := call("/api/leaves", uuid.New()); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a user without grants, got %d", code)
+	}
+	if authz.HasPermission(authz.PermissionsForRole(authz.RoleEmployee), authz.PermReviewLeaves) {
+		t.Fatal("a grant must not leak into the role's permissions")
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/services"
+)
+
+// ImpersonationGuard runs after AuthMiddleware. Under an impersonation token
+// it refuses writes when the service is read-only and records every request
+// against the real actor once the handler has run.
+func ImpersonationGuard(impersonation services.ImpersonationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, err := uuid.Parse(c.GetString("impersonator_id"))
+		if err != nil {
+			c.Next()
+			return
+		}
+		targetID, _ := uuid.Parse(c.GetString("user_id"))
+
+		if impersonation.ReadOnly() && !isSafeMethod(c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "write operations are disabled while impersonating",
+				"code":  "IMPERSONATION_READ_ONLY",
+			})
+		} else {
+			c.Next()
+		}
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		impersonation.RecordRequest(actorID, targetID, c.Request.Method, path, c.Writer.Status(), c.ClientIP())
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
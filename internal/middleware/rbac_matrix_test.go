@@ -35,7 +35,7 @@ func newRBACRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 	api := r.Group("/api")
-	api.Use(AuthMiddleware(testSecret))
+	api.Use(AuthMiddleware(utils.HMACKey(testSecret), nil, nil, nil))
 
 	api.GET("/employees", RequirePermissions(authz.PermManageEmployees), func(c *gin.Context) { c.Status(http.StatusOK) })
 	api.POST("/departments", RequirePermissions(authz.PermManageDepartments), func(c *gin.Context) { c.Status(http.StatusOK) })
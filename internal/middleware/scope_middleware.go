@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go-backend/internal/services"
+)
+
+// ResolveScope runs after AuthMiddleware on department-scoped routes and
+// stores the caller's services.Scope under "scope".
+func ResolveScope(departments services.DepartmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user"})
+			return
+		}
+		permissions, _ := c.Get("permissions")
+		granted, _ := permissions.([]string)
+		delegators, _ := c.Get("delegator_ids")
+		delegatorIDs, _ := delegators.([]uuid.UUID)
+
+		scope, err := departments.ScopeFor(userID, delegatorIDs, granted)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not resolve department scope"})
+			return
+		}
+
+		c.Set("scope", scope)
+		c.Next()
+	}
+}
@@ -1,9 +1,28 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type Department struct {
 	BaseModel
 
 	Name string `gorm:"uniqueIndex;not null"`
 
 	Employees []Employee
+	Managers  []DepartmentManager
+}
+
+// DepartmentManager gives a user scoped authority over a department's
+// employees. A department can have several managers and a user can manage
+// several departments.
+type DepartmentManager struct {
+	DepartmentID uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID       uuid.UUID  `gorm:"type:uuid;primaryKey;index"`
+	AssignedBy   *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt    time.Time
+
+	User User
 }
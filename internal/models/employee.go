@@ -10,15 +10,21 @@ type Employee struct {
 	BaseModel
 
 	UserID       uuid.UUID `gorm:"type:uuid;uniqueIndex;not null"`
-	DepartmentID *uuid.UUID
-	Status       string `gorm:"type:varchar(50);not null"`
-	HireDate     time.Time
+	DepartmentID *uuid.UUID `gorm:"index"`
+	// ManagerID is the employee this one reports to; reporting lines never
+	// form a cycle.
+	ManagerID    *uuid.UUID `gorm:"type:uuid;index"`
+	Status       string `gorm:"type:varchar(50);not null;index"`
+	HireDate     time.Time `gorm:"index"`
 	FirstName    string `gorm:"type:varchar(100);not null"` // add
 	LastName     string `gorm:"type:varchar(100);not null"` // add
+	JobTitle     string `gorm:"type:varchar(150)"`
 
 	User        User
 	Department  *Department
 	Attendances []Attendance
+	// Department, status, job title and hire date are derived from these
+	EmploymentEvents []EmploymentEvent
 }
 
 	
\ No newline at end of file
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Employment event types. A hire opens an employee's history; every later
+// event changes only the fields it sets. A rehire brings back a terminated
+// or deactivated employee under the same record; after a termination it
+// starts a new period of employment and so a new hire date.
+const (
+	EmploymentEventHire         = "hire"
+	EmploymentEventTransfer     = "transfer"
+	EmploymentEventPromotion    = "promotion"
+	EmploymentEventRoleChange   = "role_change"
+	EmploymentEventStatusChange = "status_change"
+	EmploymentEventTermination  = "termination"
+	EmploymentEventRehire       = "rehire"
+)
+
+// EmploymentEvent is one effective-dated change to an employee's
+// department, role, status or job title. The employee row and the user's
+// role are derived from the events effective on or before today; events
+// dated later are applied once their day comes.
+type EmploymentEvent struct {
+	BaseModel
+
+	EmployeeID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_employment_events_employee_date,priority:1"`
+	Type          string     `gorm:"type:varchar(30);not null"`
+	EffectiveDate time.Time  `gorm:"type:date;not null;index:idx_employment_events_employee_date,priority:2"`
+	DepartmentID  *uuid.UUID `gorm:"type:uuid"`
+	Role          *string    `gorm:"type:varchar(50)"`
+	Status        *string    `gorm:"type:varchar(50)"`
+	JobTitle      *string    `gorm:"type:varchar(150)"`
+	Reason        string     `gorm:"type:text"`
+	RecordedBy    *uuid.UUID `gorm:"type:uuid"`
+
+	// AppliedAt is set once the event is reflected in the employee record;
+	// cancelled events are never applied.
+	AppliedAt   *time.Time `gorm:"index"`
+	CancelledAt *time.Time
+	CancelledBy *uuid.UUID `gorm:"type:uuid"`
+}
+
+// Pending reports whether the event is still waiting for its effective date.
+func (e *EmploymentEvent) Pending() bool {
+	return e.AppliedAt == nil && e.CancelledAt == nil
+}
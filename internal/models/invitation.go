@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationExpired  = "expired"
+	InvitationRevoked  = "revoked"
+)
+
+// Invitation is a single-use onboarding link that lets a new hire choose
+// their own password. Only the hash of the token is stored.
+type Invitation struct {
+	BaseModel
+
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	EmployeeID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Email      string    `gorm:"type:varchar(255);not null"`
+	TokenHash  string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	RevokedAt  *time.Time
+	InvitedBy  *uuid.UUID `gorm:"type:uuid"`
+}
+
+// Status derives the invitation state at now.
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case now.After(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
@@ -18,6 +18,10 @@ type LeaveRequest struct {
 	ReviewedBy *uuid.UUID `gorm:"type:uuid"`
 	ReviewedAt *time.Time
 
+	// ApproverID is the user the request was routed to: the requester's
+	// manager at the time of the request, if they had one.
+	ApproverID *uuid.UUID `gorm:"type:uuid;index"`
+
 	User     User
 	Employee Employee
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	LoginThrottleScopeAccount = "account"
+	LoginThrottleScopeIP      = "ip"
+
+	// Password reset requests are counted separately from failed logins.
+	LoginThrottleScopeResetEmail = "reset_email"
+	LoginThrottleScopeResetIP    = "reset_ip"
+)
+
+// LoginThrottle counts recent failed logins for an account (by email) or a
+// client IP address. The reset scopes count password reset requests instead.
+type LoginThrottle struct {
+	BaseModel
+
+	Scope         string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_login_throttle_scope_identifier"`
+	Identifier    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_login_throttle_scope_identifier"`
+	Failures      int       `gorm:"not null;default:0"`
+	LastFailureAt time.Time `gorm:"not null"`
+	LockedUntil   *time.Time
+}
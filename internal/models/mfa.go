@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFAFactor is a user's TOTP enrollment. It only protects logins once
+// ConfirmedAt is set.
+type MFAFactor struct {
+	BaseModel
+
+	UserID          uuid.UUID `gorm:"type:uuid;uniqueIndex;not null"`
+	SecretEncrypted string    `gorm:"type:text;not null" json:"-"`
+	ConfirmedAt     *time.Time
+	LastUsedStep    int64 `gorm:"not null;default:0"`
+
+	User User
+}
+
+// MFARecoveryCode is a single-use fallback code, stored by hash.
+type MFARecoveryCode struct {
+	BaseModel
+
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UsedAt   *time.Time
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offboarding is the termination of an employee: the termination event,
+// effective the day after the last working day, and the checklist the
+// departments work through. It is withdrawn when the event is cancelled.
+type Offboarding struct {
+	BaseModel
+
+	EmployeeID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	EventID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex"`
+	LastWorkingDay time.Time  `gorm:"type:date;not null"`
+	ReasonCode     string     `gorm:"type:varchar(30);not null"`
+	Notes          string     `gorm:"type:text"`
+	InitiatedBy    *uuid.UUID `gorm:"type:uuid"`
+
+	Employee Employee
+	Event    EmploymentEvent
+	Tasks    []OffboardingTask
+}
+
+// OffboardingTask is one checklist item of an offboarding, for a department
+// to complete.
+type OffboardingTask struct {
+	BaseModel
+
+	OffboardingID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	DepartmentID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Title         string     `gorm:"type:varchar(200);not null"`
+	Position      int        `gorm:"not null;default:0"`
+	CompletedAt   *time.Time `gorm:"index"`
+	CompletedBy   *uuid.UUID `gorm:"type:uuid"`
+
+	Department  Department
+	Offboarding *Offboarding
+}
+
+// OffboardingTaskTemplate is a task every offboarding gives to a
+// department, such as recovering a laptop or closing payroll.
+type OffboardingTaskTemplate struct {
+	BaseModel
+
+	DepartmentID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Title        string    `gorm:"type:varchar(200);not null"`
+	Position     int       `gorm:"not null;default:0"`
+
+	Department Department
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links an external identity provider account to a user.
+// Issuer and Subject together identify the account; the email is kept only
+// for display and auditing.
+type UserIdentity struct {
+	BaseModel
+
+	UserID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	Issuer  string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identity_subject"`
+	Subject string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identity_subject"`
+	Email   string    `gorm:"type:varchar(255)"`
+
+	User User
+}
+
+// OIDCLoginState holds the per-attempt secrets of an authorization-code
+// login until the callback consumes it. The state is stored by hash.
+type OIDCLoginState struct {
+	BaseModel
+
+	StateHash    string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Nonce        string    `gorm:"type:varchar(128);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "github.com/google/uuid"
+
+// PasswordHistory keeps the bcrypt hashes of a user's recent passwords so
+// they cannot be reused.
+type PasswordHistory struct {
+	BaseModel
+
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	PasswordHash string    `gorm:"not null" json:"-"`
+}
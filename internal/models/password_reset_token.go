@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken is a single-use reset link, stored by hash.
+type PasswordResetToken struct {
+	BaseModel
+
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash   string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	UsedAt      *time.Time
+	RequestedIP string `gorm:"type:varchar(64)"`
+
+	User User
+}
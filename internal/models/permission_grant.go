@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermissionGrant temporarily extends what a user may do between StartsAt
+// and EndsAt. It grants either a single Permission or, when DelegatorID is
+// set, everything the delegator may do, including their departments.
+type PermissionGrant struct {
+	BaseModel
+
+	GranteeID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Permission  string     `gorm:"type:varchar(100)"`
+	DelegatorID *uuid.UUID `gorm:"type:uuid;index"`
+	StartsAt    time.Time  `gorm:"not null"`
+	EndsAt      time.Time  `gorm:"not null;index"`
+	Reason      string     `gorm:"type:varchar(255);not null"`
+	GrantedBy   uuid.UUID  `gorm:"type:uuid;not null"`
+	RevokedAt   *time.Time
+	RevokedBy   *uuid.UUID `gorm:"type:uuid"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken tracks an issued refresh token by the hash of its jti.
+// Tokens rotated from the same login share a FamilyID.
+type RefreshToken struct {
+	BaseModel
+
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	FamilyID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash    string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UserAgent    string    `gorm:"type:varchar(255)"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	RotatedAt    *time.Time
+	ReplacedByID *uuid.UUID `gorm:"type:uuid"`
+	RevokedAt    *time.Time
+
+	User User
+}
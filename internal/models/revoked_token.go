@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken denylists a single access token by jti until it expires.
+type RevokedToken struct {
+	BaseModel
+
+	JTI       string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission is a capability checked in code. Rows mirror the authz
+// catalog; admins compose them into roles but cannot invent new ones.
+type Permission struct {
+	Name        string `gorm:"type:varchar(100);primaryKey"`
+	Description string `gorm:"type:text"`
+	CreatedAt   time.Time
+}
+
+// Role is a named set of permissions. System roles are referenced by code
+// and cannot be deleted.
+type Role struct {
+	BaseModel
+
+	Name        string `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string `gorm:"type:text"`
+	IsSystem    bool   `gorm:"not null;default:false"`
+
+	Permissions []RolePermission `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// RolePermission grants a permission to a role.
+type RolePermission struct {
+	RoleID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PermissionName string    `gorm:"type:varchar(100);primaryKey"`
+
+	Permission Permission `gorm:"foreignKey:PermissionName;references:Name;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+// secret is planted in every hidden field; it must not survive marshaling.
+const secret = "s3cr3t-value"
+
+func TestSecretColumnsAreNotSerialized(t *testing.T) {
+	cases := map[string]struct {
+		value  interface{}
+		hidden []string
+	}{
+		"User": {
+			value:  User{Email: "ada@example.com", PasswordHash: secret},
+			hidden: []string{"PasswordHash", "password_hash"},
+		},
+		"APIKey": {
+			value:  APIKey{Name: "ci", Prefix: "sp_abc", KeyHash: secret, Permissions: datatypes.NewJSONType([]string{"view_profile"})},
+			hidden: []string{"KeyHash", "key_hash"},
+		},
+		"ServiceAccount": {
+			value:  ServiceAccount{APIKeys: []APIKey{{KeyHash: secret}}, User: User{PasswordHash: secret}},
+			hidden: []string{"KeyHash", "PasswordHash"},
+		},
+		"MFAFactor": {
+			value:  MFAFactor{SecretEncrypted: secret, User: User{PasswordHash: secret}},
+			hidden: []string{"SecretEncrypted", "secret_encrypted", "PasswordHash"},
+		},
+		"MFARecoveryCode": {
+			value:  MFARecoveryCode{CodeHash: secret},
+			hidden: []string{"CodeHash", "code_hash"},
+		},
+		"PasswordHistory": {
+			value:  PasswordHistory{PasswordHash: secret},
+			hidden: []string{"PasswordHash"},
+		},
+		"PasswordResetToken": {
+			value:  PasswordResetToken{TokenHash: secret},
+			hidden: []string{"TokenHash"},
+		},
+		"RefreshToken": {
+			value:  RefreshToken{TokenHash: secret},
+			hidden: []string{"TokenHash"},
+		},
+		"Invitation": {
+			value:  Invitation{TokenHash: secret},
+			hidden: []string{"TokenHash"},
+		},
+		"OIDCLoginState": {
+			value:  OIDCLoginState{StateHash: secret, CodeVerifier: secret},
+			hidden: []string{"StateHash", "CodeVerifier"},
+		},
+	}
+
+	for name, tc := range cases {
+		data, err := json.Marshal(tc.value)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", name, err)
+		}
+		body := string(data)
+		if strings.Contains(body, secret) {
+			t.Fatalf("%s: secret leaked into %s", name, body)
+		}
+		for _, field := range tc.hidden {
+			if strings.Contains(body, `"`+field+`"`) {
+				t.Fatalf("%s: expected %s to be absent from %s", name, field, body)
+			}
+		}
+	}
+}
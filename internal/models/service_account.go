@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ServiceAccount is a non-human principal used by integrations. It is backed
+// by a User with RoleServiceAccount so audit entries keep a valid actor.
+type ServiceAccount struct {
+	BaseModel
+
+	UserID      uuid.UUID  `gorm:"type:uuid;uniqueIndex;not null"`
+	Name        string     `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Description string     `gorm:"type:text"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid"`
+
+	User    User
+	APIKeys []APIKey
+}
+
+// APIKey authenticates a service account. Only the SHA-256 hash of the key is
+// stored; Prefix is kept in clear so admins can tell keys apart.
+type APIKey struct {
+	BaseModel
+
+	ServiceAccountID uuid.UUID                    `gorm:"type:uuid;not null;index"`
+	Name             string                       `gorm:"type:varchar(100);not null"`
+	Prefix           string                       `gorm:"type:varchar(16);not null"`
+	KeyHash          string                       `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Permissions      datatypes.JSONType[[]string] `gorm:"not null"`
+	ExpiresAt        *time.Time
+	LastUsedAt       *time.Time
+	LastUsedIP       string `gorm:"type:varchar(64)"`
+	RevokedAt        *time.Time
+	CreatedBy        *uuid.UUID `gorm:"type:uuid"`
+}
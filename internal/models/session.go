@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one login of a user on one device. Its ID is the refresh token
+// family ID and the "sid" claim of every access token issued for it.
+type Session struct {
+	BaseModel
+
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Device     string    `gorm:"type:varchar(100)"`
+	IPAddress  string    `gorm:"type:varchar(64)"`
+	UserAgent  string    `gorm:"type:varchar(255)"`
+	LastSeenAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+	RevokedAt  *time.Time
+	RevokedBy  *uuid.UUID `gorm:"type:uuid"`
+}
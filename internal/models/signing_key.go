@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SigningKey is an asymmetric JWT signing key. The newest unretired key signs
+// new tokens; retired keys stay published for verification until RetiredAt
+// is older than the longest token lifetime.
+type SigningKey struct {
+	BaseModel
+
+	KID                 string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Algorithm           string `gorm:"type:varchar(20);not null"`
+	PrivateKeyEncrypted string `gorm:"type:text;not null"`
+	RetiredAt           *time.Time
+}
@@ -1,13 +1,33 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type User struct {
 	BaseModel
 
 	Email        string `gorm:"uniqueIndex;not null"`
-	PasswordHash string `gorm:"not null"`
+	PasswordHash string `gorm:"not null" json:"-"`
 	Role         string `gorm:"type:varchar(50);not null"`
 	IsActive     bool   `gorm:"default:true"`
 
+	// PermissionVersion is bumped on every role change; tokens carrying an
+	// older version are re-evaluated against the current role.
+	PermissionVersion int `gorm:"not null;default:1"`
+
+	// PasswordChangedAt drives password expiry; nil means CreatedAt.
+	PasswordChangedAt *time.Time
+
+	// TokensRevokedAt invalidates every token issued before it ("logout everywhere").
+	TokensRevokedAt *time.Time
+
+	// DeactivatedAt/DeactivatedBy record who cut off access and when.
+	DeactivatedAt *time.Time
+	DeactivatedBy *uuid.UUID `gorm:"type:uuid"`
+
 	Employee  *Employee
 	AuditLogs []AuditLog
 }
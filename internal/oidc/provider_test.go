@@ -0,0 +1,181 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "staffpoint"
+	testClientSecret = "client-secret"
+	testRedirectURL  = "http://localhost:5173/auth/callback"
+	testCode         = "auth-code"
+)
+
+// mockIdP is a minimal OpenID provider: discovery, JWKS and a token endpoint
+// that enforces PKCE against the challenge sent to the authorization URL.
+type mockIdP struct {
+	t         *testing.T
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{t: t, key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "idp-key",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != testClientID || secret != testClientSecret {
+			http.Error(w, "invalid_client", http.StatusUnauthorized)
+			return
+		}
+		if r.FormValue("code") != testCode || r.FormValue("redirect_uri") != testRedirectURL {
+			http.Error(w, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+		if PKCEChallenge(r.FormValue("code_verifier")) != idp.challenge {
+			http.Error(w, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   testClientID,
+			"sub":   "user-123",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": idp.nonce,
+		}
+		for name, value := range idp.claims {
+			claims[name] = value
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "idp-key"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeJSON(w, map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize plays the browser leg: it records what the IdP would have seen
+// on the authorization request.
+func (idp *mockIdP) authorize(authURL string) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != testClientID {
+		idp.t.Fatalf("unexpected authorization request: %s", authURL)
+	}
+	idp.challenge = query.Get("code_challenge")
+	idp.nonce = query.Get("nonce")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestProvider(idp *mockIdP) *Provider {
+	return NewProvider(Config{
+		Issuer:       idp.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	}, idp.server.Client())
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{
+		"email":          "jane@example.com",
+		"email_verified": "true",
+		"given_name":     "Jane",
+		"groups":         []string{"hr-managers", "staff"},
+	}
+	provider := newTestProvider(idp)
+	ctx := context.Background()
+
+	verifier := "a-sufficiently-long-code-verifier-for-the-pkce-exchange"
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	idp.authorize(authURL)
+
+	identity, err := provider.Exchange(ctx, testCode, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Subject != "user-123" || identity.Issuer != idp.server.URL {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	if identity.Email != "jane@example.com" || !identity.EmailVerified || identity.GivenName != "Jane" {
+		t.Fatalf("unexpected profile claims %+v", identity)
+	}
+	if len(identity.Groups) != 2 || identity.Groups[0] != "hr-managers" {
+		t.Fatalf("unexpected groups %v", identity.Groups)
+	}
+}
+
+func TestExchangeRejectsWrongVerifierAndNonce(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestProvider(idp)
+	ctx := context.Background()
+
+	verifier := "a-sufficiently-long-code-verifier-for-the-pkce-exchange"
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	idp.authorize(authURL)
+
+	if _, err := provider.Exchange(ctx, testCode, "some-other-verifier", "nonce-1"); err == nil {
+		t.Fatal("expected a PKCE mismatch to fail")
+	}
+	if _, err := provider.Exchange(ctx, testCode, verifier, "another-nonce"); err == nil {
+		t.Fatal("expected a nonce mismatch to fail")
+	}
+}
@@ -3,13 +3,14 @@ package repositories
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AnalyticsRepository interface {
 	DailySummary(date time.Time) (map[string]int64, error)
 	AttendanceTrend(from, to time.Time) ([]TrendPoint, error)
-	Absentees(date time.Time) ([]string, error)
+	Absentees(date time.Time) ([]AbsenteeRow, error)
 }
 
 type TrendPoint struct {
@@ -17,6 +18,13 @@ type TrendPoint struct {
 	Count int64
 }
 
+type AbsenteeRow struct {
+	UserID    uuid.UUID
+	FirstName string
+	LastName  string
+	Email     string
+}
+
 type analyticsRepository struct {
 	db *gorm.DB
 }
@@ -65,18 +73,19 @@ func (r *analyticsRepository) AttendanceTrend(from, to time.Time) ([]TrendPoint,
 	return data, err
 }
 
-func (r *analyticsRepository) Absentees(date time.Time) ([]string, error) {
-	var emails []string
+func (r *analyticsRepository) Absentees(date time.Time) ([]AbsenteeRow, error) {
+	var rows []AbsenteeRow
 
 	err := r.db.Raw(`
-		SELECT u.email
+		SELECT u.id AS user_id, e.first_name, e.last_name, u.email
 		FROM users u
 		JOIN employees e ON u.id = e.user_id
 		WHERE e.status = 'active'
 		AND e.id NOT IN (
 			SELECT employee_id FROM attendances WHERE work_date = ?
 		)
-	`, date).Scan(&emails).Error
+		ORDER BY e.last_name, e.first_name
+	`, date).Scan(&rows).Error
 
-	return emails, err
+	return rows, err
 }
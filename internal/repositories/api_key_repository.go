@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type APIKeyRepository interface {
+	CreateServiceAccount(account *models.ServiceAccount) error
+	FindServiceAccount(id uuid.UUID) (*models.ServiceAccount, error)
+	ListServiceAccounts() ([]models.ServiceAccount, error)
+
+	Create(key *models.APIKey) error
+	FindByHash(keyHash string) (*models.APIKey, error)
+	FindByID(serviceAccountID, id uuid.UUID) (*models.APIKey, error)
+	ListForServiceAccount(serviceAccountID uuid.UUID) ([]models.APIKey, error)
+	Revoke(id uuid.UUID, at time.Time) error
+	RevokeAllForServiceAccount(serviceAccountID uuid.UUID, at time.Time) error
+	TouchLastUsed(id uuid.UUID, at time.Time, ip string) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) CreateServiceAccount(account *models.ServiceAccount) error {
+	return r.db.Create(account).Error
+}
+
+func (r *apiKeyRepository) FindServiceAccount(id uuid.UUID) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	if err := r.db.Preload("User").First(&account, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *apiKeyRepository) ListServiceAccounts() ([]models.ServiceAccount, error) {
+	var accounts []models.ServiceAccount
+	err := r.db.Preload("User").Order("name").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepository) FindByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) FindByID(serviceAccountID, id uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Where("id = ? AND service_account_id = ?", id, serviceAccountID).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) ListForServiceAccount(serviceAccountID uuid.UUID) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Where("service_account_id = ?", serviceAccountID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) Revoke(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *apiKeyRepository) RevokeAllForServiceAccount(serviceAccountID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("service_account_id = ? AND revoked_at IS NULL", serviceAccountID).
+		Update("revoked_at", at).Error
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id uuid.UUID, at time.Time, ip string) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"go-backend/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -10,6 +11,9 @@ type DepartmentRepository interface {
 	Create(dept *models.Department) error
 	List() ([]models.Department, error)
 	FindByID(id string) (*models.Department, error)
+	ListManagers(departmentID uuid.UUID) ([]models.DepartmentManager, error)
+	ReplaceManagers(departmentID uuid.UUID, userIDs []uuid.UUID, assignedBy uuid.UUID) error
+	ManagedDepartmentIDs(userIDs []uuid.UUID) ([]uuid.UUID, error)
 }
 
 type departmentRepository struct {
@@ -37,3 +41,37 @@ func (r *departmentRepository) FindByID(id string) (*models.Department, error) {
 	}
 	return &dept, nil
 }
+
+func (r *departmentRepository) ListManagers(departmentID uuid.UUID) ([]models.DepartmentManager, error) {
+	var managers []models.DepartmentManager
+	err := r.db.Preload("User").Where("department_id = ?", departmentID).Order("created_at").Find(&managers).Error
+	return managers, err
+}
+
+// ReplaceManagers sets the department's managers to exactly userIDs.
+func (r *departmentRepository) ReplaceManagers(departmentID uuid.UUID, userIDs []uuid.UUID, assignedBy uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("department_id = ?", departmentID).Delete(&models.DepartmentManager{}).Error; err != nil {
+			return err
+		}
+
+		managers := make([]models.DepartmentManager, 0, len(userIDs))
+		for _, userID := range userIDs {
+			managers = append(managers, models.DepartmentManager{
+				DepartmentID: departmentID,
+				UserID:       userID,
+				AssignedBy:   &assignedBy,
+			})
+		}
+		if len(managers) == 0 {
+			return nil
+		}
+		return tx.Omit("User").Create(&managers).Error
+	})
+}
+
+func (r *departmentRepository) ManagedDepartmentIDs(userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.DepartmentManager{}).Distinct("department_id").Where("user_id IN ?", userIDs).Pluck("department_id", &ids).Error
+	return ids, err
+}
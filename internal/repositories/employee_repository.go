@@ -1,20 +1,78 @@
 package repositories
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"go-backend/internal/models"
 )
 
+// ErrReportingCycle is returned when a manager assignment would make an
+// employee report, directly or indirectly, to themselves.
+var ErrReportingCycle = errors.New("manager assignment would create a reporting cycle")
+
+// EmployeeScope restricts a query to the employees in DepartmentIDs and
+// those listed in EmployeeIDs, such as a manager's reporting line. Methods
+// taking a *EmployeeScope treat nil as every employee.
+type EmployeeScope struct {
+	DepartmentIDs []uuid.UUID
+	EmployeeIDs   []uuid.UUID
+}
+
+// employeesInScope is a subquery selecting the IDs of employees in scope.
+func employeesInScope(db *gorm.DB, scope *EmployeeScope) *gorm.DB {
+	return db.Model(&models.Employee{}).Select("id").
+		Where("department_id IN ? OR id IN ?", scope.DepartmentIDs, scope.EmployeeIDs)
+}
+
+// EmployeeFilter selects a page of the employee directory. Zero values do
+// not filter. HiredFrom and HiredTo are dates and both are inclusive.
+// AsOf limits the page to employees employed on that day according to
+// their history and matches DepartmentID against the department they were
+// in that day. OrderBy must be a trusted column expression.
+type EmployeeFilter struct {
+	Scope         *EmployeeScope
+	AsOf          *time.Time
+	DepartmentID  *uuid.UUID
+	Status        string
+	Role          string
+	HiredFrom     *time.Time
+	HiredTo       *time.Time
+	Search        string
+	OrderBy       string
+	Limit         int
+	Offset        int
+}
+
+// NewHire is a user account, its employee record, the hire event opening
+// its employment history and the audit entry that records the hire. The
+// employee ID is linked into the event and the audit entry on insert.
+type NewHire struct {
+	User     *models.User
+	Employee *models.Employee
+	Event    *models.EmploymentEvent
+	Audit    *models.AuditLog
+}
+
 // Interface
 type EmployeeRepository interface {
 	Create(employee *models.Employee) error
+	CreateHires(hires []NewHire) error
 	Update(employee *models.Employee) error
+	UpdateDetails(employee *models.Employee, events []*models.EmploymentEvent) error
 	FindByID(id uuid.UUID) (*models.Employee, error)
 	FindByUserID(userID uuid.UUID) (*models.Employee, error)
-	List() ([]*models.Employee, error)
-	Count() (int64, error)
+	FindByIDs(ids []uuid.UUID) ([]*models.Employee, error)
+	SetManager(employeeID uuid.UUID, managerID *uuid.UUID) error
+	UpdateEmploymentState(employeeID uuid.UUID, state EmploymentState) error
+	ReportIDs(managerUserIDs []uuid.UUID) ([]uuid.UUID, error)
+	ListForOrgChart() ([]*models.Employee, error)
+	Search(filter EmployeeFilter) ([]*models.Employee, int64, error)
+	Count(scope *EmployeeScope) (int64, error)
 }
 
 // Implementation
@@ -30,10 +88,59 @@ func (r *employeeRepository) Create(employee *models.Employee) error {
 	return r.db.Create(employee).Error
 }
 
+// CreateHires inserts every hire in one transaction; nothing is written if
+// any insert fails.
+func (r *employeeRepository) CreateHires(hires []NewHire) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, hire := range hires {
+			if err := createUser(tx, hire.User); err != nil {
+				return err
+			}
+			hire.Employee.UserID = hire.User.ID
+			if err := tx.Create(hire.Employee).Error; err != nil {
+				return err
+			}
+			if hire.Event != nil {
+				hire.Event.EmployeeID = hire.Employee.ID
+				if err := tx.Create(hire.Event).Error; err != nil {
+					return err
+				}
+			}
+			if hire.Audit != nil {
+				hire.Audit.EntityID = &hire.Employee.ID
+				if err := tx.Create(hire.Audit).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
 func (r *employeeRepository) Update(employee *models.Employee) error {
 	return r.db.Save(employee).Error
 }
 
+// UpdateDetails saves the employee's names together with the events
+// recording the rest of an edit, so the edit is stored whole or not at all.
+func (r *employeeRepository) UpdateDetails(employee *models.Employee, events []*models.EmploymentEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Employee{}).Where("id = ?", employee.ID).Updates(map[string]interface{}{
+			"first_name": employee.FirstName,
+			"last_name":  employee.LastName,
+		}).Error
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *employeeRepository) FindByID(id uuid.UUID) (*models.Employee, error) {
 	var emp models.Employee
 	if err := r.db.Preload("User").Preload("Department").First(&emp, "id = ?", id).Error; err != nil {
@@ -50,17 +157,242 @@ func (r *employeeRepository) FindByUserID(userID uuid.UUID) (*models.Employee, e
 	return &emp, nil
 }
 
-func (r *employeeRepository) List() ([]*models.Employee, error) {
+func (r *employeeRepository) FindByIDs(ids []uuid.UUID) ([]*models.Employee, error) {
 	var employees []*models.Employee
-	if err := r.db.Preload("User").Preload("Department").Find(&employees).Error; err != nil {
-		return nil, err
+	err := r.db.Preload("User").Preload("Department").
+		Where("id IN ?", ids).
+		Order("last_name, first_name").
+		Find(&employees).Error
+	return employees, err
+}
+
+// SetManager makes employeeID report to managerID, or to nobody when nil.
+// Assignments are serialised so two concurrent changes cannot close a loop
+// that neither would close alone.
+func (r *employeeRepository) SetManager(employeeID uuid.UUID, managerID *uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if managerID != nil {
+			if *managerID == employeeID {
+				return ErrReportingCycle
+			}
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('employees.manager_id'))").Error; err != nil {
+				return err
+			}
+
+			// Walk up from the new manager; finding the employee means a loop
+			var loops int64
+			err := tx.Raw(`
+				WITH RECURSIVE chain AS (
+					SELECT id, manager_id FROM employees WHERE id = ?
+					UNION
+					SELECT e.id, e.manager_id FROM employees e JOIN chain c ON e.id = c.manager_id
+				)
+				SELECT COUNT(*) FROM chain WHERE id = ?
+			`, *managerID, employeeID).Scan(&loops).Error
+			if err != nil {
+				return err
+			}
+			if loops > 0 {
+				return ErrReportingCycle
+			}
+		}
+
+		return tx.Model(&models.Employee{}).Where("id = ?", employeeID).Update("manager_id", managerID).Error
+	})
+}
+
+// EmploymentState is the part of an employee record derived from their
+// employment history.
+type EmploymentState struct {
+	DepartmentID *uuid.UUID
+	Status       string
+	JobTitle     string
+	HireDate     time.Time
+
+	// Account, when set, is written in the same transaction.
+	Account *AccountChange
+}
+
+// AccountChange is what an employment change does to the employee's login.
+// Deactivating also revokes every session, refresh token and access token
+// of the user, so an inactive employee never keeps a working login.
+type AccountChange struct {
+	UserID     uuid.UUID
+	Deactivate bool
+	Reactivate bool
+	Role       string // empty leaves the role alone
+	At         time.Time
+	By         *uuid.UUID
+}
+
+// UpdateEmploymentState writes only the derived columns, leaving names and
+// the reporting line untouched.
+func (r *employeeRepository) UpdateEmploymentState(employeeID uuid.UUID, state EmploymentState) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Employee{}).Where("id = ?", employeeID).Updates(map[string]interface{}{
+			"department_id": state.DepartmentID,
+			"status":        state.Status,
+			"job_title":     state.JobTitle,
+			"hire_date":     state.HireDate,
+		}).Error; err != nil {
+			return err
+		}
+		if state.Account == nil {
+			return nil
+		}
+		return updateAccount(tx, *state.Account)
+	})
+}
+
+func updateAccount(tx *gorm.DB, change AccountChange) error {
+	updates := map[string]interface{}{}
+	switch {
+	case change.Deactivate:
+		updates["is_active"] = false
+		updates["deactivated_at"] = change.At
+		updates["deactivated_by"] = change.By
+		updates["tokens_revoked_at"] = change.At
+	case change.Reactivate:
+		updates["is_active"] = true
+		updates["deactivated_at"] = nil
+		updates["deactivated_by"] = nil
+	}
+	if change.Role != "" {
+		updates["role"] = change.Role
+		updates["permission_version"] = gorm.Expr("permission_version + 1")
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(&models.User{}).Where("id = ?", change.UserID).Updates(updates).Error; err != nil {
+			return err
+		}
 	}
-	return employees, nil
+	if !change.Deactivate {
+		return nil
+	}
+
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", change.UserID).
+		Update("revoked_at", change.At).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", change.UserID).
+		Update("revoked_at", change.At).Error
+}
+
+// ReportIDs returns the employees reporting directly or indirectly to the
+// employees of managerUserIDs.
+func (r *employeeRepository) ReportIDs(managerUserIDs []uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	if len(managerUserIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Raw(`
+		WITH RECURSIVE reports AS (
+			SELECT e.id FROM employees e
+			JOIN employees m ON m.id = e.manager_id
+			WHERE m.user_id IN ?
+			UNION
+			SELECT e.id FROM employees e JOIN reports r ON e.manager_id = r.id
+		)
+		SELECT id FROM reports
+	`, managerUserIDs).Scan(&ids).Error
+	return ids, err
 }
 
-func (r *employeeRepository) Count() (int64, error) {
+// ListForOrgChart returns every employee who has not been deactivated or
+// terminated.
+func (r *employeeRepository) ListForOrgChart() ([]*models.Employee, error) {
+	var employees []*models.Employee
+	err := r.db.Preload("Department").
+		Where("status NOT IN ?", []string{"inactive", "terminated"}).
+		Order("last_name, first_name").
+		Find(&employees).Error
+	return employees, err
+}
+
+// latestEventValue is a subquery for the last value an employee's live
+// events set column to on or before the day bound to its placeholder.
+// column must be a trusted column name.
+func latestEventValue(column string) string {
+	return "SELECT " + column + " FROM employment_events" +
+		" WHERE employee_id = employees.id AND cancelled_at IS NULL AND " + column + " IS NOT NULL AND effective_date <= ?" +
+		" ORDER BY effective_date DESC, created_at DESC LIMIT 1"
+}
+
+// Search returns one page of employees matching filter together with the
+// total number of matches.
+func (r *employeeRepository) Search(filter EmployeeFilter) ([]*models.Employee, int64, error) {
+	db := r.db.Model(&models.Employee{}).Joins("JOIN users ON users.id = employees.user_id")
+	if filter.Scope != nil {
+		db = db.Where("employees.id IN (?)", employeesInScope(r.db, filter.Scope))
+	}
+	if filter.AsOf != nil {
+		day := normalizeDate(*filter.AsOf)
+		db = db.Where(
+			"EXISTS (SELECT 1 FROM employment_events WHERE employee_id = employees.id AND type = ? AND cancelled_at IS NULL AND effective_date <= ?)",
+			models.EmploymentEventHire, day,
+		)
+		db = db.Where("COALESCE(("+latestEventValue("status")+"), '') NOT IN ?", day, []string{"inactive", "terminated"})
+		if filter.DepartmentID != nil {
+			db = db.Where("("+latestEventValue("department_id")+") = ?", day, *filter.DepartmentID)
+		}
+	} else if filter.DepartmentID != nil {
+		db = db.Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+	if filter.Status != "" {
+		db = db.Where("employees.status = ?", filter.Status)
+	}
+	if filter.Role != "" {
+		db = db.Where("users.role = ?", filter.Role)
+	}
+	if filter.HiredFrom != nil {
+		db = db.Where("employees.hire_date >= ?", *filter.HiredFrom)
+	}
+	if filter.HiredTo != nil {
+		db = db.Where("employees.hire_date < ?", filter.HiredTo.AddDate(0, 0, 1))
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		db = db.Where(
+			"((employees.first_name || ' ' || employees.last_name) ILIKE ? OR users.email ILIKE ?)",
+			pattern, pattern,
+		)
+	}
+
+	// Count and Find each start from the filtered query
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var employees []*models.Employee
+	err := db.Preload("User").Preload("Department").
+		Order(filter.OrderBy).
+		Order("employees.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&employees).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return employees, total, nil
+}
+
+// escapeLike makes user input match literally inside a LIKE pattern.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *employeeRepository) Count(scope *EmployeeScope) (int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Employee{}).Count(&count).Error; err != nil {
+	db := r.db.Model(&models.Employee{})
+	if scope != nil {
+		db = db.Where("id IN (?)", employeesInScope(r.db, scope))
+	}
+	if err := db.Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestUpdateEmploymentStateDeactivatesAccountInSameTransaction(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewEmployeeRepository(db)
+
+	now := time.Now().UTC()
+	err := repo.UpdateEmploymentState(uuid.New(), EmploymentState{
+		Status:  "inactive",
+		Account: &AccountChange{UserID: uuid.New(), Deactivate: true, At: now},
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	want := []string{"BEGIN", `UPDATE "employees"`, `UPDATE "users"`, `UPDATE "refresh_tokens"`, `UPDATE "sessions"`, "COMMIT"}
+	got := recorder.queries()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statements, got %q", len(want), got)
+	}
+	for i, prefix := range want {
+		if len(got[i]) < len(prefix) || got[i][:len(prefix)] != prefix {
+			t.Fatalf("statement %d: expected %s, got %q", i, prefix, got[i])
+		}
+	}
+
+	users := recorder.find(`UPDATE "users"`)
+	if active, ok := users[0].arg("is_active"); !ok || active != false {
+		t.Fatalf("expected is_active to be set to false, got %v", active)
+	}
+}
+
+func TestCreateHiresKeepsInvitedUsersInactive(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewEmployeeRepository(db)
+
+	err := repo.CreateHires([]NewHire{{
+		User:     &models.User{Email: "invitee@example.com", Role: "employee"},
+		Employee: &models.Employee{FirstName: "Ada", LastName: "Lovelace"},
+	}})
+	if err != nil {
+		t.Fatalf("create hires: %v", err)
+	}
+
+	got := recorder.queries()
+	if got[0] != "BEGIN" || got[len(got)-1] != "COMMIT" {
+		t.Fatalf("expected one transaction, got %q", got)
+	}
+	updates := recorder.find(`UPDATE "users"`)
+	if len(updates) != 1 {
+		t.Fatalf("expected is_active to be written explicitly, got %q", got)
+	}
+	if active, ok := updates[0].arg("is_active"); !ok || active != false {
+		t.Fatalf("expected is_active to be set to false, got %v", active)
+	}
+}
+
+func TestSearchTreatsHiredToAsInclusive(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewEmployeeRepository(db)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, _, err := repo.Search(EmployeeFilter{HiredFrom: &day, HiredTo: &day, OrderBy: "employees.last_name", Limit: 10}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+
+	counts := recorder.find("SELECT count(*)")
+	if len(counts) != 1 {
+		t.Fatalf("expected one count query, got %q", recorder.queries())
+	}
+	count := counts[0]
+	if !strings.Contains(count.query, "employees.hire_date >= $1 AND employees.hire_date < $2") {
+		t.Fatalf("unexpected hire date filter: %s", count.query)
+	}
+	if count.args[0] != day || count.args[1] != day.AddDate(0, 0, 1) {
+		t.Fatalf("expected hires on %s to match, got bounds %v and %v", day.Format("2006-01-02"), count.args[0], count.args[1])
+	}
+}
+
+func TestUpdateDetailsWritesNamesAndEventsInSameTransaction(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewEmployeeRepository(db)
+
+	employee := &models.Employee{FirstName: "Ada", LastName: "King"}
+	employee.ID = uuid.New()
+	role := "manager"
+	events := []*models.EmploymentEvent{{EmployeeID: employee.ID, Type: models.EmploymentEventRoleChange, Role: &role}}
+	if err := repo.UpdateDetails(employee, events); err != nil {
+		t.Fatalf("update details: %v", err)
+	}
+
+	want := []string{"BEGIN", `UPDATE "employees"`, `INSERT INTO "employment_events"`, "COMMIT"}
+	got := recorder.queries()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statements, got %q", len(want), got)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(got[i], prefix) {
+			t.Fatalf("statement %d: expected %s, got %q", i, prefix, got[i])
+		}
+	}
+}
+
+func TestSearchAsOfFiltersByHistoryInSQL(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewEmployeeRepository(db)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	departmentID := uuid.New()
+	_, _, err := repo.Search(EmployeeFilter{AsOf: &day, DepartmentID: &departmentID, OrderBy: "employees.last_name", Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+
+	counts := recorder.find("SELECT count(*)")
+	if len(counts) != 1 {
+		t.Fatalf("expected one count query, got %q", recorder.queries())
+	}
+	count := counts[0].query
+	for _, fragment := range []string{"EXISTS (SELECT 1 FROM employment_events", "SELECT status FROM employment_events", "SELECT department_id FROM employment_events"} {
+		if !strings.Contains(count, fragment) {
+			t.Fatalf("expected %q in the filter: %s", fragment, count)
+		}
+	}
+	if strings.Contains(count, "employees.department_id =") {
+		t.Fatalf("as-of search should not match today's department: %s", count)
+	}
+
+	pages := recorder.find(`SELECT "employees"`)
+	if len(pages) != 1 || !strings.Contains(pages[0].query, "LIMIT $8 OFFSET $9") {
+		t.Fatalf("expected the page to be limited in SQL, got %q", recorder.queries())
+	}
+	if args := pages[0].args; args[7] != int64(10) || args[8] != int64(20) {
+		t.Fatalf("expected limit 10 and offset 20, got %v and %v", args[7], args[8])
+	}
+}
+
+func TestSetManagerRejectsReportingCycles(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewEmployeeRepository(db)
+
+	employeeID := uuid.New()
+	if err := repo.SetManager(employeeID, &employeeID); !errors.Is(err, ErrReportingCycle) {
+		t.Fatalf("expected self-management to be refused, got %v", err)
+	}
+
+	// The new manager's chain leads back to the employee.
+	recorder.respond("WITH RECURSIVE chain", []string{"count"}, []driver.Value{int64(1)})
+	managerID := uuid.New()
+	if err := repo.SetManager(employeeID, &managerID); !errors.Is(err, ErrReportingCycle) {
+		t.Fatalf("expected a cycle to be refused, got %v", err)
+	}
+	if updates := recorder.find(`UPDATE "employees"`); len(updates) != 0 {
+		t.Fatalf("expected no manager to be written, got %q", recorder.queries())
+	}
+	got := recorder.queries()
+	if got[len(got)-1] != "ROLLBACK" {
+		t.Fatalf("expected the transaction to roll back, got %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type EmploymentEventRepository interface {
+	Create(event *models.EmploymentEvent) error
+	FindByID(id uuid.UUID) (*models.EmploymentEvent, error)
+	ListByEmployee(employeeID uuid.UUID) ([]models.EmploymentEvent, error)
+	ListEffective(asOf time.Time, employeeIDs []uuid.UUID) ([]models.EmploymentEvent, error)
+	ListDue(asOf time.Time, limit int) ([]models.EmploymentEvent, error)
+	Claim(ids []uuid.UUID, at time.Time) (int64, error)
+	Release(ids []uuid.UUID) error
+	Cancel(id, cancelledBy uuid.UUID, at time.Time) (bool, error)
+}
+
+type employmentEventRepository struct {
+	db *gorm.DB
+}
+
+func NewEmploymentEventRepository(db *gorm.DB) EmploymentEventRepository {
+	return &employmentEventRepository{db: db}
+}
+
+func (r *employmentEventRepository) Create(event *models.EmploymentEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *employmentEventRepository) FindByID(id uuid.UUID) (*models.EmploymentEvent, error) {
+	var event models.EmploymentEvent
+	if err := r.db.First(&event, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ListByEmployee returns the whole history, cancelled events included, in
+// the order the events take effect.
+func (r *employmentEventRepository) ListByEmployee(employeeID uuid.UUID) ([]models.EmploymentEvent, error) {
+	var events []models.EmploymentEvent
+	err := r.db.Where("employee_id = ?", employeeID).
+		Order("effective_date, created_at").
+		Find(&events).Error
+	return events, err
+}
+
+// ListEffective returns the live events of employeeIDs effective on or
+// before asOf, in the order the events take effect.
+func (r *employmentEventRepository) ListEffective(asOf time.Time, employeeIDs []uuid.UUID) ([]models.EmploymentEvent, error) {
+	var events []models.EmploymentEvent
+	err := r.db.Where("employee_id IN ? AND cancelled_at IS NULL AND effective_date <= ?", employeeIDs, normalizeDate(asOf)).
+		Order("effective_date, created_at").
+		Find(&events).Error
+	return events, err
+}
+
+// ListDue returns pending events whose effective date has come.
+func (r *employmentEventRepository) ListDue(asOf time.Time, limit int) ([]models.EmploymentEvent, error) {
+	var events []models.EmploymentEvent
+	err := r.db.Where("applied_at IS NULL AND cancelled_at IS NULL AND effective_date <= ?", normalizeDate(asOf)).
+		Order("effective_date, created_at").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// Claim marks pending events as applied and reports how many it claimed,
+// so only one instance applies a given event.
+func (r *employmentEventRepository) Claim(ids []uuid.UUID, at time.Time) (int64, error) {
+	result := r.db.Model(&models.EmploymentEvent{}).
+		Where("id IN ? AND applied_at IS NULL AND cancelled_at IS NULL", ids).
+		Update("applied_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// Release returns claimed events to pending after a failed apply.
+func (r *employmentEventRepository) Release(ids []uuid.UUID) error {
+	return r.db.Model(&models.EmploymentEvent{}).
+		Where("id IN ?", ids).
+		Update("applied_at", nil).Error
+}
+
+// Cancel withdraws a pending event and reports whether it was still pending.
+func (r *employmentEventRepository) Cancel(id, cancelledBy uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.EmploymentEvent{}).
+		Where("id = ? AND applied_at IS NULL AND cancelled_at IS NULL", id).
+		Updates(map[string]interface{}{"cancelled_at": at, "cancelled_by": cancelledBy})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type InvitationRepository interface {
+	Create(invitation *models.Invitation) error
+	FindByHash(tokenHash string) (*models.Invitation, error)
+	FindLatestForEmployee(employeeID uuid.UUID) (*models.Invitation, error)
+	ListLatest(scope *EmployeeScope) ([]models.Invitation, error)
+	RevokePendingForUser(userID uuid.UUID, at time.Time) error
+	Accept(acceptance InvitationAcceptance) (bool, error)
+}
+
+// InvitationAcceptance is everything accepting an invitation writes: the
+// spent invitation, the chosen password, the activated user and employee
+// and the employment event recording it.
+type InvitationAcceptance struct {
+	InvitationID uuid.UUID
+	UserID       uuid.UUID
+	EmployeeID   uuid.UUID
+	PasswordHash string
+	// RememberPassword adds the hash to the user's password history
+	RememberPassword bool
+	Event            *models.EmploymentEvent
+	At               time.Time
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(invitation *models.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByHash(tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindLatestForEmployee(employeeID uuid.UUID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := r.db.Where("employee_id = ?", employeeID).Order("created_at DESC").First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListLatest returns the most recent invitation of every employee in
+// scope, or of every employee when nil.
+func (r *invitationRepository) ListLatest(scope *EmployeeScope) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	db := r.db.Table("invitations").Select("DISTINCT ON (employee_id) *").Order("employee_id, created_at DESC")
+	if scope != nil {
+		db = db.Where("employee_id IN (?)", employeesInScope(r.db, scope))
+	}
+	err := db.Scan(&invitations).Error
+	return invitations, err
+}
+
+func (r *invitationRepository) RevokePendingForUser(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.Invitation{}).
+		Where("user_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+// Accept writes the acceptance in one transaction, updating only the
+// columns it changes. It reports false, writing nothing, if the invitation
+// was already accepted or revoked or the employee is no longer invited.
+func (r *invitationRepository) Accept(acceptance InvitationAcceptance) (bool, error) {
+	accepted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", acceptance.InvitationID).
+			Update("accepted_at", acceptance.At)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		result = tx.Model(&models.Employee{}).
+			Where("id = ? AND status = ?", acceptance.EmployeeID, "invited").
+			Update("status", "active")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errNotInvited
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", acceptance.UserID).Updates(map[string]interface{}{
+			"password_hash":       acceptance.PasswordHash,
+			"password_changed_at": acceptance.At,
+			"is_active":           true,
+		}).Error; err != nil {
+			return err
+		}
+		if acceptance.RememberPassword {
+			if err := tx.Create(&models.PasswordHistory{UserID: acceptance.UserID, PasswordHash: acceptance.PasswordHash}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(acceptance.Event).Error; err != nil {
+			return err
+		}
+		accepted = true
+		return nil
+	})
+	if errors.Is(err, errNotInvited) {
+		return false, nil
+	}
+	return accepted, err
+}
+
+// errNotInvited rolls back an acceptance for an employee who was activated
+// some other way in the meantime.
+var errNotInvited = errors.New("employee is no longer invited")
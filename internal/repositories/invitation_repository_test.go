@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestAcceptWritesEverythingInOneTransaction(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewInvitationRepository(db)
+
+	employeeID, userID := uuid.New(), uuid.New()
+	active := "active"
+	accepted, err := repo.Accept(InvitationAcceptance{
+		InvitationID:     uuid.New(),
+		UserID:           userID,
+		EmployeeID:       employeeID,
+		PasswordHash:     "hash",
+		RememberPassword: true,
+		Event:            &models.EmploymentEvent{EmployeeID: employeeID, Type: models.EmploymentEventStatusChange, Status: &active},
+		At:               time.Now().UTC(),
+	})
+	if err != nil || !accepted {
+		t.Fatalf("accept: %v, %v", accepted, err)
+	}
+
+	want := []string{"BEGIN", `UPDATE "invitations"`, `UPDATE "employees"`, `UPDATE "users"`, `INSERT INTO "password_histories"`, `INSERT INTO "employment_events"`, "COMMIT"}
+	got := recorder.queries()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statements, got %q", len(want), got)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(got[i], prefix) {
+			t.Fatalf("statement %d: expected %s, got %q", i, prefix, got[i])
+		}
+	}
+
+	// Only the changed columns are written, not a stale copy of the rows
+	employees := recorder.find(`UPDATE "employees"`)[0].query
+	if !strings.Contains(employees, `SET "status"=$1,"updated_at"=$2 WHERE`) || !strings.Contains(employees, "status = $4") {
+		t.Fatalf("unexpected employee update: %s", employees)
+	}
+	users := recorder.find(`UPDATE "users"`)[0]
+	for _, column := range []string{"email", "role", "permission_version"} {
+		if _, ok := users.arg(column); ok {
+			t.Fatalf("expected %s to be left alone: %s", column, users.query)
+		}
+	}
+	if active, ok := users.arg("is_active"); !ok || active != true {
+		t.Fatalf("expected the user to be activated, got %v", active)
+	}
+}
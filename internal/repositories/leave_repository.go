@@ -14,8 +14,9 @@ type LeaveRepository interface {
 	Update(req *models.LeaveRequest) error
 	FindByID(id uuid.UUID) (*models.LeaveRequest, error)
 	ListByUser(userID uuid.UUID, limit int) ([]models.LeaveRequest, error)
-	ListAll(status string, limit int) ([]models.LeaveRequest, error)
-	CountPending() (int64, error)
+	ListAll(status string, limit int, scope *EmployeeScope) ([]models.LeaveRequest, error)
+	ListByApprover(approverIDs []uuid.UUID, status string, limit int) ([]models.LeaveRequest, error)
+	CountPending(scope *EmployeeScope) (int64, error)
 }
 
 type leaveRepository struct {
@@ -58,7 +59,9 @@ func (r *leaveRepository) ListByUser(userID uuid.UUID, limit int) ([]models.Leav
 	return leaves, err
 }
 
-func (r *leaveRepository) ListAll(status string, limit int) ([]models.LeaveRequest, error) {
+// ListAll returns leave requests of employees in scope, or of everyone when
+// nil.
+func (r *leaveRepository) ListAll(status string, limit int, scope *EmployeeScope) ([]models.LeaveRequest, error) {
 	var leaves []models.LeaveRequest
 	if limit <= 0 {
 		limit = 50
@@ -68,14 +71,40 @@ func (r *leaveRepository) ListAll(status string, limit int) ([]models.LeaveReque
 	if status != "" {
 		db = db.Where("status = ?", status)
 	}
+	if scope != nil {
+		db = db.Where("employee_id IN (?)", employeesInScope(r.db, scope))
+	}
+
+	err := db.Find(&leaves).Error
+	return leaves, err
+}
+
+// ListByApprover returns the requests routed to any of approverIDs.
+func (r *leaveRepository) ListByApprover(approverIDs []uuid.UUID, status string, limit int) ([]models.LeaveRequest, error) {
+	var leaves []models.LeaveRequest
+	if limit <= 0 {
+		limit = 50
+	}
+
+	db := r.db.Preload("User").Preload("Employee").
+		Where("approver_id IN ?", approverIDs).
+		Order("created_at DESC").
+		Limit(limit)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
 
 	err := db.Find(&leaves).Error
 	return leaves, err
 }
 
-func (r *leaveRepository) CountPending() (int64, error) {
+func (r *leaveRepository) CountPending(scope *EmployeeScope) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.LeaveRequest{}).Where("status = ?", "pending").Count(&count).Error
+	db := r.db.Model(&models.LeaveRequest{}).Where("status = ?", "pending")
+	if scope != nil {
+		db = db.Where("employee_id IN (?)", employeesInScope(r.db, scope))
+	}
+	err := db.Count(&count).Error
 	return count, err
 }
 
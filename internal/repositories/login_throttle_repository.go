@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend/internal/models"
+)
+
+type LoginThrottleRepository interface {
+	Find(scope, identifier string) (*models.LoginThrottle, error)
+	RecordFailure(scope, identifier string, window time.Duration) (*models.LoginThrottle, error)
+	Lock(id uuid.UUID, until time.Time) error
+	Delete(scope, identifier string) error
+}
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) Find(scope, identifier string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	if err := r.db.Where("scope = ? AND identifier = ?", scope, identifier).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordFailure atomically increments the failure counter, restarting it
+// when the previous failure is older than window.
+func (r *loginThrottleRepository) RecordFailure(scope, identifier string, window time.Duration) (*models.LoginThrottle, error) {
+	now := time.Now().UTC()
+	throttle := &models.LoginThrottle{
+		Scope:         scope,
+		Identifier:    identifier,
+		Failures:      1,
+		LastFailureAt: now,
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}, {Name: "identifier"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures": gorm.Expr(
+				"CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END",
+				now.Add(-window),
+			),
+			"last_failure_at": now,
+			"updated_at":      now,
+		}),
+	}).Create(throttle).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Find(scope, identifier)
+}
+
+func (r *loginThrottleRepository) Lock(id uuid.UUID, until time.Time) error {
+	return r.db.Model(&models.LoginThrottle{}).Where("id = ?", id).Update("locked_until", until).Error
+}
+
+func (r *loginThrottleRepository) Delete(scope, identifier string) error {
+	return r.db.Where("scope = ? AND identifier = ?", scope, identifier).Delete(&models.LoginThrottle{}).Error
+}
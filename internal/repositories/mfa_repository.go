@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type MFARepository interface {
+	FindFactor(userID uuid.UUID) (*models.MFAFactor, error)
+	SaveFactor(factor *models.MFAFactor) error
+	DeleteForUser(userID uuid.UUID) error
+	AdvanceStep(factorID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uuid.UUID, codes []models.MFARecoveryCode) error
+	UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) FindFactor(userID uuid.UUID) (*models.MFAFactor, error) {
+	var factor models.MFAFactor
+	if err := r.db.First(&factor, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+func (r *mfaRepository) SaveFactor(factor *models.MFAFactor) error {
+	return r.db.Save(factor).Error
+}
+
+// DeleteForUser removes the factor and every recovery code of the user.
+func (r *mfaRepository) DeleteForUser(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.MFAFactor{}).Error
+	})
+}
+
+// AdvanceStep records step as the last accepted TOTP step, reporting false
+// when a code for this or a later step was already used.
+func (r *mfaRepository) AdvanceStep(factorID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&models.MFAFactor{}).
+		Where("id = ? AND last_used_step < ?", factorID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uuid.UUID, codes []models.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *mfaRepository) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now().UTC())
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend/internal/models"
+)
+
+type OffboardingRepository interface {
+	Create(offboarding *models.Offboarding, at time.Time) ([]models.EmploymentEvent, error)
+	FindCurrent(employeeID uuid.UUID) (*models.Offboarding, error)
+	FindTask(id uuid.UUID) (*models.OffboardingTask, error)
+	CompleteTask(id, completedBy uuid.UUID, at time.Time) (bool, error)
+	ListTasks(departmentIDs []uuid.UUID, includeCompleted bool) ([]models.OffboardingTask, error)
+	ListTemplates() ([]models.OffboardingTaskTemplate, error)
+	CreateTemplate(template *models.OffboardingTaskTemplate) error
+	DeleteTemplate(id uuid.UUID) (bool, error)
+}
+
+type offboardingRepository struct {
+	db *gorm.DB
+}
+
+func NewOffboardingRepository(db *gorm.DB) OffboardingRepository {
+	return &offboardingRepository{db: db}
+}
+
+// Create inserts the termination event, the offboarding and its tasks in
+// one transaction. Pending events of the employee effective on or after the
+// termination are cancelled at at in the same transaction and returned.
+func (r *offboardingRepository) Create(offboarding *models.Offboarding, at time.Time) ([]models.EmploymentEvent, error) {
+	var superseded []models.EmploymentEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		event := &offboarding.Event
+		err := tx.Model(&superseded).Clauses(clause.Returning{}).
+			Where("employee_id = ? AND applied_at IS NULL AND cancelled_at IS NULL AND effective_date >= ?", event.EmployeeID, event.EffectiveDate).
+			Updates(map[string]interface{}{"cancelled_at": at, "cancelled_by": event.RecordedBy}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		offboarding.EventID = offboarding.Event.ID
+		if err := tx.Omit("Employee", "Event", "Tasks").Create(offboarding).Error; err != nil {
+			return err
+		}
+		for i := range offboarding.Tasks {
+			offboarding.Tasks[i].OffboardingID = offboarding.ID
+		}
+		if len(offboarding.Tasks) == 0 {
+			return nil
+		}
+		return tx.Omit("Department", "Offboarding").Create(&offboarding.Tasks).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return superseded, nil
+}
+
+// FindCurrent returns the latest offboarding of the employee whose
+// termination has not been cancelled, with its checklist.
+func (r *offboardingRepository) FindCurrent(employeeID uuid.UUID) (*models.Offboarding, error) {
+	var offboarding models.Offboarding
+	err := r.db.
+		Joins("JOIN employment_events ON employment_events.id = offboardings.event_id").
+		Where("offboardings.employee_id = ? AND employment_events.cancelled_at IS NULL", employeeID).
+		Preload("Event").
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, created_at")
+		}).
+		Preload("Tasks.Department").
+		Order("offboardings.created_at DESC").
+		First(&offboarding).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offboarding, nil
+}
+
+func (r *offboardingRepository) FindTask(id uuid.UUID) (*models.OffboardingTask, error) {
+	var task models.OffboardingTask
+	if err := r.db.Preload("Offboarding.Event").First(&task, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CompleteTask reports whether the task was still open.
+func (r *offboardingRepository) CompleteTask(id, completedBy uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.OffboardingTask{}).
+		Where("id = ? AND completed_at IS NULL", id).
+		Updates(map[string]interface{}{"completed_at": at, "completed_by": completedBy})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListTasks returns the tasks of live offboardings assigned to
+// departmentIDs, or to every department when nil, soonest leaver first.
+func (r *offboardingRepository) ListTasks(departmentIDs []uuid.UUID, includeCompleted bool) ([]models.OffboardingTask, error) {
+	tasks := []models.OffboardingTask{}
+	if departmentIDs != nil && len(departmentIDs) == 0 {
+		return tasks, nil
+	}
+
+	query := r.db.
+		Joins("JOIN offboardings ON offboardings.id = offboarding_tasks.offboarding_id").
+		Joins("JOIN employment_events ON employment_events.id = offboardings.event_id").
+		Where("employment_events.cancelled_at IS NULL")
+	if departmentIDs != nil {
+		query = query.Where("offboarding_tasks.department_id IN ?", departmentIDs)
+	}
+	if !includeCompleted {
+		query = query.Where("offboarding_tasks.completed_at IS NULL")
+	}
+	err := query.
+		Preload("Department").
+		Preload("Offboarding.Employee").
+		Order("offboardings.last_working_day, offboarding_tasks.position, offboarding_tasks.created_at").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *offboardingRepository) ListTemplates() ([]models.OffboardingTaskTemplate, error) {
+	templates := []models.OffboardingTaskTemplate{}
+	err := r.db.Preload("Department").Order("position, created_at").Find(&templates).Error
+	return templates, err
+}
+
+func (r *offboardingRepository) CreateTemplate(template *models.OffboardingTaskTemplate) error {
+	return r.db.Omit("Department").Create(template).Error
+}
+
+// DeleteTemplate reports whether the template existed. Checklists already
+// created from it are kept.
+func (r *offboardingRepository) DeleteTemplate(id uuid.UUID) (bool, error) {
+	result := r.db.Delete(&models.OffboardingTaskTemplate{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
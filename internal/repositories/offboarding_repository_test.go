@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestCreateOffboardingCancelsLaterPendingEventsInSameTransaction(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewOffboardingRepository(db)
+
+	employeeID, actorID := uuid.New(), uuid.New()
+	terminated := "terminated"
+	effective := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	offboarding := &models.Offboarding{
+		EmployeeID:     employeeID,
+		LastWorkingDay: effective.AddDate(0, 0, -1),
+		ReasonCode:     "resignation",
+		Event: models.EmploymentEvent{
+			EmployeeID:    employeeID,
+			Type:          models.EmploymentEventTermination,
+			EffectiveDate: effective,
+			Status:        &terminated,
+			RecordedBy:    &actorID,
+		},
+		Tasks: []models.OffboardingTask{{DepartmentID: uuid.New(), Title: "Recover laptop"}},
+	}
+	if _, err := repo.Create(offboarding, time.Now().UTC()); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	want := []string{"BEGIN", `UPDATE "employment_events"`, `INSERT INTO "employment_events"`, `INSERT INTO "offboardings"`, `INSERT INTO "offboarding_tasks"`, "COMMIT"}
+	got := recorder.queries()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statements, got %q", len(want), got)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(got[i], prefix) {
+			t.Fatalf("statement %d: expected %s, got %q", i, prefix, got[i])
+		}
+	}
+
+	cancel := recorder.find(`UPDATE "employment_events"`)[0]
+	if !strings.Contains(cancel.query, "applied_at IS NULL AND cancelled_at IS NULL AND effective_date >=") {
+		t.Fatalf("expected only pending events from the termination on to be cancelled: %s", cancel.query)
+	}
+	if by, ok := cancel.arg("cancelled_by"); !ok || by != actorID.String() {
+		t.Fatalf("expected the events to be cancelled by the initiator, got %v", by)
+	}
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-backend/internal/models"
+)
+
+type OIDCRepository interface {
+	FindIdentity(issuer, subject string) (*models.UserIdentity, error)
+	CreateIdentity(identity *models.UserIdentity) error
+	CreateState(state *models.OIDCLoginState) error
+	ConsumeState(stateHash string) (*models.OIDCLoginState, error)
+	DeleteExpiredStates(before time.Time) error
+}
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+func (r *oidcRepository) FindIdentity(issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *oidcRepository) CreateIdentity(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *oidcRepository) CreateState(state *models.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState deletes and returns the login state in one statement, so a
+// callback can only be completed once.
+func (r *oidcRepository) ConsumeState(stateHash string) (*models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	result := r.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+func (r *oidcRepository) DeleteExpiredStates(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&models.OIDCLoginState{}).Error
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type PasswordHistoryRepository interface {
+	Create(entry *models.PasswordHistory) error
+	ListRecent(userID uuid.UUID, limit int) ([]models.PasswordHistory, error)
+	Prune(userID uuid.UUID, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) Create(entry *models.PasswordHistory) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *passwordHistoryRepository) ListRecent(userID uuid.UUID, limit int) ([]models.PasswordHistory, error) {
+	var entries []models.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// Prune deletes all but the newest keep entries of the user.
+func (r *passwordHistoryRepository) Prune(userID uuid.UUID, keep int) error {
+	return r.db.Exec(`
+		DELETE FROM password_histories
+		WHERE user_id = ? AND id NOT IN (
+			SELECT id FROM password_histories
+			WHERE user_id = ?
+			ORDER BY created_at DESC
+			LIMIT ?
+		)`, userID, userID, keep).Error
+}
@@ -13,7 +13,7 @@ type PayslipRepository interface {
 	FindByID(id uuid.UUID) (*models.Payslip, error)
 	FindByEmployeePeriod(employeeID uuid.UUID, month, year int) (*models.Payslip, error)
 	ListByUser(userID uuid.UUID, limit int) ([]models.Payslip, error)
-	ListAll(limit int, employeeID *uuid.UUID, month, year *int) ([]models.Payslip, error)
+	ListAll(limit int, employeeID *uuid.UUID, month, year *int, scope *EmployeeScope) ([]models.Payslip, error)
 }
 
 type payslipRepository struct {
@@ -57,7 +57,7 @@ func (r *payslipRepository) ListByUser(userID uuid.UUID, limit int) ([]models.Pa
 	return payslips, err
 }
 
-func (r *payslipRepository) ListAll(limit int, employeeID *uuid.UUID, month, year *int) ([]models.Payslip, error) {
+func (r *payslipRepository) ListAll(limit int, employeeID *uuid.UUID, month, year *int, scope *EmployeeScope) ([]models.Payslip, error) {
 	if limit <= 0 {
 		limit = 100
 	}
@@ -71,6 +71,9 @@ func (r *payslipRepository) ListAll(limit int, employeeID *uuid.UUID, month, yea
 	if year != nil {
 		db = db.Where("year = ?", *year)
 	}
+	if scope != nil {
+		db = db.Where("employee_id IN (?)", employeesInScope(r.db, scope))
+	}
 	var payslips []models.Payslip
 	err := db.Find(&payslips).Error
 	return payslips, err
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type PermissionGrantRepository interface {
+	Create(grant *models.PermissionGrant) error
+	FindByID(id uuid.UUID) (*models.PermissionGrant, error)
+	List(granteeID *uuid.UUID, activeAt *time.Time) ([]models.PermissionGrant, error)
+	ListUnexpired(granteeID uuid.UUID, now time.Time) ([]models.PermissionGrant, error)
+	Revoke(id, revokedBy uuid.UUID, at time.Time) error
+}
+
+type permissionGrantRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionGrantRepository(db *gorm.DB) PermissionGrantRepository {
+	return &permissionGrantRepository{db: db}
+}
+
+func (r *permissionGrantRepository) Create(grant *models.PermissionGrant) error {
+	return r.db.Create(grant).Error
+}
+
+func (r *permissionGrantRepository) FindByID(id uuid.UUID) (*models.PermissionGrant, error) {
+	var grant models.PermissionGrant
+	if err := r.db.First(&grant, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// List returns grants newest first, optionally for one grantee and only
+// those in force at activeAt.
+func (r *permissionGrantRepository) List(granteeID *uuid.UUID, activeAt *time.Time) ([]models.PermissionGrant, error) {
+	var grants []models.PermissionGrant
+	query := r.db.Order("created_at DESC")
+	if granteeID != nil {
+		query = query.Where("grantee_id = ?", *granteeID)
+	}
+	if activeAt != nil {
+		query = query.Where("revoked_at IS NULL AND starts_at <= ? AND ends_at > ?", *activeAt, *activeAt)
+	}
+	err := query.Find(&grants).Error
+	return grants, err
+}
+
+// ListUnexpired returns the grantee's unrevoked grants that have not ended,
+// including those that have not started yet.
+func (r *permissionGrantRepository) ListUnexpired(granteeID uuid.UUID, now time.Time) ([]models.PermissionGrant, error) {
+	var grants []models.PermissionGrant
+	err := r.db.
+		Where("grantee_id = ? AND revoked_at IS NULL AND ends_at > ?", granteeID, now).
+		Order("starts_at").
+		Find(&grants).Error
+	return grants, err
+}
+
+func (r *permissionGrantRepository) Revoke(id, revokedBy uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.PermissionGrant{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": at, "revoked_by": revokedBy}).Error
+}
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// statementRecorder is a database/sql driver that records every statement
+// instead of running it. Writes report one affected row and queries return
+// no rows unless a canned result was set with respond, which is enough to
+// check what a repository sends to Postgres.
+type statementRecorder struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+	results    []cannedResult
+}
+
+type cannedResult struct {
+	fragment string
+	columns  []string
+	rows     [][]driver.Value
+}
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+}
+
+// newRecordingDB returns a GORM handle backed by a fresh recorder.
+func newRecordingDB(t *testing.T) (*gorm.DB, *statementRecorder) {
+	t.Helper()
+	recorder := &statementRecorder{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(recorder)}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open recording db: %v", err)
+	}
+	return db, recorder
+}
+
+func (r *statementRecorder) record(query string, args []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, recordedStatement{query: query, args: args})
+}
+
+// respond makes queries containing fragment return rows.
+func (r *statementRecorder) respond(fragment string, columns []string, rows ...[]driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, cannedResult{fragment: fragment, columns: columns, rows: rows})
+}
+
+func (r *statementRecorder) result(query string) driver.Rows {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, result := range r.results {
+		if strings.Contains(query, result.fragment) {
+			return &cannedRows{columns: result.columns, rows: result.rows}
+		}
+	}
+	return emptyRows{}
+}
+
+// find returns the recorded statements containing fragment.
+func (r *statementRecorder) find(fragment string) []recordedStatement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []recordedStatement
+	for _, statement := range r.statements {
+		if strings.Contains(statement.query, fragment) {
+			found = append(found, statement)
+		}
+	}
+	return found
+}
+
+// queries returns every recorded statement in order.
+func (r *statementRecorder) queries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := make([]string, 0, len(r.statements))
+	for _, statement := range r.statements {
+		queries = append(queries, statement.query)
+	}
+	return queries
+}
+
+// arg returns the value bound to column in an INSERT or UPDATE ... SET
+// statement, relying on GORM listing columns in placeholder order.
+func (s recordedStatement) arg(column string) (driver.Value, bool) {
+	quoted := `"` + column + `"`
+	var columns []string
+	switch {
+	case strings.HasPrefix(s.query, "INSERT"):
+		start, end := strings.Index(s.query, "("), strings.Index(s.query, ")")
+		columns = strings.Split(s.query[start+1:end], ",")
+	case strings.HasPrefix(s.query, "UPDATE"):
+		set := s.query[strings.Index(s.query, " SET ")+5:]
+		if i := strings.Index(set, " WHERE "); i >= 0 {
+			set = set[:i]
+		}
+		for _, assignment := range strings.Split(set, ",") {
+			// Expressions such as "permission_version"="permission_version"+1
+			// bind nothing; skip them so the positions line up
+			if strings.Contains(assignment, "$") {
+				columns = append(columns, strings.SplitN(assignment, "=", 2)[0])
+			}
+		}
+	}
+	for i, name := range columns {
+		if strings.TrimSpace(name) == quoted && i < len(s.args) {
+			return s.args[i], true
+		}
+	}
+	return nil, false
+}
+
+func (r *statementRecorder) Connect(context.Context) (driver.Conn, error) {
+	return &recorderConn{r}, nil
+}
+
+func (r *statementRecorder) Driver() driver.Driver {
+	return recorderDriver{r}
+}
+
+type recorderDriver struct {
+	r *statementRecorder
+}
+
+func (d recorderDriver) Open(string) (driver.Conn, error) {
+	return &recorderConn{d.r}, nil
+}
+
+type recorderConn struct {
+	r *statementRecorder
+}
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return &recorderStmt{c.r, query}, nil
+}
+
+func (c *recorderConn) Close() error { return nil }
+
+func (c *recorderConn) Begin() (driver.Tx, error) {
+	c.r.record("BEGIN", nil)
+	return recorderTx{c.r}, nil
+}
+
+type recorderTx struct {
+	r *statementRecorder
+}
+
+func (t recorderTx) Commit() error {
+	t.r.record("COMMIT", nil)
+	return nil
+}
+
+func (t recorderTx) Rollback() error {
+	t.r.record("ROLLBACK", nil)
+	return nil
+}
+
+type recorderStmt struct {
+	r     *statementRecorder
+	query string
+}
+
+func (s *recorderStmt) Close() error  { return nil }
+func (s *recorderStmt) NumInput() int { return -1 }
+
+func (s *recorderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.r.record(s.query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recorderStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.r.record(s.query, args)
+	return s.r.result(s.query), nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+type cannedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *cannedRows) Columns() []string { return r.columns }
+func (r *cannedRows) Close() error      { return nil }
+
+func (r *cannedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
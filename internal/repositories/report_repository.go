@@ -3,11 +3,13 @@ package repositories
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AttendanceReportRow struct {
 	Date      time.Time
+	UserID    uuid.UUID
 	Email     string
 	ClockIn   *time.Time
 	ClockOut  *time.Time
@@ -31,6 +33,7 @@ func (r *reportRepository) AttendanceReport(from, to time.Time) ([]AttendanceRep
 	err := r.db.Raw(`
 		SELECT 
 			a.work_date AS date,
+			u.id AS user_id,
 			u.email,
 			a.clock_in,
 			a.clock_out
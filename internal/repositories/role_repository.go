@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	FindByName(name string) (*models.Role, error)
+	Create(role *models.Role, permissions []string) error
+	Update(role *models.Role, permissions []string) error
+	Delete(id uuid.UUID) error
+	CountUsers(name string) (int64, error)
+	ListPermissions() ([]models.Permission, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").First(&role, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(role *models.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Create(role).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role, permissions)
+	})
+}
+
+// Update saves the role and replaces its permission set atomically.
+func (r *roleRepository) Update(role *models.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role, permissions)
+	})
+}
+
+func (r *roleRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Role{}, "id = ?", id).Error
+	})
+}
+
+func (r *roleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+func (r *roleRepository) ListPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func replaceRolePermissions(tx *gorm.DB, role *models.Role, permissions []string) error {
+	if err := tx.Where("role_id = ?", role.ID).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+
+	grants := make([]models.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		grants = append(grants, models.RolePermission{RoleID: role.ID, PermissionName: permission})
+	}
+	if len(grants) > 0 {
+		if err := tx.Omit("Permission").Create(&grants).Error; err != nil {
+			return err
+		}
+	}
+	role.Permissions = grants
+	return nil
+}
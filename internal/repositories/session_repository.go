@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type SessionRepository interface {
+	Create(session *models.Session) error
+	FindByID(id uuid.UUID) (*models.Session, error)
+	ListActiveForUser(userID uuid.UUID, now time.Time) ([]models.Session, error)
+	Touch(id uuid.UUID, at time.Time, ipAddress, userAgent string, expiresAt *time.Time) error
+	Revoke(id uuid.UUID, revokedBy *uuid.UUID, at time.Time) error
+	RevokeAllForUser(userID uuid.UUID, at time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) FindByID(id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActiveForUser(userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch records activity. Empty ipAddress/userAgent and a nil expiresAt leave
+// the stored values unchanged.
+func (r *sessionRepository) Touch(id uuid.UUID, at time.Time, ipAddress, userAgent string, expiresAt *time.Time) error {
+	updates := map[string]interface{}{"last_seen_at": at}
+	if ipAddress != "" {
+		updates["ip_address"] = ipAddress
+	}
+	if userAgent != "" {
+		updates["user_agent"] = userAgent
+	}
+	if expiresAt != nil {
+		updates["expires_at"] = *expiresAt
+	}
+	return r.db.Model(&models.Session{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *sessionRepository) Revoke(id uuid.UUID, revokedBy *uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": at, "revoked_by": revokedBy}).Error
+}
+
+func (r *sessionRepository) RevokeAllForUser(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+)
+
+type SigningKeyRepository interface {
+	Rotate(key *models.SigningKey, staleBefore time.Time) (bool, error)
+	ListUsable(retiredAfter time.Time) ([]models.SigningKey, error)
+	FindOldest() (*models.SigningKey, error)
+}
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+// Rotate stores key and retires every other active key, unless an active key
+// created after staleBefore already exists. The advisory lock serializes
+// instances rotating at the same moment, so only the first one creates a key;
+// the others report false and pick it up on their next reload.
+func (r *signingKeyRepository) Rotate(key *models.SigningKey, staleBefore time.Time) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('signing_keys.rotate'))").Error; err != nil {
+			return err
+		}
+
+		var fresh int64
+		err := tx.Model(&models.SigningKey{}).
+			Where("retired_at IS NULL AND created_at > ?", staleBefore).
+			Count(&fresh).Error
+		if err != nil || fresh > 0 {
+			return err
+		}
+
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SigningKey{}).
+			Where("id <> ? AND retired_at IS NULL", key.ID).
+			Update("retired_at", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// ListUsable returns active keys plus keys retired after retiredAfter,
+// newest first.
+func (r *signingKeyRepository) ListUsable(retiredAfter time.Time) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	err := r.db.
+		Where("retired_at IS NULL OR retired_at > ?", retiredAfter).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepository) FindOldest() (*models.SigningKey, error) {
+	var key models.SigningKey
+	if err := r.db.Order("created_at ASC").First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"go-backend/internal/models"
+)
+
+func TestRotateSigningKeyHoldsLockAndRetiresOthers(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewSigningKeyRepository(db)
+
+	key := &models.SigningKey{KID: "kid", Algorithm: "EdDSA", PrivateKeyEncrypted: "secret"}
+	rotated, err := repo.Rotate(key, time.Now().UTC().Add(-time.Hour))
+	if err != nil || !rotated {
+		t.Fatalf("expected the key to be created, got %v, %v", rotated, err)
+	}
+
+	want := []string{"BEGIN", "SELECT pg_advisory_xact_lock", "SELECT count(*)", `INSERT INTO "signing_keys"`, `UPDATE "signing_keys"`, "COMMIT"}
+	got := recorder.queries()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statements, got %q", len(want), got)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(got[i], prefix) {
+			t.Fatalf("statement %d: expected %s, got %q", i, prefix, got[i])
+		}
+	}
+	if check := recorder.find("SELECT count(*)")[0]; !strings.Contains(check.query, "retired_at IS NULL AND created_at >") {
+		t.Fatalf("expected the fresh key check to look at active keys only: %s", check.query)
+	}
+	if retire := recorder.find(`UPDATE "signing_keys"`)[0]; !strings.Contains(retire.query, "id <> $3 AND retired_at IS NULL") {
+		t.Fatalf("expected every other active key to be retired: %s", retire.query)
+	}
+}
+
+func TestRotateSigningKeySkipsWhenAFreshKeyExists(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewSigningKeyRepository(db)
+	recorder.respond("SELECT count(*)", []string{"count"}, []driver.Value{int64(1)})
+
+	key := &models.SigningKey{KID: "kid", Algorithm: "EdDSA", PrivateKeyEncrypted: "secret"}
+	rotated, err := repo.Rotate(key, time.Now().UTC().Add(-time.Hour))
+	if err != nil || rotated {
+		t.Fatalf("expected the rotation to be skipped, got %v, %v", rotated, err)
+	}
+	if inserts := recorder.find(`INSERT INTO "signing_keys"`); len(inserts) != 0 {
+		t.Fatalf("expected no key to be created, got %q", recorder.queries())
+	}
+}
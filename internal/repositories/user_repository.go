@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
@@ -13,6 +15,8 @@ type UserRepository interface {
 	Update(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uuid.UUID) (*models.User, error)
+	RevokeTokens(id uuid.UUID, at time.Time) error
+	UpdateRole(id uuid.UUID, role string) error
 }
 
 // Implementation
@@ -25,11 +29,30 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createUser(tx, user)
+	})
+}
+
+// createUser inserts user. GORM writes the column default (true) in place of
+// a false IsActive, so inactive users such as invitees are switched off again
+// before the transaction commits.
+func createUser(tx *gorm.DB, user *models.User) error {
+	active := user.IsActive
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	if active {
+		return nil
+	}
+	user.IsActive = false
+	return tx.Model(&models.User{}).Where("id = ?", user.ID).Update("is_active", false).Error
 }
 
+// Update saves the user. The permission version is left alone so a stale
+// copy cannot roll back a concurrent role change.
 func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit("permission_version").Save(user).Error
 }
 
 func (r *userRepository) FindByEmail(email string) (*models.User, error) {
@@ -47,3 +70,16 @@ func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) RevokeTokens(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("tokens_revoked_at", at).Error
+}
+
+// UpdateRole changes the role and bumps the permission version in one
+// statement, so outstanding tokens see the change on their next request.
+func (r *userRepository) UpdateRole(id uuid.UUID, role string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"role":               role,
+		"permission_version": gorm.Expr("permission_version + 1"),
+	}).Error
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"testing"
+
+	"go-backend/internal/models"
+)
+
+func TestCreateKeepsInvitedUsersInactive(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewUserRepository(db)
+
+	user := &models.User{Email: "invitee@example.com", Role: "employee", IsActive: false}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got := recorder.queries()
+	if len(got) != 4 || got[0] != "BEGIN" || got[3] != "COMMIT" {
+		t.Fatalf("expected the insert and the update in one transaction, got %q", got)
+	}
+	updates := recorder.find(`UPDATE "users"`)
+	if len(updates) != 1 {
+		t.Fatalf("expected is_active to be written explicitly, got %q", got)
+	}
+	if active, ok := updates[0].arg("is_active"); !ok || active != false {
+		t.Fatalf("expected is_active to be set to false, got %v", active)
+	}
+	if user.IsActive {
+		t.Fatal("expected the returned user to stay inactive")
+	}
+}
+
+func TestCreateLeavesActiveUsersToTheDefault(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewUserRepository(db)
+
+	if err := repo.Create(&models.User{Email: "admin@example.com", Role: "admin", IsActive: true}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if updates := recorder.find(`UPDATE "users"`); len(updates) != 0 {
+		t.Fatalf("expected a single insert, got %q", recorder.queries())
+	}
+}
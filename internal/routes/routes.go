@@ -1,19 +1,22 @@
 package routes
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"go-backend/internal/authz"
 	"go-backend/internal/handlers"
+	"go-backend/internal/mailer"
 	"go-backend/internal/middleware"
+	"go-backend/internal/oidc"
 	"go-backend/internal/repositories"
 	"go-backend/internal/services"
+	"go-backend/pkg/utils"
 )
 
 func RegisterRoutes(router *gin.Engine, db *gorm.DB, jwtSecret string) {
-	api := router.Group("/api")
-
 	// ===== Repositories =====
 	userRepo := repositories.NewUserRepository(db)
 	employeeRepo := repositories.NewEmployeeRepository(db)
@@ -24,24 +27,77 @@ func RegisterRoutes(router *gin.Engine, db *gorm.DB, jwtSecret string) {
 	auditRepo := repositories.NewAuditRepository(db)
 	leaveRepo := repositories.NewLeaveRepository(db)
 	payslipRepo := repositories.NewPayslipRepository(db)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
+	revokedTokenRepo := repositories.NewRevokedTokenRepository(db)
+	loginThrottleRepo := repositories.NewLoginThrottleRepository(db)
+	passwordResetRepo := repositories.NewPasswordResetRepository(db)
+	mfaRepo := repositories.NewMFARepository(db)
+	signingKeyRepo := repositories.NewSigningKeyRepository(db)
+	oidcRepo := repositories.NewOIDCRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	passwordHistoryRepo := repositories.NewPasswordHistoryRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+	invitationRepo := repositories.NewInvitationRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+	permissionGrantRepo := repositories.NewPermissionGrantRepository(db)
+	employmentEventRepo := repositories.NewEmploymentEventRepository(db)
+	offboardingRepo := repositories.NewOffboardingRepository(db)
+
+	mail := mailer.FromEnv()
 
 	// ===== Services =====
+	tokenKeySvc, err := services.NewTokenKeyService(signingKeyRepo, services.TokenKeyConfigFromEnv(jwtSecret))
+	if err != nil {
+		log.Fatalf("Signing key setup failed: %v", err)
+	}
 	auditSvc := services.NewAuditService(auditRepo)
-	authSvc := services.NewAuthService(userRepo, employeeRepo, jwtSecret)
-	employeeSvc := services.NewEmployeeService(userRepo, employeeRepo, auditSvc)
-	departmentSvc := services.NewDepartmentService(departmentRepo)
-	profileSvc := services.NewProfileService(userRepo, employeeRepo, auditSvc)
+	roleSvc := services.NewRoleService(roleRepo, auditSvc)
+	authz.SetRoleStore(roleSvc)
+	permissionGrantSvc := services.NewPermissionGrantService(permissionGrantRepo, userRepo, auditSvc)
+	denylist := services.NewTokenDenylist(revokedTokenRepo, userRepo, sessionRepo)
+	loginThrottleSvc := services.NewLoginThrottleService(loginThrottleRepo, userRepo, auditSvc, services.LoginThrottlePolicyFromEnv())
+	mfaSvc := services.NewMFAService(mfaRepo, userRepo, permissionGrantSvc, auditSvc, utils.GetEnv("MFA_ENCRYPTION_KEY", jwtSecret))
+	passwordSvc := services.NewPasswordService(passwordHistoryRepo, userRepo, utils.PasswordPolicyFromEnv())
+	authSvc := services.NewAuthService(userRepo, employeeRepo, refreshTokenRepo, sessionRepo, auditSvc, denylist, loginThrottleSvc, mfaSvc, tokenKeySvc, passwordSvc)
+	employmentHistorySvc := services.NewEmploymentHistoryService(employmentEventRepo, employeeRepo, departmentRepo, auditSvc, authSvc)
+	invitationSvc := services.NewInvitationService(invitationRepo, userRepo, employeeRepo, passwordSvc, auditSvc, mail)
+	employeeSvc := services.NewEmployeeService(userRepo, employeeRepo, auditSvc, employmentHistorySvc, invitationSvc)
+	offboardingSvc := services.NewOffboardingService(offboardingRepo, employeeRepo, departmentRepo, employmentHistorySvc, auditSvc)
+	employeeImportSvc := services.NewEmployeeImportService(userRepo, employeeRepo, departmentRepo, invitationSvc)
+	orgChartSvc := services.NewOrgChartService(employeeRepo)
+	departmentSvc := services.NewDepartmentService(departmentRepo, userRepo, employeeRepo, auditSvc)
+	profileSvc := services.NewProfileService(userRepo, employeeRepo, auditSvc, passwordSvc)
 	attendanceSvc := services.NewAttendanceService(attendanceRepo, employeeRepo, auditSvc)
 	analyticsSvc := services.NewAnalyticsService(analyticsRepo)
 	reportSvc := services.NewReportService(reportRepo)
-	leaveSvc := services.NewLeaveService(leaveRepo, auditSvc)
+	leaveSvc := services.NewLeaveService(leaveRepo, employeeRepo, auditSvc)
 	notificationSvc := services.NewNotificationService(leaveRepo)
 	payslipSvc := services.NewPayslipService(payslipRepo, employeeRepo, auditSvc)
+	passwordResetSvc := services.NewPasswordResetService(passwordResetRepo, loginThrottleRepo, userRepo, auditSvc, authSvc, passwordSvc, mail)
+	oidcCfg := services.OIDCConfigFromEnv()
+	var oidcProvider *oidc.Provider
+	if oidcCfg.Enabled() {
+		oidcProvider = oidc.NewProvider(oidcCfg.Provider, nil)
+	}
+	sessionSvc := services.NewSessionService(sessionRepo, refreshTokenRepo, denylist, auditSvc)
+	apiKeySvc := services.NewAPIKeyService(apiKeyRepo, userRepo, auditSvc)
+	impersonationSvc := services.NewImpersonationService(userRepo, employeeRepo, denylist, tokenKeySvc, auditSvc)
+	ssoSvc := services.NewSSOService(oidcCfg, oidcProvider, oidcRepo, userRepo, employeeRepo, authSvc, auditSvc, employmentHistorySvc)
 	// Add other services as needed
 
 	// ===== Handlers =====
 	authHandler := handlers.NewAuthHandler(authSvc)
+	ssoHandler := handlers.NewSSOHandler(ssoSvc)
+	serviceAccountHandler := handlers.NewServiceAccountHandler(apiKeySvc)
+	sessionHandler := handlers.NewSessionHandler(sessionSvc)
+	invitationHandler := handlers.NewInvitationHandler(invitationSvc)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationSvc)
+	roleHandler := handlers.NewRoleHandler(roleSvc)
 	employeeHandler := handlers.NewEmployeeHandler(employeeSvc)
+	employeeImportHandler := handlers.NewEmployeeImportHandler(employeeImportSvc)
+	orgChartHandler := handlers.NewOrgChartHandler(orgChartSvc)
+	employmentHistoryHandler := handlers.NewEmploymentHistoryHandler(employmentHistorySvc)
+	offboardingHandler := handlers.NewOffboardingHandler(offboardingSvc)
 	departmentHandler := handlers.NewDepartmentHandler(departmentSvc)
 	profileHandler := handlers.NewProfileHandler(profileSvc)
 	attendanceHandler := handlers.NewAttendanceHandler(attendanceSvc)
@@ -51,85 +107,154 @@ func RegisterRoutes(router *gin.Engine, db *gorm.DB, jwtSecret string) {
 	leaveHandler := handlers.NewLeaveHandler(leaveSvc)
 	notificationHandler := handlers.NewNotificationHandler(notificationSvc)
 	payslipHandler := handlers.NewPayslipHandler(payslipSvc)
+	userAdminHandler := handlers.NewUserAdminHandler(loginThrottleSvc, mfaSvc)
+	mfaHandler := handlers.NewMFAHandler(mfaSvc)
+	jwksHandler := handlers.NewJWKSHandler(tokenKeySvc)
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetSvc)
+	permissionGrantHandler := handlers.NewPermissionGrantHandler(permissionGrantSvc)
+	accessHandler := handlers.NewAccessHandler()
+
+	// ===== Route table =====
+	table := []Route{
+		// Well-known
+		{Method: "GET", Path: "/.well-known/jwks.json", Handler: jwksHandler.JWKS, Public: true},
+
+		// Auth
+		{Method: "POST", Path: "/api/auth/login", Handler: authHandler.Login, Public: true},
+		{Method: "POST", Path: "/api/auth/refresh", Handler: authHandler.Refresh, Public: true},
+		{Method: "POST", Path: "/api/auth/logout", Handler: authHandler.Logout},
+		{Method: "POST", Path: "/api/auth/forgot-password", Handler: passwordResetHandler.ForgotPassword, Public: true},
+		{Method: "POST", Path: "/api/auth/reset-password", Handler: passwordResetHandler.ResetPassword, Public: true},
+		{Method: "POST", Path: "/api/auth/mfa/enroll", Handler: authHandler.MFAEnroll, Public: true},
+		{Method: "POST", Path: "/api/auth/mfa/verify", Handler: authHandler.MFAVerify, Public: true},
+		{Method: "POST", Path: "/api/auth/password/change", Handler: authHandler.ChangeExpiredPassword, Public: true},
+		{Method: "POST", Path: "/api/auth/accept-invite", Handler: invitationHandler.Accept, Public: true},
+		{Method: "GET", Path: "/api/auth/oidc/authorize", Handler: ssoHandler.Authorize, Public: true},
+		{Method: "POST", Path: "/api/auth/oidc/callback", Handler: ssoHandler.Callback, Public: true},
+
+		// Ending impersonation is a write, so it sits outside the read-only guard
+		{Method: "DELETE", Path: "/api/impersonation", Handler: impersonationHandler.Stop, Unguarded: true},
+
+		// Introspection
+		{Method: "GET", Path: "/api/me/permissions", Handler: accessHandler.MyPermissions, Scoped: true},
+
+		// Employees
+		{Method: "GET", Path: "/api/employees/count", Handler: employeeHandler.CountEmployees, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/employees/", Handler: employeeHandler.ListEmployees, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/employees/", Handler: employeeHandler.CreateEmployee, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/employees/import", Handler: employeeImportHandler.Import, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "PUT", Path: "/api/employees/:id", Handler: employeeHandler.UpdateEmployee, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "DELETE", Path: "/api/employees/:id", Handler: employeeHandler.DeactivateEmployee, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "PUT", Path: "/api/employees/:id/manager", Handler: employeeHandler.SetManager, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/employees/as-of", Handler: employmentHistoryHandler.DirectoryAsOf, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/employees/:id/history", Handler: employmentHistoryHandler.History, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/employees/:id/state", Handler: employmentHistoryHandler.StateAsOf, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/employees/:id/events", Handler: employmentHistoryHandler.Record, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "DELETE", Path: "/api/employees/:id/events/:event_id", Handler: employmentHistoryHandler.Cancel, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/employees/:id/rehire", Handler: employmentHistoryHandler.Rehire, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/employees/:id/termination", Handler: offboardingHandler.Get, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/employees/:id/termination", Handler: offboardingHandler.Terminate, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "DELETE", Path: "/api/employees/:id/termination", Handler: offboardingHandler.Cancel, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/offboarding/tasks", Handler: offboardingHandler.ListTasks, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/offboarding/tasks/:id/complete", Handler: offboardingHandler.CompleteTask, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/offboarding/templates", Handler: offboardingHandler.ListTemplates, Permissions: []string{authz.PermManageDepartments}},
+		{Method: "POST", Path: "/api/offboarding/templates", Handler: offboardingHandler.CreateTemplate, Permissions: []string{authz.PermManageDepartments}},
+		{Method: "DELETE", Path: "/api/offboarding/templates/:id", Handler: offboardingHandler.DeleteTemplate, Permissions: []string{authz.PermManageDepartments}},
+		{Method: "GET", Path: "/api/employees/:id/reports", Handler: orgChartHandler.Reports, Permissions: []string{authz.PermViewOrgChart}},
+		{Method: "GET", Path: "/api/org-chart", Handler: orgChartHandler.Get, Permissions: []string{authz.PermViewOrgChart}},
+		{Method: "GET", Path: "/api/employees/invites", Handler: invitationHandler.List, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "GET", Path: "/api/employees/:id/invite", Handler: invitationHandler.Status, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "POST", Path: "/api/employees/:id/invite", Handler: invitationHandler.Resend, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+		{Method: "DELETE", Path: "/api/employees/:id/invite", Handler: invitationHandler.Revoke, Permissions: []string{authz.PermManageEmployees}, Scoped: true},
+
+		// Departments
+		{Method: "POST", Path: "/api/departments/", Handler: departmentHandler.Create, Permissions: []string{authz.PermManageDepartments}},
+		{Method: "GET", Path: "/api/departments/", Handler: departmentHandler.List, Permissions: []string{authz.PermViewDepartments}},
+		{Method: "GET", Path: "/api/departments/:id/managers", Handler: departmentHandler.ListManagers, Permissions: []string{authz.PermManageDepartments}},
+		{Method: "PUT", Path: "/api/departments/:id/managers", Handler: departmentHandler.SetManagers, Permissions: []string{authz.PermManageDepartments}},
+
+		// Profile
+		{Method: "GET", Path: "/api/profile/", Handler: profileHandler.GetProfile, Permissions: []string{authz.PermViewProfile}},
+		{Method: "GET", Path: "/api/profile/mfa", Handler: mfaHandler.Status, Permissions: []string{authz.PermViewProfile}},
+		{Method: "GET", Path: "/api/profile/sessions", Handler: sessionHandler.ListMine, Permissions: []string{authz.PermViewProfile}},
+		{Method: "DELETE", Path: "/api/profile/sessions/:id", Handler: sessionHandler.RevokeMine, Permissions: []string{authz.PermViewProfile}},
+		{Method: "PUT", Path: "/api/profile/", Handler: profileHandler.UpdateProfile, Permissions: []string{authz.PermViewProfile, authz.PermUpdateProfile}},
+		{Method: "POST", Path: "/api/profile/mfa/enroll", Handler: mfaHandler.Enroll, Permissions: []string{authz.PermViewProfile, authz.PermUpdateProfile}},
+		{Method: "POST", Path: "/api/profile/mfa/confirm", Handler: mfaHandler.Confirm, Permissions: []string{authz.PermViewProfile, authz.PermUpdateProfile}},
+		{Method: "POST", Path: "/api/profile/mfa/recovery-codes", Handler: mfaHandler.RegenerateRecoveryCodes, Permissions: []string{authz.PermViewProfile, authz.PermUpdateProfile}},
+		{Method: "DELETE", Path: "/api/profile/mfa", Handler: mfaHandler.Disable, Permissions: []string{authz.PermViewProfile, authz.PermUpdateProfile}},
+
+		// Attendance
+		{Method: "POST", Path: "/api/attendance/clock-in", Handler: attendanceHandler.ClockIn, Permissions: []string{authz.PermClockAttendance}},
+		{Method: "POST", Path: "/api/attendance/clock-out", Handler: attendanceHandler.ClockOut, Permissions: []string{authz.PermClockAttendance}},
+
+		// Analytics
+		{Method: "GET", Path: "/api/analytics/daily-summary", Handler: analyticsHandler.DailySummary, Permissions: []string{authz.PermViewAnalytics}},
+		{Method: "GET", Path: "/api/analytics/attendance-trend", Handler: analyticsHandler.Trend, Permissions: []string{authz.PermViewAnalytics}},
+		{Method: "GET", Path: "/api/analytics/absentees", Handler: analyticsHandler.Absentees, Permissions: []string{authz.PermViewAnalytics}},
+
+		// Reports
+		{Method: "GET", Path: "/api/reports/attendance/csv", Handler: reportHandler.ExportCSV, Permissions: []string{authz.PermExportReports}},
+		{Method: "GET", Path: "/api/reports/attendance/pdf", Handler: reportHandler.ExportPDF, Permissions: []string{authz.PermExportReports}},
+
+		// Audit logs
+		{Method: "GET", Path: "/api/audit-logs/", Handler: auditHandler.List, Permissions: []string{authz.PermViewAuditLogs}},
+		{Method: "GET", Path: "/api/audit-logs/csv", Handler: auditHandler.ExportCSV, Permissions: []string{authz.PermViewAuditLogs}},
+		{Method: "GET", Path: "/api/audit-logs/pdf", Handler: auditHandler.ExportPDF, Permissions: []string{authz.PermViewAuditLogs}},
+
+		// Leaves
+		{Method: "POST", Path: "/api/leaves/", Handler: leaveHandler.Request, Permissions: []string{authz.PermRequestLeave}},
+		{Method: "GET", Path: "/api/leaves/mine", Handler: leaveHandler.Mine, Permissions: []string{authz.PermRequestLeave, authz.PermViewOwnLeaves}},
+		{Method: "PUT", Path: "/api/leaves/:id/cancel", Handler: leaveHandler.CancelMine, Permissions: []string{authz.PermRequestLeave, authz.PermViewOwnLeaves}},
+		{Method: "GET", Path: "/api/leaves/", Handler: leaveHandler.List, Permissions: []string{authz.PermRequestLeave, authz.PermViewOwnLeaves, authz.PermReviewLeaves}, Scoped: true},
+		{Method: "GET", Path: "/api/leaves/assigned", Handler: leaveHandler.Assigned, Permissions: []string{authz.PermRequestLeave, authz.PermViewOwnLeaves, authz.PermReviewLeaves}, Scoped: true},
+		{Method: "PUT", Path: "/api/leaves/:id/review", Handler: leaveHandler.Review, Permissions: []string{authz.PermRequestLeave, authz.PermViewOwnLeaves, authz.PermReviewLeaves}, Scoped: true},
+
+		// Notifications
+		{Method: "GET", Path: "/api/notifications/", Handler: notificationHandler.List, Permissions: []string{authz.PermViewNotifications}, Scoped: true},
+
+		// Payslips
+		{Method: "GET", Path: "/api/payslips/mine", Handler: payslipHandler.Mine, Permissions: []string{authz.PermViewOwnPayslips}, Scoped: true},
+		{Method: "GET", Path: "/api/payslips/:id", Handler: payslipHandler.GetByID, Permissions: []string{authz.PermViewOwnPayslips}, Scoped: true},
+		{Method: "GET", Path: "/api/payslips/:id/pdf", Handler: payslipHandler.DownloadPDF, Permissions: []string{authz.PermViewOwnPayslips}, Scoped: true},
+		{Method: "GET", Path: "/api/payslips/", Handler: payslipHandler.List, Permissions: []string{authz.PermViewOwnPayslips, authz.PermManagePayslips}, Scoped: true},
+		{Method: "POST", Path: "/api/payslips/", Handler: payslipHandler.Generate, Permissions: []string{authz.PermViewOwnPayslips, authz.PermManagePayslips}, Scoped: true},
+
+		// User administration
+		{Method: "POST", Path: "/api/admin/users/:id/unlock", Handler: userAdminHandler.Unlock, Permissions: []string{authz.PermManageUsers}},
+		{Method: "DELETE", Path: "/api/admin/users/:id/mfa", Handler: userAdminHandler.ResetMFA, Permissions: []string{authz.PermManageUsers}},
+		{Method: "GET", Path: "/api/admin/users/:id/sessions", Handler: sessionHandler.ListForUser, Permissions: []string{authz.PermManageUsers}},
+		{Method: "DELETE", Path: "/api/admin/users/:id/sessions/:sessionId", Handler: sessionHandler.RevokeForUser, Permissions: []string{authz.PermManageUsers}},
+		{Method: "POST", Path: "/api/admin/users/:id/impersonate", Handler: impersonationHandler.Start, Permissions: []string{authz.PermManageUsers, authz.PermImpersonateUsers}},
+
+		// Service accounts & API keys
+		{Method: "GET", Path: "/api/admin/service-accounts/", Handler: serviceAccountHandler.List, Permissions: []string{authz.PermManageUsers}},
+		{Method: "POST", Path: "/api/admin/service-accounts/", Handler: serviceAccountHandler.Create, Permissions: []string{authz.PermManageUsers}},
+		{Method: "DELETE", Path: "/api/admin/service-accounts/:id", Handler: serviceAccountHandler.Deactivate, Permissions: []string{authz.PermManageUsers}},
+		{Method: "GET", Path: "/api/admin/service-accounts/:id/keys", Handler: serviceAccountHandler.ListKeys, Permissions: []string{authz.PermManageUsers}},
+		{Method: "POST", Path: "/api/admin/service-accounts/:id/keys", Handler: serviceAccountHandler.CreateKey, Permissions: []string{authz.PermManageUsers}},
+		{Method: "DELETE", Path: "/api/admin/service-accounts/:id/keys/:keyId", Handler: serviceAccountHandler.RevokeKey, Permissions: []string{authz.PermManageUsers}},
+
+		// Roles & permissions
+		{Method: "GET", Path: "/api/admin/roles/", Handler: roleHandler.List, Permissions: []string{authz.PermManageRoles}},
+		{Method: "GET", Path: "/api/admin/roles/:name", Handler: roleHandler.Get, Permissions: []string{authz.PermManageRoles}},
+		{Method: "POST", Path: "/api/admin/roles/", Handler: roleHandler.Create, Permissions: []string{authz.PermManageRoles}},
+		{Method: "PUT", Path: "/api/admin/roles/:name", Handler: roleHandler.Update, Permissions: []string{authz.PermManageRoles}},
+		{Method: "DELETE", Path: "/api/admin/roles/:name", Handler: roleHandler.Delete, Permissions: []string{authz.PermManageRoles}},
+		{Method: "GET", Path: "/api/admin/permissions", Handler: roleHandler.ListPermissions, Permissions: []string{authz.PermManageRoles}},
+		{Method: "GET", Path: "/api/admin/route-permissions", Handler: accessHandler.RoutePermissions, Permissions: []string{authz.PermManageRoles}},
+
+		// Temporary grants & delegations
+		{Method: "GET", Path: "/api/admin/grants/", Handler: permissionGrantHandler.List, Permissions: []string{authz.PermManageGrants}},
+		{Method: "POST", Path: "/api/admin/grants/", Handler: permissionGrantHandler.Create, Permissions: []string{authz.PermManageGrants}},
+		{Method: "DELETE", Path: "/api/admin/grants/:id", Handler: permissionGrantHandler.Revoke, Permissions: []string{authz.PermManageGrants}},
+	}
 
-	// ===== Auth Routes =====
-	auth := api.Group("/auth")
-	auth.POST("/login", authHandler.Login)
-	auth.POST("/refresh", authHandler.Refresh)
-
-	// ===== Protected Routes =====
-	protected := api.Group("/")
-	protected.Use(middleware.AuthMiddleware(jwtSecret))
-	protected.Use(middleware.AuditAuthorizationFailures(auditSvc))
-
-	// Employees
-	employees := protected.Group("/employees")
-	employees.Use(middleware.RequirePermissions(authz.PermManageEmployees))
-	employees.GET("/count", employeeHandler.CountEmployees)
-	employees.GET("/", employeeHandler.ListEmployees)
-	employees.POST("/", employeeHandler.CreateEmployee)
-	employees.PUT("/:id", employeeHandler.UpdateEmployee)
-	employees.DELETE("/:id", employeeHandler.DeactivateEmployee)
-
-	// Departments
-	departments := protected.Group("/departments")
-	departments.POST("/", middleware.RequirePermissions(authz.PermManageDepartments), departmentHandler.Create)
-	departments.GET("/", middleware.RequirePermissions(authz.PermViewDepartments), departmentHandler.List)
-
-	// Profile
-	profile := protected.Group("/profile")
-	profile.Use(middleware.RequirePermissions(authz.PermViewProfile))
-	profile.GET("/", profileHandler.GetProfile)
-	profile.Use(middleware.RequirePermissions(authz.PermUpdateProfile))
-	profile.PUT("/", profileHandler.UpdateProfile)
-
-	// Attendance
-	attendance := protected.Group("/attendance")
-	attendance.Use(middleware.RequirePermissions(authz.PermClockAttendance))
-	attendance.POST("/clock-in", attendanceHandler.ClockIn)
-	attendance.POST("/clock-out", attendanceHandler.ClockOut)
-
-	// Analytics
-	analytics := protected.Group("/analytics")
-	analytics.Use(middleware.RequirePermissions(authz.PermViewAnalytics))
-	analytics.GET("/daily-summary", analyticsHandler.DailySummary)
-	analytics.GET("/attendance-trend", analyticsHandler.Trend)
-	analytics.GET("/absentees", analyticsHandler.Absentees)
-
-	// Reports
-	reports := protected.Group("/reports")
-	reports.Use(middleware.RequirePermissions(authz.PermExportReports))
-	reports.GET("/attendance/csv", reportHandler.ExportCSV)
-	reports.GET("/attendance/pdf", reportHandler.ExportPDF)
-
-	// Audit logs
-	audit := protected.Group("/audit-logs")
-	audit.Use(middleware.RequirePermissions(authz.PermViewAuditLogs))
-	audit.GET("/", auditHandler.List)
-	audit.GET("/csv", auditHandler.ExportCSV)
-	audit.GET("/pdf", auditHandler.ExportPDF)
-
-	// Leaves
-	leaves := protected.Group("/leaves")
-	leaves.Use(middleware.RequirePermissions(authz.PermRequestLeave))
-	leaves.POST("/", leaveHandler.Request)
-	leaves.Use(middleware.RequirePermissions(authz.PermViewOwnLeaves))
-	leaves.GET("/mine", leaveHandler.Mine)
-	leaves.PUT("/:id/cancel", leaveHandler.CancelMine)
-	leaves.GET("/", middleware.RequirePermissions(authz.PermReviewLeaves), leaveHandler.List)
-	leaves.PUT("/:id/review", middleware.RequirePermissions(authz.PermReviewLeaves), leaveHandler.Review)
-
-	// Notifications
-	notifications := protected.Group("/notifications")
-	notifications.Use(middleware.RequirePermissions(authz.PermViewNotifications))
-	notifications.GET("/", notificationHandler.List)
-
-	// Payslips
-	payslips := protected.Group("/payslips")
-	payslips.Use(middleware.RequirePermissions(authz.PermViewOwnPayslips))
-	payslips.GET("/mine", payslipHandler.Mine)
-	payslips.GET("/:id", payslipHandler.GetByID)
-	payslips.GET("/:id/pdf", payslipHandler.DownloadPDF)
-	payslips.GET("/", middleware.RequirePermissions(authz.PermManagePayslips), payslipHandler.List)
-	payslips.POST("/", middleware.RequirePermissions(authz.PermManagePayslips), payslipHandler.Generate)
+	registerTable(router, table, routeMiddleware{
+		auth:          middleware.AuthMiddleware(tokenKeySvc, denylist, apiKeySvc, permissionGrantSvc),
+		auditFailures: middleware.AuditAuthorizationFailures(auditSvc),
+		impersonation: middleware.ImpersonationGuard(impersonationSvc),
+		scope:         middleware.ResolveScope(departmentSvc),
+	})
+	accessHandler.SetRoutes(describeRoutes(table))
 }
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend/internal/handlers"
+	"go-backend/internal/middleware"
+)
+
+// Route declares one endpoint. The router is built from the route table and
+// /api/admin/route-permissions is generated from the same table, so what is
+// enforced and what is reported cannot drift apart.
+type Route struct {
+	Method  string
+	Path    string
+	Handler gin.HandlerFunc
+
+	// Public routes are served without a token.
+	Public bool
+	// Permissions are all required. A protected route without any is open
+	// to every authenticated caller.
+	Permissions []string
+	// Scoped routes resolve the caller's department scope first.
+	Scoped bool
+	// Unguarded routes stay writable under read-only impersonation.
+	Unguarded bool
+}
+
+// routeMiddleware is the shared chain the table is registered with.
+type routeMiddleware struct {
+	auth          gin.HandlerFunc
+	auditFailures gin.HandlerFunc
+	impersonation gin.HandlerFunc
+	scope         gin.HandlerFunc
+}
+
+func registerTable(router *gin.Engine, table []Route, mw routeMiddleware) {
+	for _, route := range table {
+		router.Handle(route.Method, route.Path, mw.chain(route)...)
+	}
+}
+
+func (mw routeMiddleware) chain(route Route) []gin.HandlerFunc {
+	if route.Public {
+		return []gin.HandlerFunc{route.Handler}
+	}
+
+	chain := []gin.HandlerFunc{mw.auth, mw.auditFailures}
+	if !route.Unguarded {
+		chain = append(chain, mw.impersonation)
+	}
+	if len(route.Permissions) > 0 {
+		chain = append(chain, middleware.RequirePermissions(route.Permissions...))
+	}
+	if route.Scoped {
+		chain = append(chain, mw.scope)
+	}
+	return append(chain, route.Handler)
+}
+
+// describeRoutes lists the table sorted by path and method.
+func describeRoutes(table []Route) []handlers.RoutePermission {
+	described := make([]handlers.RoutePermission, 0, len(table))
+	for _, route := range table {
+		permissions := append([]string{}, route.Permissions...)
+		described = append(described, handlers.RoutePermission{
+			Method:           route.Method,
+			Path:             route.Path,
+			Public:           route.Public,
+			Permissions:      permissions,
+			DepartmentScoped: route.Scoped,
+		})
+	}
+
+	sort.Slice(described, func(i, j int) bool {
+		if described[i].Path != described[j].Path {
+			return described[i].Path < described[j].Path
+		}
+		return described[i].Method < described[j].Method
+	})
+	return described
+}
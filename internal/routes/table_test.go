@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-backend/internal/authz"
+)
+
+func TestRouteTableDrivesRouterAndCatalog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls []string
+	step := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			calls = append(calls, name)
+			if name == "auth" {
+				c.Set("permissions", []string{authz.PermViewProfile})
+			}
+		}
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	table := []Route{
+		{Method: "GET", Path: "/api/profile/", Handler: ok, Permissions: []string{authz.PermViewProfile}, Scoped: true},
+		{Method: "POST", Path: "/api/auth/login", Handler: ok, Public: true},
+		{Method: "GET", Path: "/api/audit-logs/", Handler: ok, Permissions: []string{authz.PermViewAuditLogs}},
+		{Method: "DELETE", Path: "/api/impersonation", Handler: ok, Unguarded: true},
+	}
+	router := gin.New()
+	registerTable(router, table, routeMiddleware{
+		auth:          step("auth"),
+		auditFailures: step("audit"),
+		impersonation: step("guard"),
+		scope:         step("scope"),
+	})
+
+	call := func(method, path string) int {
+		calls = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	if code := call("GET", "/api/profile/"); code != http.StatusOK || len(calls) != 4 || calls[3] != "scope" {
+		t.Fatalf("expected the full chain for a scoped route, got %d %v", code, calls)
+	}
+	if code := call("POST", "/api/auth/login"); code != http.StatusOK || len(calls) != 0 {
+		t.Fatalf("expected a public route to skip authentication, got %d %v", code, calls)
+	}
+	if code := call("GET", "/api/audit-logs/"); code != http.StatusForbidden {
+		t.Fatalf("expected the declared permission to be enforced, got %d", code)
+	}
+	if code := call("DELETE", "/api/impersonation"); code != http.StatusOK || len(calls) != 2 {
+		t.Fatalf("expected an unguarded route to skip the impersonation guard, got %d %v", code, calls)
+	}
+
+	described := describeRoutes(table)
+	if len(described) != len(table) || described[0].Path != "/api/audit-logs/" || !described[1].Public {
+		t.Fatalf("expected the catalog sorted by path, got %+v", described)
+	}
+	if described[3].Path != "/api/profile/" || !described[3].DepartmentScoped || described[3].Permissions[0] != authz.PermViewProfile {
+		t.Fatalf("expected the catalog to mirror the table, got %+v", described[3])
+	}
+}
@@ -22,6 +22,6 @@ func (s *AnalyticsService) Trend(from, to time.Time) ([]repositories.TrendPoint,
 	return s.repo.AttendanceTrend(from, to)
 }
 
-func (s *AnalyticsService) Absentees(date time.Time) ([]string, error) {
+func (s *AnalyticsService) Absentees(date time.Time) ([]repositories.AbsenteeRow, error) {
 	return s.repo.Absentees(date)
 }
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+// apiKeyPrefix marks StaffPoint keys so they are easy to spot in secret
+// scanners and logs.
+const apiKeyPrefix = "sp_"
+
+// apiKeyTouchInterval limits last-used bookkeeping to one write per key per
+// interval instead of one per request.
+const apiKeyTouchInterval = time.Minute
+
+var (
+	ErrInvalidAPIKey          = errors.New("invalid api key")
+	errInvalidServiceAccount  = errors.New("name must be 2-63 lowercase letters, digits or hyphens")
+	errInvalidAPIKeyScope     = errors.New("at least one valid permission is required")
+	errAPIKeyNotGrantable     = errors.New("permission cannot be given to an api key")
+	ErrAPIKeyExceedsCaller    = errors.New("api key would carry a permission you do not hold")
+	serviceAccountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+)
+
+// APIKeyPrincipal is the identity behind an authenticated API key.
+type APIKeyPrincipal struct {
+	KeyID            uuid.UUID
+	ServiceAccountID uuid.UUID
+	UserID           uuid.UUID
+	Permissions      []string
+}
+
+// CreatedAPIKey carries the plaintext key, which is only ever shown once.
+type CreatedAPIKey struct {
+	Key    string         `json:"key"`
+	APIKey *models.APIKey `json:"api_key"`
+}
+
+type APIKeyService interface {
+	CreateServiceAccount(name, description string, adminID uuid.UUID) (*models.ServiceAccount, error)
+	ListServiceAccounts() ([]models.ServiceAccount, error)
+	DeactivateServiceAccount(id, adminID uuid.UUID) error
+
+	CreateKey(serviceAccountID uuid.UUID, name string, permissions []string, expiresAt *time.Time, adminID uuid.UUID, adminPermissions []string) (*CreatedAPIKey, error)
+	ListKeys(serviceAccountID uuid.UUID) ([]models.APIKey, error)
+	RevokeKey(serviceAccountID, keyID, adminID uuid.UUID) error
+
+	Authenticate(rawKey, ipAddress string) (*APIKeyPrincipal, error)
+	RecordRequest(principal *APIKeyPrincipal, method, path string, status int, ipAddress string)
+}
+
+type apiKeyService struct {
+	repo     repositories.APIKeyRepository
+	userRepo repositories.UserRepository
+	auditSvc AuditService
+}
+
+func NewAPIKeyService(
+	repo repositories.APIKeyRepository,
+	userRepo repositories.UserRepository,
+	auditSvc AuditService,
+) APIKeyService {
+	return &apiKeyService{repo: repo, userRepo: userRepo, auditSvc: auditSvc}
+}
+
+// CreateServiceAccount creates the account and its backing user. The user
+// gets a random password and no employee record, so it cannot log in.
+func (s *apiKeyService) CreateServiceAccount(name, description string, adminID uuid.UUID) (*models.ServiceAccount, error) {
+	name = strings.TrimSpace(name)
+	if !serviceAccountNamePattern.MatchString(name) {
+		return nil, errInvalidServiceAccount
+	}
+
+	password, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:        name + "@service-accounts.invalid",
+		PasswordHash: hash,
+		Role:         authz.RoleServiceAccount,
+		IsActive:     true,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	account := &models.ServiceAccount{
+		UserID:      user.ID,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		CreatedBy:   &adminID,
+	}
+	if err := s.repo.CreateServiceAccount(account); err != nil {
+		return nil, err
+	}
+	account.User = *user
+
+	s.auditSvc.Log(adminID, "SERVICE_ACCOUNT_CREATED", "service_account", &account.ID, map[string]interface{}{
+		"name": name,
+	})
+	return account, nil
+}
+
+func (s *apiKeyService) ListServiceAccounts() ([]models.ServiceAccount, error) {
+	return s.repo.ListServiceAccounts()
+}
+
+// DeactivateServiceAccount disables the backing user and revokes every key.
+func (s *apiKeyService) DeactivateServiceAccount(id, adminID uuid.UUID) error {
+	account, err := s.repo.FindServiceAccount(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	user := account.User
+	user.IsActive = false
+	user.DeactivatedAt = &now
+	user.DeactivatedBy = &adminID
+	if err := s.userRepo.Update(&user); err != nil {
+		return err
+	}
+	if err := s.repo.RevokeAllForServiceAccount(account.ID, now); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(adminID, "SERVICE_ACCOUNT_DEACTIVATED", "service_account", &account.ID, map[string]interface{}{
+		"name": account.Name,
+	})
+	return nil
+}
+
+// CreateKey issues a key scoped to permissions, each of which must be a
+// known authz permission that could be granted and that the creator holds
+// in adminPermissions, so a key never outranks whoever made it.
+func (s *apiKeyService) CreateKey(
+	serviceAccountID uuid.UUID,
+	name string,
+	permissions []string,
+	expiresAt *time.Time,
+	adminID uuid.UUID,
+	adminPermissions []string,
+) (*CreatedAPIKey, error) {
+	account, err := s.repo.FindServiceAccount(serviceAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.User.IsActive {
+		return nil, errors.New("service account is deactivated")
+	}
+
+	scope := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !authz.IsPermission(permission) {
+			return nil, errors.New("unknown permission: " + permission)
+		}
+		if !authz.HasPermission(adminPermissions, permission) {
+			return nil, fmt.Errorf("%w: %s", ErrAPIKeyExceedsCaller, permission)
+		}
+		if !authz.IsGrantable(permission) {
+			return nil, fmt.Errorf("%w: %s", errAPIKeyNotGrantable, permission)
+		}
+		if !authz.HasPermission(scope, permission) {
+			scope = append(scope, permission)
+		}
+	}
+	if len(scope) == 0 {
+		return nil, errInvalidAPIKeyScope
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	secret, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	key := &models.APIKey{
+		ServiceAccountID: account.ID,
+		Name:             strings.TrimSpace(name),
+		Prefix:           rawKey[:len(apiKeyPrefix)+8],
+		KeyHash:          utils.HashToken(rawKey),
+		Permissions:      datatypes.NewJSONType(scope),
+		ExpiresAt:        expiresAt,
+		CreatedBy:        &adminID,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(adminID, "API_KEY_CREATED", "api_key", &key.ID, map[string]interface{}{
+		"service_account_id": account.ID.String(),
+		"name":               key.Name,
+		"permissions":        scope,
+	})
+	return &CreatedAPIKey{Key: rawKey, APIKey: key}, nil
+}
+
+func (s *apiKeyService) ListKeys(serviceAccountID uuid.UUID) ([]models.APIKey, error) {
+	return s.repo.ListForServiceAccount(serviceAccountID)
+}
+
+func (s *apiKeyService) RevokeKey(serviceAccountID, keyID, adminID uuid.UUID) error {
+	key, err := s.repo.FindByID(serviceAccountID, keyID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Revoke(key.ID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(adminID, "API_KEY_REVOKED", "api_key", &key.ID, map[string]interface{}{
+		"service_account_id": serviceAccountID.String(),
+	})
+	return nil
+}
+
+// Authenticate resolves a raw key to its principal. Revoked and expired keys
+// and keys of deactivated accounts are rejected.
+func (s *apiKeyService) Authenticate(rawKey, ipAddress string) (*APIKeyPrincipal, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.FindByHash(utils.HashToken(rawKey))
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now().UTC()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	account, err := s.repo.FindServiceAccount(key.ServiceAccountID)
+	if err != nil || !account.User.IsActive || account.User.Role != authz.RoleServiceAccount {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		_ = s.repo.TouchLastUsed(key.ID, now, ipAddress)
+	}
+
+	// Drop permissions that no longer exist in the policy.
+	permissions := make([]string, 0, len(key.Permissions.Data()))
+	for _, permission := range key.Permissions.Data() {
+		if authz.IsPermission(permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return &APIKeyPrincipal{
+		KeyID:            key.ID,
+		ServiceAccountID: account.ID,
+		UserID:           account.UserID,
+		Permissions:      permissions,
+	}, nil
+}
+
+// RecordRequest attributes a call made with an API key to that key.
+func (s *apiKeyService) RecordRequest(principal *APIKeyPrincipal, method, path string, status int, ipAddress string) {
+	s.auditSvc.Log(principal.UserID, "API_KEY_REQUEST", "api_key", &principal.KeyID, map[string]interface{}{
+		"service_account_id": principal.ServiceAccountID.String(),
+		"method":             method,
+		"path":               path,
+		"status":             status,
+		"ip_address":         ipAddress,
+	})
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+)
+
+func TestCreateKeyCannotOutrankItsCreator(t *testing.T) {
+	account := &models.ServiceAccount{Name: "payroll-sync"}
+	account.ID = uuid.New()
+	account.User.IsActive = true
+	repo := &memoryAPIKeyRepo{accounts: map[uuid.UUID]*models.ServiceAccount{account.ID: account}}
+	svc := &apiKeyService{repo: repo, auditSvc: &memoryAudit{}}
+
+	// A non-admin user administrator, such as a custom helpdesk role
+	helpdesk := []string{authz.PermManageUsers, authz.PermViewProfile}
+	for _, permission := range []string{authz.PermManageRoles, authz.PermManageAllDepartments} {
+		_, err := svc.CreateKey(account.ID, "sync", []string{authz.PermViewProfile, permission}, nil, uuid.New(), helpdesk)
+		if !errors.Is(err, ErrAPIKeyExceedsCaller) {
+			t.Fatalf("%s: expected ErrAPIKeyExceedsCaller, got %v", permission, err)
+		}
+	}
+
+	// Even admins cannot hand out permissions that are never granted.
+	admin := authz.PermissionsForRole(authz.RoleAdmin)
+	if _, err := svc.CreateKey(account.ID, "sync", []string{authz.PermImpersonateUsers}, nil, uuid.New(), admin); !errors.Is(err, errAPIKeyNotGrantable) {
+		t.Fatalf("expected errAPIKeyNotGrantable, got %v", err)
+	}
+	if len(repo.keys) != 0 {
+		t.Fatalf("expected no key to be stored, got %d", len(repo.keys))
+	}
+
+	created, err := svc.CreateKey(account.ID, "sync", []string{authz.PermViewProfile}, nil, uuid.New(), helpdesk)
+	if err != nil || created.Key == "" {
+		t.Fatalf("expected a key within the creator's permissions, got %v", err)
+	}
+}
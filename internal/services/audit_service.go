@@ -2,6 +2,7 @@ package services
 
 import (
 	"encoding/json"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -32,7 +33,7 @@ func NewAuditService(repo repositories.AuditRepository) AuditService {
 	return &auditService{repo}
 }
 
-// Log creates an audit entry. Fire-and-forget; errors are ignored
+// Log creates an audit entry. Fire-and-forget; errors are only logged
 func (s *auditService) Log(
 	userID uuid.UUID,
 	action string,
@@ -40,6 +41,22 @@ func (s *auditService) Log(
 	entityID *uuid.UUID,
 	metadata map[string]interface{},
 ) {
+	// Fire-and-forget: do not break business logic if audit fails
+	if err := s.repo.Create(newAuditLog(userID, action, entity, entityID, metadata)); err != nil {
+		log.Printf("writing audit log %s: %v", action, err)
+	}
+}
+
+// newAuditLog builds an entry for callers that must write it themselves,
+// e.g. inside the transaction that makes the change being audited. A zero
+// userID, such as a failed login for an unknown email, is stored as NULL.
+func newAuditLog(
+	userID uuid.UUID,
+	action string,
+	entity string,
+	entityID *uuid.UUID,
+	metadata map[string]interface{},
+) *models.AuditLog {
 	var meta datatypes.JSON = []byte("{}") // default empty JSON
 
 	if metadata != nil {
@@ -49,16 +66,16 @@ func (s *auditService) Log(
 		}
 	}
 
-	log := &models.AuditLog{
-		UserID:   &userID,
+	entry := &models.AuditLog{
 		Action:   action,
 		Entity:   entity,
 		EntityID: entityID,
 		Metadata: meta,
 	}
-
-	// Fire-and-forget: do not break business logic if audit fails
-	_ = s.repo.Create(log)
+	if userID != uuid.Nil {
+		entry.UserID = &userID
+	}
+	return entry
 }
 func (s *auditService) List(limit int) ([]models.AuditLog, error) {
 	return s.repo.List(limit)
@@ -8,90 +8,238 @@ import (
 	"github.com/google/uuid"
 
 	"go-backend/internal/authz"
+	"go-backend/internal/models"
 	"go-backend/internal/repositories"
 	"go-backend/pkg/utils"
 )
 
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+	mfaTokenTTL     = 5 * time.Minute
+
+	passwordChangeTokenTTL = 10 * time.Minute
+)
+
+// ErrAccountDisabled is returned by Login when the credentials are valid but
+// the user has been deactivated.
+var ErrAccountDisabled = errors.New("account disabled")
+
+// LoginResult carries either a token pair or, when a second factor is
+// needed, a short-lived MFA challenge token.
+type LoginResult struct {
+	AccessToken           string   `json:"access_token,omitempty"`
+	RefreshToken          string   `json:"refresh_token,omitempty"`
+	MFARequired           bool     `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string   `json:"mfa_token,omitempty"`
+	RecoveryCodes         []string `json:"recovery_codes,omitempty"`
+
+	// PasswordChangeRequired is set when the password has expired; the
+	// PasswordChangeToken lets the user choose a new one and continue.
+	PasswordChangeRequired bool   `json:"password_change_required,omitempty"`
+	PasswordChangeToken    string `json:"password_change_token,omitempty"`
+}
+
+// AccessRevoker cuts off every session and outstanding token of a user.
+type AccessRevoker interface {
+	RevokeUserAccess(userID uuid.UUID) error
+}
+
 type AuthService struct {
-	userRepo     repositories.UserRepository
-	employeeRepo repositories.EmployeeRepository
-	jwtSecret    string
+	userRepo         repositories.UserRepository
+	employeeRepo     repositories.EmployeeRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	sessionRepo      repositories.SessionRepository
+	auditSvc         AuditService
+	denylist         TokenDenylist
+	throttle         LoginThrottleService
+	mfaSvc           MFAService
+	tokenKeys        TokenKeyService
+	passwordSvc      PasswordService
 }
 
 func NewAuthService(
 	userRepo repositories.UserRepository,
 	employeeRepo repositories.EmployeeRepository,
-	secret string,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	sessionRepo repositories.SessionRepository,
+	auditSvc AuditService,
+	denylist TokenDenylist,
+	throttle LoginThrottleService,
+	mfaSvc MFAService,
+	tokenKeys TokenKeyService,
+	passwordSvc PasswordService,
 ) *AuthService {
 	return &AuthService{
-		userRepo:     userRepo,
-		employeeRepo: employeeRepo,
-		jwtSecret:    secret,
+		userRepo:         userRepo,
+		employeeRepo:     employeeRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		auditSvc:         auditSvc,
+		denylist:         denylist,
+		throttle:         throttle,
+		mfaSvc:           mfaSvc,
+		tokenKeys:        tokenKeys,
+		passwordSvc:      passwordSvc,
 	}
 }
 
-// Login authenticates a user and returns access & refresh tokens
-func (s *AuthService) Login(email, password string) (string, string, error) {
-	// 1. Find user by email
+// Login authenticates a user and returns access & refresh tokens. Users with
+// MFA enabled, or whose role requires it, get an MFA challenge instead.
+func (s *AuthService) Login(email, password, userAgent, ipAddress string) (*LoginResult, error) {
+	// 1. Reject attempts from locked-out accounts or IPs
+	if err := s.throttle.Check(email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	// 2. Find user by email; service accounts authenticate with API keys only
 	user, err := s.userRepo.FindByEmail(email)
-	if err != nil {
-		return "", "", errors.New("invalid credentials")
+	if err != nil || user.Role == authz.RoleServiceAccount {
+		s.throttle.RecordFailure(uuid.Nil, email, ipAddress, "unknown_email")
+		return nil, errors.New("invalid credentials")
 	}
 
-	// 2. Verify password
+	// 3. Verify password
 	if err := utils.CheckPassword(user.PasswordHash, password); err != nil {
-		return "", "", errors.New("invalid credentials")
+		s.throttle.RecordFailure(user.ID, email, ipAddress, "invalid_password")
+		return nil, errors.New("invalid credentials")
+	}
+	s.throttle.RecordSuccess(email)
+
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
+
+	// 4. Expired passwords must be replaced before anything is issued
+	if s.passwordSvc.IsExpired(user) {
+		token, err := s.generatePasswordChangeToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{PasswordChangeRequired: true, PasswordChangeToken: token}, nil
 	}
 
-	// 3. Get employee record (needed for employeeID in JWT)
+	// 5. Second factor, then tokens
+	return s.completeLogin(user, userAgent, ipAddress)
+}
+
+// ChangeExpiredPassword sets a new password for a user whose password has
+// expired, authenticated by the token Login returned, and then continues the
+// login as usual.
+func (s *AuthService) ChangeExpiredPassword(changeToken, newPassword, userAgent, ipAddress string) (*LoginResult, error) {
+	claims, err := s.tokenKeys.Verify(changeToken)
+	if err != nil || claims.TokenType != utils.TokenTypePasswordChange {
+		return nil, errors.New("invalid password change token")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid password change token")
+	}
+
+	// Once the password has been changed the token is spent.
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.IsActive || !s.passwordSvc.IsExpired(user) {
+		return nil, errors.New("invalid password change token")
+	}
+
+	if err := s.passwordSvc.ChangePassword(user, newPassword); err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(user.ID, "PASSWORD_CHANGED", "user", &user.ID, map[string]interface{}{
+		"reason":     "expired",
+		"ip_address": ipAddress,
+	})
+
+	return s.completeLogin(user, userAgent, ipAddress)
+}
+
+// completeLogin runs the steps shared by every primary authentication method:
+// it issues an MFA challenge when one is needed and a token pair otherwise.
+func (s *AuthService) completeLogin(user *models.User, userAgent, ipAddress string) (*LoginResult, error) {
+	// Get employee record (needed for employeeID in JWT)
 	employee, err := s.employeeRepo.FindByUserID(user.ID)
 	if err != nil {
-		return "", "", errors.New("employee record not found")
+		return nil, errors.New("employee record not found")
 	}
 
-	// 4. Generate access token
-	permissions := authz.PermissionsForRole(user.Role)
+	enabled, err := s.mfaSvc.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled || s.mfaSvc.IsRequiredFor(user.ID, user.Role) {
+		mfaToken, err := s.generateMFAToken(user, employee.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			MFARequired:           true,
+			MFAEnrollmentRequired: !enabled,
+			MFAToken:              mfaToken,
+		}, nil
+	}
 
-	accessToken, err := utils.GenerateToken(
-		user.ID.String(),
-		employee.ID.String(),
-		user.Role,
-		permissions,
-		s.jwtSecret,
-		15*time.Minute,
-	)
+	return s.issueSession(user, employee.ID.String(), userAgent, ipAddress)
+}
+
+// BeginMFAEnrollment lets a user whose role requires MFA enroll during login,
+// authenticated by the MFA challenge token.
+func (s *AuthService) BeginMFAEnrollment(mfaToken string) (*MFAEnrollment, error) {
+	_, user, err := s.parseMFAToken(mfaToken)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	return s.mfaSvc.BeginEnrollment(user.ID)
+}
+
+// VerifyMFA completes a login challenge with a TOTP or recovery code. If the
+// user was enrolling, the factor is confirmed and recovery codes returned.
+func (s *AuthService) VerifyMFA(mfaToken, code, recoveryCode, userAgent, ipAddress string) (*LoginResult, error) {
+	claims, user, err := s.parseMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
 	}
 
-	// 5. Generate refresh token
-	refreshToken, err := utils.GenerateToken(
-		user.ID.String(),
-		employee.ID.String(),
-		user.Role,
-		permissions,
-		s.jwtSecret,
-		7*24*time.Hour,
-	)
+	if err := s.throttle.Check(user.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.mfaSvc.IsEnabled(user.ID)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	switch {
+	case !enabled:
+		recoveryCodes, err = s.mfaSvc.ConfirmEnrollment(user.ID, code)
+	case recoveryCode != "":
+		err = s.mfaSvc.UseRecoveryCode(user.ID, recoveryCode)
+	default:
+		err = s.mfaSvc.Verify(user.ID, code)
+	}
+	if err != nil {
+		s.throttle.RecordFailure(user.ID, user.Email, ipAddress, "invalid_mfa_code")
+		return nil, err
 	}
 
-	return accessToken, refreshToken, nil
+	result, err := s.issueSession(user, claims.EmployeeID, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = recoveryCodes
+	return result, nil
 }
 
 // Refresh validates a refresh token and rotates access/refresh tokens.
-func (s *AuthService) Refresh(refreshToken string) (string, string, error) {
-	claims := &utils.JWTClaims{}
-	token, err := jwt.ParseWithClaims(
-		refreshToken,
-		claims,
-		func(t *jwt.Token) (interface{}, error) {
-			return []byte(s.jwtSecret), nil
-		},
-		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
-	)
-	if err != nil || !token.Valid {
+// Presenting a token that was already rotated is treated as theft: the
+// whole token family is revoked and the event is audited.
+func (s *AuthService) Refresh(refreshToken, userAgent, ipAddress string) (string, string, error) {
+	claims, err := s.tokenKeys.Verify(refreshToken)
+	if err != nil || claims.TokenType != utils.TokenTypeRefresh || claims.ID == "" {
 		return "", "", errors.New("invalid refresh token")
 	}
 
@@ -100,6 +248,18 @@ func (s *AuthService) Refresh(refreshToken string) (string, string, error) {
 		return "", "", errors.New("invalid refresh token")
 	}
 
+	current, err := s.refreshTokenRepo.FindByHash(utils.HashToken(claims.ID))
+	if err != nil || current.UserID != userID {
+		return "", "", errors.New("invalid refresh token")
+	}
+	if current.RotatedAt != nil {
+		s.handleRefreshTokenReuse(current, userAgent)
+		return "", "", errors.New("invalid refresh token")
+	}
+	if current.RevokedAt != nil || time.Now().After(current.ExpiresAt) {
+		return "", "", errors.New("invalid refresh token")
+	}
+
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil || !user.IsActive {
 		return "", "", errors.New("user not found")
@@ -114,29 +274,204 @@ func (s *AuthService) Refresh(refreshToken string) (string, string, error) {
 		employeeID = employee.ID.String()
 	}
 
-	newAccessToken, err := utils.GenerateToken(
-		user.ID.String(),
-		employeeID,
-		user.Role,
-		authz.PermissionsForRole(user.Role),
-		s.jwtSecret,
-		15*time.Minute,
-	)
+	newAccessToken, err := s.generateAccessToken(user, employeeID, current.FamilyID)
 	if err != nil {
 		return "", "", err
 	}
 
-	newRefreshToken, err := utils.GenerateToken(
-		user.ID.String(),
-		employeeID,
-		user.Role,
-		authz.PermissionsForRole(user.Role),
-		s.jwtSecret,
-		7*24*time.Hour,
-	)
+	newRefreshToken, next, err := s.generateRefreshToken(user, employeeID, current.FamilyID, userAgent)
 	if err != nil {
 		return "", "", err
 	}
+	if err := s.refreshTokenRepo.Rotate(current, next); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenRotated) {
+			s.handleRefreshTokenReuse(current, userAgent)
+			return "", "", errors.New("invalid refresh token")
+		}
+		return "", "", err
+	}
+
+	_ = s.sessionRepo.Touch(current.FamilyID, time.Now().UTC(), ipAddress, truncate(userAgent, 255), &next.ExpiresAt)
 
 	return newAccessToken, newRefreshToken, nil
 }
+
+// Logout revokes the caller's current session: the presented access token is
+// denylisted and its refresh token family revoked. With everywhere set, every
+// session and every outstanding token of the user is revoked.
+func (s *AuthService) Logout(userID uuid.UUID, jti, sessionID string, expiresAt time.Time, everywhere bool) error {
+	if everywhere {
+		if err := s.RevokeUserAccess(userID); err != nil {
+			return err
+		}
+
+		s.auditSvc.Log(userID, "LOGOUT_ALL", "user", &userID, nil)
+		return nil
+	}
+
+	if familyID, err := uuid.Parse(sessionID); err == nil {
+		if err := s.refreshTokenRepo.RevokeFamily(familyID); err != nil {
+			return err
+		}
+		if err := s.denylist.RevokeSession(familyID, &userID); err != nil {
+			return err
+		}
+	}
+	if err := s.denylist.RevokeToken(jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(userID, "LOGOUT", "user", &userID, map[string]interface{}{
+		"session_id": sessionID,
+	})
+	return nil
+}
+
+// RevokeUserAccess revokes every refresh token family of the user and
+// invalidates all access tokens issued so far.
+func (s *AuthService) RevokeUserAccess(userID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	return s.denylist.RevokeAllForUser(userID)
+}
+
+func (s *AuthService) handleRefreshTokenReuse(token *models.RefreshToken, userAgent string) {
+	_ = s.refreshTokenRepo.RevokeFamily(token.FamilyID)
+	_ = s.denylist.RevokeSession(token.FamilyID, nil)
+
+	s.auditSvc.Log(token.UserID, "REFRESH_TOKEN_REUSED", "refresh_token", &token.ID, map[string]interface{}{
+		"family_id":  token.FamilyID.String(),
+		"user_agent": userAgent,
+	})
+}
+
+// issueSession starts a new session: a session record, a refresh token
+// family with the same ID and the first token pair.
+func (s *AuthService) issueSession(user *models.User, employeeID, userAgent, ipAddress string) (*LoginResult, error) {
+	familyID := uuid.New()
+	accessToken, err := s.generateAccessToken(user, employeeID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, record, err := s.generateRefreshToken(user, employeeID, familyID, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.Create(record); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := &models.Session{
+		BaseModel:  models.BaseModel{ID: familyID},
+		UserID:     user.ID,
+		Device:     utils.DescribeDevice(userAgent),
+		IPAddress:  ipAddress,
+		UserAgent:  truncate(userAgent, 255),
+		LastSeenAt: now,
+		ExpiresAt:  record.ExpiresAt,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (s *AuthService) generateMFAToken(user *models.User, employeeID string) (string, error) {
+	claims := &utils.JWTClaims{
+		UserID:     user.ID.String(),
+		EmployeeID: employeeID,
+		TokenType:  utils.TokenTypeMFA,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(mfaTokenTTL)),
+		},
+	}
+	return s.tokenKeys.Sign(claims)
+}
+
+func (s *AuthService) generatePasswordChangeToken(user *models.User) (string, error) {
+	claims := &utils.JWTClaims{
+		UserID:    user.ID.String(),
+		TokenType: utils.TokenTypePasswordChange,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(passwordChangeTokenTTL)),
+		},
+	}
+	return s.tokenKeys.Sign(claims)
+}
+
+func (s *AuthService) parseMFAToken(mfaToken string) (*utils.JWTClaims, *models.User, error) {
+	claims, err := s.tokenKeys.Verify(mfaToken)
+	if err != nil || claims.TokenType != utils.TokenTypeMFA {
+		return nil, nil, errors.New("invalid mfa token")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, nil, errors.New("invalid mfa token")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.IsActive {
+		return nil, nil, errors.New("invalid mfa token")
+	}
+	return claims, user, nil
+}
+
+func (s *AuthService) generateAccessToken(user *models.User, employeeID string, familyID uuid.UUID) (string, error) {
+	claims := &utils.JWTClaims{
+		UserID:            user.ID.String(),
+		Role:              user.Role,
+		EmployeeID:        employeeID,
+		Permissions:       authz.PermissionsForRole(user.Role),
+		TokenType:         utils.TokenTypeAccess,
+		SessionID:         familyID.String(),
+		PermissionVersion: user.PermissionVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
+		},
+	}
+	return s.tokenKeys.Sign(claims)
+}
+
+func (s *AuthService) generateRefreshToken(
+	user *models.User,
+	employeeID string,
+	familyID uuid.UUID,
+	userAgent string,
+) (string, *models.RefreshToken, error) {
+	expiresAt := time.Now().UTC().Add(refreshTokenTTL)
+	claims := &utils.JWTClaims{
+		UserID:     user.ID.String(),
+		Role:       user.Role,
+		EmployeeID: employeeID,
+		TokenType:  utils.TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := s.tokenKeys.Sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	record := &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(claims.ID),
+		UserAgent: truncate(userAgent, 255),
+		ExpiresAt: expiresAt,
+	}
+	return token, record, nil
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
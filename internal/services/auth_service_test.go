@@ -0,0 +1,140 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+type memoryRefreshTokenRepo struct {
+	repositories.RefreshTokenRepository
+	tokens map[string]*models.RefreshToken
+}
+
+func (r *memoryRefreshTokenRepo) Create(token *models.RefreshToken) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *memoryRefreshTokenRepo) FindByHash(hash string) (*models.RefreshToken, error) {
+	if token, ok := r.tokens[hash]; ok {
+		copied := *token
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryRefreshTokenRepo) Rotate(current, next *models.RefreshToken) error {
+	stored := r.tokens[current.TokenHash]
+	if stored.RotatedAt != nil || stored.RevokedAt != nil {
+		return repositories.ErrRefreshTokenRotated
+	}
+	now := time.Now().UTC()
+	stored.RotatedAt = &now
+	return r.Create(next)
+}
+
+func (r *memoryRefreshTokenRepo) RevokeFamily(familyID uuid.UUID) error {
+	now := time.Now().UTC()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type memorySessionRepo struct {
+	repositories.SessionRepository
+}
+
+func (memorySessionRepo) Create(session *models.Session) error { return nil }
+
+func (memorySessionRepo) Touch(id uuid.UUID, at time.Time, ipAddress, userAgent string, expiresAt *time.Time) error {
+	return nil
+}
+
+type memorySessionDenylist struct {
+	TokenDenylist
+	revokedSessions []uuid.UUID
+}
+
+func (d *memorySessionDenylist) RevokeSession(sessionID uuid.UUID, revokedBy *uuid.UUID) error {
+	d.revokedSessions = append(d.revokedSessions, sessionID)
+	return nil
+}
+
+func TestRefreshRotatesTokensAndRevokesFamilyOnReuse(t *testing.T) {
+	user := &models.User{Email: "ana@example.com", Role: "employee", IsActive: true}
+	tokens := &memoryRefreshTokenRepo{tokens: map[string]*models.RefreshToken{}}
+	denylist := &memorySessionDenylist{}
+	audit := &memoryAudit{}
+	svc := &AuthService{
+		userRepo:         newMemoryUserRepo(user),
+		refreshTokenRepo: tokens,
+		sessionRepo:      memorySessionRepo{},
+		auditSvc:         audit,
+		denylist:         denylist,
+		tokenKeys:        hmacTokenKeys{utils.HMACKey("test-secret")},
+	}
+
+	login, err := svc.issueSession(user, uuid.NewString(), "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+
+	_, rotated, err := svc.Refresh(login.RefreshToken, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("first refresh should succeed: %v", err)
+	}
+	if rotated == login.RefreshToken {
+		t.Fatal("refresh should issue a new refresh token")
+	}
+
+	if _, _, err := svc.Refresh(login.RefreshToken, "stolen-agent", "10.0.0.1"); err == nil {
+		t.Fatal("reusing a rotated refresh token should fail")
+	}
+	if !audit.logged("REFRESH_TOKEN_REUSED") {
+		t.Fatal("reuse should be audited")
+	}
+	if len(denylist.revokedSessions) != 1 {
+		t.Fatal("reuse should revoke the session's access tokens")
+	}
+	if _, _, err := svc.Refresh(rotated, "test-agent", "127.0.0.1"); err == nil {
+		t.Fatal("reuse should revoke the rest of the family too")
+	}
+}
+
+func TestRefreshRejectsAccessTokensAndInactiveUsers(t *testing.T) {
+	user := &models.User{Email: "ana@example.com", Role: "employee", IsActive: true}
+	svc := &AuthService{
+		userRepo:         newMemoryUserRepo(user),
+		refreshTokenRepo: &memoryRefreshTokenRepo{tokens: map[string]*models.RefreshToken{}},
+		sessionRepo:      memorySessionRepo{},
+		auditSvc:         &memoryAudit{},
+		denylist:         &memorySessionDenylist{},
+		tokenKeys:        hmacTokenKeys{utils.HMACKey("test-secret")},
+	}
+
+	login, err := svc.issueSession(user, uuid.NewString(), "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+	if _, _, err := svc.Refresh(login.AccessToken, "test-agent", "127.0.0.1"); err == nil {
+		t.Fatal("an access token must not be accepted as a refresh token")
+	}
+
+	user.IsActive = false
+	if _, _, err := svc.Refresh(login.RefreshToken, "test-agent", "127.0.0.1"); err == nil {
+		t.Fatal("a deactivated user must not refresh")
+	}
+}
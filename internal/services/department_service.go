@@ -3,6 +3,9 @@ package services
 import (
 	"errors"
 
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
 	"go-backend/internal/models"
 	"go-backend/internal/repositories"
 )
@@ -10,14 +13,30 @@ import (
 type DepartmentService interface {
 	Create(name string) (*models.Department, error)
 	List() ([]models.Department, error)
+
+	ListManagers(departmentID uuid.UUID) ([]models.DepartmentManager, error)
+	SetManagers(departmentID uuid.UUID, userIDs []uuid.UUID, adminID uuid.UUID) error
+
+	// ScopeFor resolves the employees an actor holding permissions may act
+	// on: the departments and reporting lines of the actor and of the users
+	// who delegated to them.
+	ScopeFor(userID uuid.UUID, delegatorIDs []uuid.UUID, permissions []string) (Scope, error)
 }
 
 type departmentService struct {
-	repo repositories.DepartmentRepository
+	repo         repositories.DepartmentRepository
+	userRepo     repositories.UserRepository
+	employeeRepo repositories.EmployeeRepository
+	auditSvc     AuditService
 }
 
-func NewDepartmentService(repo repositories.DepartmentRepository) DepartmentService {
-	return &departmentService{repo: repo}
+func NewDepartmentService(
+	repo repositories.DepartmentRepository,
+	userRepo repositories.UserRepository,
+	employeeRepo repositories.EmployeeRepository,
+	auditSvc AuditService,
+) DepartmentService {
+	return &departmentService{repo: repo, userRepo: userRepo, employeeRepo: employeeRepo, auditSvc: auditSvc}
 }
 
 func (s *departmentService) Create(name string) (*models.Department, error) {
@@ -39,3 +58,73 @@ func (s *departmentService) Create(name string) (*models.Department, error) {
 func (s *departmentService) List() ([]models.Department, error) {
 	return s.repo.List()
 }
+
+func (s *departmentService) ListManagers(departmentID uuid.UUID) ([]models.DepartmentManager, error) {
+	if _, err := s.repo.FindByID(departmentID.String()); err != nil {
+		return nil, err
+	}
+	return s.repo.ListManagers(departmentID)
+}
+
+// SetManagers replaces the managers of a department. Every manager must be
+// an active, human user.
+func (s *departmentService) SetManagers(departmentID uuid.UUID, userIDs []uuid.UUID, adminID uuid.UUID) error {
+	if _, err := s.repo.FindByID(departmentID.String()); err != nil {
+		return err
+	}
+
+	managers := make([]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil || !user.IsActive || user.Role == authz.RoleServiceAccount {
+			return errors.New("manager must be an active user: " + userID.String())
+		}
+		if !containsUUID(managers, userID) {
+			managers = append(managers, userID)
+		}
+	}
+
+	if err := s.repo.ReplaceManagers(departmentID, managers, adminID); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(managers))
+	for _, id := range managers {
+		ids = append(ids, id.String())
+	}
+	s.auditSvc.Log(adminID, "DEPARTMENT_MANAGERS_SET", "department", &departmentID, map[string]interface{}{
+		"manager_ids": ids,
+	})
+	return nil
+}
+
+func (s *departmentService) ScopeFor(userID uuid.UUID, delegatorIDs []uuid.UUID, permissions []string) (Scope, error) {
+	if authz.HasPermission(permissions, authz.PermManageAllDepartments) {
+		return Scope{Global: true, Permissions: permissions}, nil
+	}
+
+	managers := append([]uuid.UUID{userID}, delegatorIDs...)
+	departmentIDs, err := s.repo.ManagedDepartmentIDs(managers)
+	if err != nil {
+		return Scope{}, err
+	}
+	reportIDs, err := s.employeeRepo.ReportIDs(managers)
+	if err != nil {
+		return Scope{}, err
+	}
+	return Scope{
+		DepartmentIDs: departmentIDs,
+		ReportIDs:     reportIDs,
+		OnBehalfOf:    delegatorIDs,
+		Permissions:   permissions,
+	}, nil
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/spreadsheet"
+	"go-backend/pkg/utils"
+)
+
+// ErrInvalidImport is returned when the file as a whole cannot be imported:
+// it is unreadable, has no header for a required field, or is too large.
+var ErrInvalidImport = errors.New("invalid import file")
+
+// ErrImportHasErrors is returned with the report when a commit is refused
+// because at least one row failed validation.
+var ErrImportHasErrors = errors.New("import has rows with errors; nothing was imported")
+
+// importFields are the CreateEmployee fields a file must provide.
+var importFields = []string{"first_name", "last_name", "email", "role", "department"}
+
+// importHeaderAliases maps normalised header names to import fields.
+var importHeaderAliases = map[string]string{
+	"first_name":      "first_name",
+	"firstname":       "first_name",
+	"given_name":      "first_name",
+	"last_name":       "last_name",
+	"lastname":        "last_name",
+	"surname":         "last_name",
+	"family_name":     "last_name",
+	"email":           "email",
+	"e_mail":          "email",
+	"email_address":   "email",
+	"role":            "role",
+	"department":      "department",
+	"department_name": "department",
+	"dept":            "department",
+}
+
+// ImportOptions controls an employee import. Mapping overrides header
+// detection: it maps an import field to the header used for it in the file.
+type ImportOptions struct {
+	Commit  bool
+	Mapping map[string]string
+}
+
+// ImportRowResult is the outcome of one data row.
+type ImportRowResult struct {
+	Row        int        `json:"row"`
+	Email      string     `json:"email"`
+	FirstName  string     `json:"first_name"`
+	LastName   string     `json:"last_name"`
+	Role       string     `json:"role"`
+	Department string     `json:"department"`
+	Errors     []string   `json:"errors,omitempty"`
+	EmployeeID *uuid.UUID `json:"employee_id,omitempty"`
+	InviteSent *bool      `json:"invite_sent,omitempty"`
+}
+
+// ImportReport summarises a dry run or a committed import.
+type ImportReport struct {
+	DryRun      bool              `json:"dry_run"`
+	Committed   bool              `json:"committed"`
+	TotalRows   int               `json:"total_rows"`
+	ValidRows   int               `json:"valid_rows"`
+	InvalidRows int               `json:"invalid_rows"`
+	Created     int               `json:"created"`
+	InvitesSent int               `json:"invites_sent"`
+	Rows        []ImportRowResult `json:"rows"`
+}
+
+// EmployeeImportService creates employees in bulk from a spreadsheet. Every
+// row is validated first; a commit writes all rows or none.
+type EmployeeImportService struct {
+	userRepo       repositories.UserRepository
+	employeeRepo   repositories.EmployeeRepository
+	departmentRepo repositories.DepartmentRepository
+	inviteSvc      InvitationService
+	maxRows        int
+}
+
+func NewEmployeeImportService(
+	userRepo repositories.UserRepository,
+	employeeRepo repositories.EmployeeRepository,
+	departmentRepo repositories.DepartmentRepository,
+	inviteSvc InvitationService,
+) *EmployeeImportService {
+	return &EmployeeImportService{
+		userRepo:       userRepo,
+		employeeRepo:   employeeRepo,
+		departmentRepo: departmentRepo,
+		inviteSvc:      inviteSvc,
+		maxRows:        utils.GetEnvInt("IMPORT_MAX_ROWS", 1000),
+	}
+}
+
+// Import validates the rows of filename and, when opts.Commit is set and
+// every row is valid, creates the employees in a single transaction and
+// invites them. The report is returned with ErrImportHasErrors too.
+func (s *EmployeeImportService) Import(
+	filename string,
+	data []byte,
+	opts ImportOptions,
+	adminID uuid.UUID,
+	scope Scope,
+) (*ImportReport, error) {
+	rows, err := spreadsheet.Read(filename, data)
+	if errors.Is(err, spreadsheet.ErrUnsupportedFormat) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read file: %v", ErrInvalidImport, err)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("%w: file has no data rows", ErrInvalidImport)
+	}
+	if len(rows)-1 > s.maxRows {
+		return nil, fmt.Errorf("%w: file has %d rows; at most %d can be imported at once", ErrInvalidImport, len(rows)-1, s.maxRows)
+	}
+
+	columns, err := importColumns(rows[0].Cells, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	departments, err := s.departmentsByName()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun:    !opts.Commit,
+		TotalRows: len(rows) - 1,
+		Rows:      make([]ImportRowResult, 0, len(rows)-1),
+	}
+	hires := make([]repositories.NewHire, 0, len(rows)-1)
+	seen := make(map[string]int)
+
+	for _, row := range rows[1:] {
+		cell := func(field string) string {
+			if i := columns[field]; i < len(row.Cells) {
+				return row.Cells[i]
+			}
+			return ""
+		}
+		result := ImportRowResult{
+			Row:        row.Number,
+			Email:      cell("email"),
+			FirstName:  cell("first_name"),
+			LastName:   cell("last_name"),
+			Role:       strings.ToLower(cell("role")),
+			Department: cell("department"),
+		}
+
+		for _, field := range importFields {
+			if cell(field) == "" {
+				result.Errors = append(result.Errors, field+" is required")
+			}
+		}
+
+		if result.Email != "" {
+			email := normalizeEmail(result.Email)
+			if address, err := mail.ParseAddress(result.Email); err != nil || address.Address != result.Email {
+				result.Errors = append(result.Errors, "email is not a valid address")
+			} else if first, ok := seen[email]; ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("email duplicates row %d", first))
+			} else if existing, _ := s.userRepo.FindByEmail(email); existing != nil {
+				result.Errors = append(result.Errors, "user with this email already exists")
+			}
+			if _, ok := seen[email]; !ok {
+				seen[email] = row.Number
+			}
+		}
+
+		if result.Role != "" {
+			if err := checkAssignableRole(result.Role, scope); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+
+		var department *models.Department
+		if result.Department != "" {
+			department = departments[strings.ToLower(result.Department)]
+			if department == nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("unknown department %q", result.Department))
+			} else if !scope.Allows(&department.ID) {
+				result.Errors = append(result.Errors, ErrOutOfScope.Error())
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			report.InvalidRows++
+		} else {
+			report.ValidRows++
+			if opts.Commit {
+				user, employee, err := newInvitedHire(result.FirstName, result.LastName, result.Email, result.Role, department.ID)
+				if err != nil {
+					return nil, err
+				}
+				hires = append(hires, repositories.NewHire{
+					User:     user,
+					Employee: employee,
+					Event:    newAppliedEvent(uuid.Nil, newHireChange(employee, result.Role), &adminID),
+					Audit: newAuditLog(adminID, "EMPLOYEE_CREATED", "employee", nil, map[string]interface{}{
+						"email":  result.Email,
+						"role":   result.Role,
+						"source": "import",
+					}),
+				})
+			}
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if !opts.Commit {
+		return report, nil
+	}
+	if report.InvalidRows > 0 {
+		return report, ErrImportHasErrors
+	}
+
+	if err := s.employeeRepo.CreateHires(hires); err != nil {
+		return nil, err
+	}
+	report.Committed = true
+	report.Created = len(hires)
+
+	// Invitations go out after the commit; a failed one can be resent. Every
+	// row was valid, so hires and report rows line up.
+	for i, hire := range hires {
+		sent := s.inviteSvc.Invite(hire.User, hire.Employee, adminID) == nil
+		if sent {
+			report.InvitesSent++
+		}
+		report.Rows[i].EmployeeID = &hire.Employee.ID
+		report.Rows[i].InviteSent = &sent
+	}
+
+	return report, nil
+}
+
+// importColumns finds the column index of every import field, either from
+// mapping or by recognising the header names.
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := normalizeImportHeader(name)
+		if _, ok := positions[key]; !ok {
+			positions[key] = i
+		}
+	}
+
+	columns := make(map[string]int, len(importFields))
+	for i, name := range header {
+		if field, ok := importHeaderAliases[normalizeImportHeader(name)]; ok {
+			if _, taken := columns[field]; !taken {
+				columns[field] = i
+			}
+		}
+	}
+	for field, name := range mapping {
+		if importHeaderAliases[field] != field {
+			return nil, fmt.Errorf("%w: cannot map unknown field %q", ErrInvalidImport, field)
+		}
+		i, ok := positions[normalizeImportHeader(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: column %q mapped to %s is not in the file", ErrInvalidImport, name, field)
+		}
+		columns[field] = i
+	}
+
+	var missing []string
+	for _, field := range importFields {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: missing columns for %s", ErrInvalidImport, strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+func normalizeImportHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// departmentsByName indexes departments by case-insensitive name.
+func (s *EmployeeImportService) departmentsByName() (map[string]*models.Department, error) {
+	departments, err := s.departmentRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*models.Department, len(departments))
+	for i := range departments {
+		byName[strings.ToLower(strings.TrimSpace(departments[i].Name))] = &departments[i]
+	}
+	return byName, nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+func TestImportColumnsRecognisesHeadersAndMapping(t *testing.T) {
+	header := []string{"E-mail", "Given Name", "Surname", "Job Role", "Dept"}
+
+	if _, err := importColumns(header, nil); !errors.Is(err, ErrInvalidImport) {
+		t.Fatalf("expected ErrInvalidImport for the unmapped role column, got %v", err)
+	}
+
+	columns, err := importColumns(header, map[string]string{"role": "job role"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"email": 0, "first_name": 1, "last_name": 2, "role": 3, "department": 4}
+	for field, i := range want {
+		if columns[field] != i {
+			t.Fatalf("%s: expected column %d, got %d", field, i, columns[field])
+		}
+	}
+
+	if _, err := importColumns(header, map[string]string{"salary": "Dept"}); !errors.Is(err, ErrInvalidImport) {
+		t.Fatalf("expected ErrInvalidImport for an unknown field, got %v", err)
+	}
+}
+
+func TestImportDryRunReportsRowErrors(t *testing.T) {
+	departments := newMemoryDepartmentRepo("Engineering", "Sales")
+	svc := &EmployeeImportService{
+		userRepo:       newMemoryUserRepo(&models.User{Email: "taken@example.com"}),
+		departmentRepo: departments,
+		// A dry run must not write anything or send invitations.
+		employeeRepo: struct {
+			repositories.EmployeeRepository
+		}{},
+		maxRows: 100,
+	}
+
+	file := strings.Join([]string{
+		"email,first_name,last_name,role,department",
+		"ada@example.com,Ada,Lovelace,employee,Engineering",
+		"ADA@example.com,Ada,Again,employee,Engineering",
+		"Taken@Example.com,Tom,Taken,employee,Sales",
+		"bob@example.com,Bob,Lost,employee,Marketing",
+		"eve@example.com,Eve,Root,admin,Sales",
+		"sam@example.com,Sam,Outside,employee,Sales",
+	}, "\n")
+	scope := Scope{
+		DepartmentIDs: []uuid.UUID{departments.departments[0].ID},
+		Permissions:   authz.PermissionsForRole(authz.RoleManager),
+	}
+
+	report, err := svc.Import("people.csv", []byte(file), ImportOptions{}, uuid.New(), scope)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !report.DryRun || report.Committed || report.Created != 0 {
+		t.Fatalf("expected an uncommitted dry run, got %+v", report)
+	}
+	if report.TotalRows != 6 || report.ValidRows != 1 || report.InvalidRows != 5 {
+		t.Fatalf("expected 1 valid and 5 invalid rows, got %d and %d", report.ValidRows, report.InvalidRows)
+	}
+
+	want := map[string]string{
+		"ADA@example.com":   "email duplicates row 2",
+		"Taken@Example.com": "user with this email already exists",
+		"bob@example.com":   `unknown department "Marketing"`,
+		"eve@example.com":   errUnassignableRole.Error(),
+		"sam@example.com":   ErrOutOfScope.Error(),
+	}
+	for _, row := range report.Rows {
+		expected, invalid := want[row.Email]
+		if !invalid {
+			if len(row.Errors) != 0 {
+				t.Fatalf("row %d: expected no errors, got %v", row.Row, row.Errors)
+			}
+			continue
+		}
+		if !slices.Contains(row.Errors, expected) {
+			t.Fatalf("row %d: expected %q, got %v", row.Row, expected, row.Errors)
+		}
+	}
+}
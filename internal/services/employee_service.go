@@ -2,39 +2,149 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
 	"github.com/google/uuid"
 
+	"go-backend/internal/authz"
 	"go-backend/internal/models"
 	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
 )
 
+// ErrInviteNotSent is returned alongside the new employee when the record was
+// created but the invitation email failed; the invite can be resent.
+var ErrInviteNotSent = errors.New("employee created but the invitation could not be sent")
+
+var errUnassignableRole = errors.New("unknown role or role cannot be assigned to employees")
+
+var errRoleExceedsPermissions = errors.New("role grants permissions you do not hold")
+
+// ErrReportingCycle is returned when a new manager reports, directly or
+// indirectly, to the employee.
+var ErrReportingCycle = repositories.ErrReportingCycle
+
+var errInactiveManager = errors.New("manager must be an active employee")
+
+// isAssignableRole allows any defined role except admin and the service
+// account role, which are never granted through the employee API.
+func isAssignableRole(role string) bool {
+	return authz.RoleExists(role) && !authz.HasRole(role, authz.RoleAdmin, authz.RoleServiceAccount)
+}
+
+// checkAssignableRole also refuses roles granting anything the actor does
+// not hold, so manage_employees cannot be turned into manage_roles or
+// impersonate_users through a custom role.
+func checkAssignableRole(role string, scope Scope) error {
+	if !isAssignableRole(role) {
+		return errUnassignableRole
+	}
+	for _, permission := range authz.PermissionsForRole(role) {
+		if !authz.HasPermission(scope.Permissions, permission) {
+			return errRoleExceedsPermissions
+		}
+	}
+	return nil
+}
+
+// checkTargetRole refuses to act on an employee whose current role holds
+// anything the actor does not, so a manager cannot edit, demote or
+// deactivate an admin or HR user in a department they manage.
+func checkTargetRole(employee *models.Employee, scope Scope) error {
+	for _, permission := range authz.PermissionsForRole(employee.User.Role) {
+		if !authz.HasPermission(scope.Permissions, permission) {
+			return ErrOutOfScope
+		}
+	}
+	return nil
+}
+
+// EmployeeQuery asks for one page of the employee directory. HiredFrom and
+// HiredTo are inclusive dates. Sort names a field, prefixed with "-" for
+// descending order.
+type EmployeeQuery struct {
+	DepartmentID *uuid.UUID
+	Status       string
+	Role         string
+	HiredFrom    *time.Time
+	HiredTo      *time.Time
+	Search       string
+	Sort         string
+	Page         int
+	PageSize     int
+}
+
+// EmployeePage is one page of the directory and the total number of matches.
+type EmployeePage struct {
+	Employees []*models.Employee
+	Total     int64
+	Page      int
+	PageSize  int
+}
+
+const (
+	defaultEmployeePageSize = 25
+	maxEmployeePageSize     = 100
+)
+
+// employeeSortColumns whitelists the fields the directory can be sorted by.
+var employeeSortColumns = map[string]string{
+	"first_name": "employees.first_name",
+	"last_name":  "employees.last_name",
+	"email":      "users.email",
+	"role":       "users.role",
+	"status":     "employees.status",
+	"hire_date":  "employees.hire_date",
+	"created_at": "employees.created_at",
+}
+
+// ErrInvalidEmployeeQuery is returned for unknown sort fields and bad ranges.
+var ErrInvalidEmployeeQuery = errors.New("invalid employee query")
+
 type EmployeeService struct {
-	userRepo      repositories.UserRepository
-	employeeRepo  repositories.EmployeeRepository
-	auditSvc      AuditService
+	userRepo     repositories.UserRepository
+	employeeRepo repositories.EmployeeRepository
+	auditSvc     AuditService
+	history      EmploymentHistoryService
+	inviteSvc    InvitationService
 }
 
 func NewEmployeeService(
 	userRepo repositories.UserRepository,
 	employeeRepo repositories.EmployeeRepository,
 	auditSvc AuditService,
+	history EmploymentHistoryService,
+	inviteSvc InvitationService,
 ) *EmployeeService {
 	return &EmployeeService{
 		userRepo: userRepo,
 		employeeRepo: employeeRepo,
 		auditSvc: auditSvc,
+		history: history,
+		inviteSvc: inviteSvc,
 	}
 }
 
-// CreateEmployee creates a new user + employee record (Admin only)
+// CreateEmployee creates a new user + employee record (Admin only) and
+// invites the new hire to choose a password. The account stays inactive
+// until the invitation is accepted.
 func (s *EmployeeService) CreateEmployee(
-	firstName, lastName, email, role, password string,
+	firstName, lastName, email, role string,
 	departmentID uuid.UUID,
 	adminID uuid.UUID, // for audit logging
+	scope Scope,
 ) (*models.Employee, error) {
+	if !scope.Allows(&departmentID) {
+		return nil, ErrOutOfScope
+	}
+
+	if err := checkAssignableRole(role, scope); err != nil {
+		return nil, err
+	}
 
 	// 1. Check if user already exists
 	existingUser, _ := s.userRepo.FindByEmail(email)
@@ -42,93 +152,218 @@ func (s *EmployeeService) CreateEmployee(
 		return nil, errors.New("user with this email already exists")
 	}
 
-	// 2. Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	// 2. Build the invited user and employee records
+	user, employee, err := newInvitedHire(firstName, lastName, email, role, departmentID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 3. Create user record
-	user := &models.User{
-		Email:       email,
-		PasswordHash: string(hashedPassword), 
-		Role:        role,
-		IsActive:    true,
+	// 3. Create the user, employee, hire event and audit entry together
+	err = s.employeeRepo.CreateHires([]repositories.NewHire{{
+		User:     user,
+		Employee: employee,
+		Event:    newAppliedEvent(uuid.Nil, newHireChange(employee, role), &adminID),
+		Audit: newAuditLog(adminID, "EMPLOYEE_CREATED", "employee", nil, map[string]interface{}{
+			"email": email,
+			"role":  role,
+		}),
+	}})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, err
+	// 4. Invitation
+	if err := s.inviteSvc.Invite(user, employee, adminID); err != nil {
+		return employee, fmt.Errorf("%w: %v", ErrInviteNotSent, err)
 	}
 
-	// 4. Create employee record
+	return employee, nil
+}
+
+// newInvitedHire builds an inactive user with an unusable placeholder
+// password and its invited employee record; the caller links and saves them.
+func newInvitedHire(firstName, lastName, email, role string, departmentID uuid.UUID) (*models.User, *models.Employee, error) {
+	placeholder, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashedPassword, err := utils.HashPassword(placeholder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := &models.User{
+		Email:        email,
+		PasswordHash: hashedPassword,
+		Role:         role,
+		IsActive:     false,
+	}
 	employee := &models.Employee{
-		UserID:       user.ID,
-		FirstName:    firstName,     
-		LastName:     lastName,       
-		DepartmentID: &departmentID,  
-		Status:       "active",       
-		HireDate:     time.Now().UTC(), 
+		FirstName:    firstName,
+		LastName:     lastName,
+		DepartmentID: &departmentID,
+		Status:       "invited",
+		HireDate:     time.Now().UTC(),
 	}
+	return user, employee, nil
+}
 
-	if err := s.employeeRepo.Create(employee); err != nil {
-		return nil, err
+// newHireChange describes the hire of a freshly created employee.
+func newHireChange(employee *models.Employee, role string) EmploymentChange {
+	return EmploymentChange{
+		Type:          models.EmploymentEventHire,
+		EffectiveDate: employee.HireDate,
+		DepartmentID:  employee.DepartmentID,
+		Role:          role,
+		Status:        employee.Status,
+		JobTitle:      employee.JobTitle,
 	}
+}
 
-	// 5. Audit log
-	s.auditSvc.Log(adminID, "EMPLOYEE_CREATED", "employee", &employee.ID, map[string]interface{}{
-		"email": email,
-		"role":  role,
-	})
+// ListEmployees returns one page of the employees within scope matching
+// query (Admin/Manager only)
+func (s *EmployeeService) ListEmployees(query EmployeeQuery, scope Scope) (*EmployeePage, error) {
+	page := &EmployeePage{
+		Employees: []*models.Employee{},
+		Page:      max(query.Page, 1),
+		PageSize:  query.PageSize,
+	}
+	if page.PageSize <= 0 {
+		page.PageSize = defaultEmployeePageSize
+	}
+	page.PageSize = min(page.PageSize, maxEmployeePageSize)
 
-	return employee, nil
-}
+	orderBy, err := employeeOrderBy(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+	if query.HiredFrom != nil && query.HiredTo != nil && query.HiredTo.Before(*query.HiredFrom) {
+		return nil, fmt.Errorf("%w: hired_to must not be before hired_from", ErrInvalidEmployeeQuery)
+	}
+	if query.DepartmentID != nil && !scope.Allows(query.DepartmentID) {
+		return nil, ErrOutOfScope
+	}
+
+	employeeScope, empty := scope.filter()
+	if empty {
+		return page, nil
+	}
 
-// ListEmployees retrieves all employees (Admin/Manager only)
-func (s *EmployeeService) ListEmployees() ([]*models.Employee, error) {
-	employees, err := s.employeeRepo.List()
+	employees, total, err := s.employeeRepo.Search(repositories.EmployeeFilter{
+		Scope:         employeeScope,
+		DepartmentID:  query.DepartmentID,
+		Status:        query.Status,
+		Role:          query.Role,
+		HiredFrom:     query.HiredFrom,
+		HiredTo:       query.HiredTo,
+		Search:        query.Search,
+		OrderBy:       orderBy,
+		Limit:         page.PageSize,
+		Offset:        (page.Page - 1) * page.PageSize,
+	})
 	if err != nil {
 		return nil, err
 	}
-	return employees, nil
+	page.Employees = employees
+	page.Total = total
+	return page, nil
+}
+
+// employeeOrderBy turns a whitelisted sort field into an ORDER BY clause.
+func employeeOrderBy(field string) (string, error) {
+	if field == "" {
+		return "employees.last_name, employees.first_name", nil
+	}
+
+	direction := "ASC"
+	if strings.HasPrefix(field, "-") {
+		direction = "DESC"
+		field = strings.TrimPrefix(field, "-")
+	}
+	column, ok := employeeSortColumns[field]
+	if !ok {
+		fields := make([]string, 0, len(employeeSortColumns))
+		for name := range employeeSortColumns {
+			fields = append(fields, name)
+		}
+		sort.Strings(fields)
+		return "", fmt.Errorf("%w: sort must be one of %s", ErrInvalidEmployeeQuery, strings.Join(fields, ", "))
+	}
+	return column + " " + direction, nil
 }
 
-func (s *EmployeeService) CountEmployees() (int64, error) {
-	return s.employeeRepo.Count()
+func (s *EmployeeService) CountEmployees(scope Scope) (int64, error) {
+	employeeScope, empty := scope.filter()
+	if empty {
+		return 0, nil
+	}
+	return s.employeeRepo.Count(employeeScope)
 }
 
 
-// UpdateEmployee updates employee details (Admin only)
+// UpdateEmployee updates employee details (Admin only). A new department or
+// role is recorded as a transfer or role change effective today.
 func (s *EmployeeService) UpdateEmployee(
 	employeeID uuid.UUID,
 	firstName, lastName string,
 	departmentID uuid.UUID,
 	role string,
 	adminID uuid.UUID, // for audit
+	scope Scope,
 ) (*models.Employee, error) {
+	if !isAssignableRole(role) {
+		return nil, errUnassignableRole
+	}
 
 	employee, err := s.employeeRepo.FindByID(employeeID)
 	if err != nil {
 		return nil, err
 	}
+	// Both the current and the target department must be within scope
+	if !scope.AllowsEmployee(employee, adminID) || !scope.Allows(&departmentID) {
+		return nil, ErrOutOfScope
+	}
+	if err := checkTargetRole(employee, scope); err != nil {
+		return nil, err
+	}
 
 	employee.FirstName = firstName
 	employee.LastName = lastName
-	employee.DepartmentID = &departmentID
 
-	// Update user role
-	user, err := s.userRepo.FindByID(employee.UserID)
-	if err != nil {
-		return nil, err
+	// Department and role go through the employment history. Both changes
+	// are validated before the names and events are written together.
+	var events []*models.EmploymentEvent
+	if employee.DepartmentID == nil || *employee.DepartmentID != departmentID {
+		event, err := s.history.Prepare(employee.ID, EmploymentChange{
+			Type:         models.EmploymentEventTransfer,
+			DepartmentID: &departmentID,
+		}, adminID, scope)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if employee.User.Role != role {
+		event, err := s.history.Prepare(employee.ID, EmploymentChange{
+			Type: models.EmploymentEventRoleChange,
+			Role: role,
+		}, adminID, scope)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
 	}
-	user.Role = role
 
-	// Save updates
-	if err := s.employeeRepo.Update(employee); err != nil {
+	if err := s.employeeRepo.UpdateDetails(employee, events); err != nil {
 		return nil, err
 	}
-	if err := s.userRepo.Update(user); err != nil {
-		return nil, err
+	for _, event := range events {
+		if err := s.history.Settle(event); err != nil {
+			return nil, err
+		}
 	}
+	employee.DepartmentID = &departmentID
+	employee.User.Role = role
 
 	// Audit log
 	s.auditSvc.Log(adminID, "EMPLOYEE_UPDATED", "employee", &employee.ID, map[string]interface{}{
@@ -140,24 +375,83 @@ func (s *EmployeeService) UpdateEmployee(
 	return employee, nil
 }
 
-// DeactivateEmployee sets employee as inactive (Admin only)
+// SetManager changes whom an employee reports to, or detaches them when
+// managerID is nil. Scoped actors need both people within their scope.
+func (s *EmployeeService) SetManager(
+	employeeID uuid.UUID,
+	managerID *uuid.UUID,
+	adminID uuid.UUID,
+	scope Scope,
+) (*models.Employee, error) {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.AllowsEmployee(employee, adminID) {
+		return nil, ErrOutOfScope
+	}
+
+	if managerID != nil {
+		manager, err := s.employeeRepo.FindByID(*managerID)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(endedStatuses, manager.Status) {
+			return nil, errInactiveManager
+		}
+		if !scope.reaches(manager) {
+			return nil, ErrOutOfScope
+		}
+	}
+
+	if err := s.employeeRepo.SetManager(employee.ID, managerID); err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"previous_manager_id": nil,
+		"manager_id":          nil,
+	}
+	if employee.ManagerID != nil {
+		metadata["previous_manager_id"] = employee.ManagerID.String()
+	}
+	if managerID != nil {
+		metadata["manager_id"] = managerID.String()
+	}
+	s.auditSvc.Log(adminID, "EMPLOYEE_MANAGER_CHANGED", "employee", &employee.ID, metadata)
+
+	employee.ManagerID = managerID
+	return employee, nil
+}
+
+// DeactivateEmployee records a status change to inactive effective today,
+// which cuts off the linked user's access immediately (Admin only)
 func (s *EmployeeService) DeactivateEmployee(
 	employeeID uuid.UUID,
 	adminID uuid.UUID,
+	scope Scope,
 ) error {
 	employee, err := s.employeeRepo.FindByID(employeeID)
 	if err != nil {
 		return err
 	}
+	if err := checkTargetRole(employee, scope); err != nil {
+		return err
+	}
 
-	employee.Status = "inactive"
-
-	if err := s.employeeRepo.Update(employee); err != nil {
+	event, err := s.history.Record(employeeID, EmploymentChange{
+		Type:   models.EmploymentEventStatusChange,
+		Status: "inactive",
+	}, adminID, scope)
+	if err != nil {
 		return err
 	}
 
 	// Audit log
-	s.auditSvc.Log(adminID, "EMPLOYEE_DEACTIVATED", "employee", &employee.ID, nil)
+	s.auditSvc.Log(adminID, "EMPLOYEE_DEACTIVATED", "employee", &employee.ID, map[string]interface{}{
+		"user_id":        employee.UserID.String(),
+		"deactivated_at": event.AppliedAt.Format(time.RFC3339),
+	})
 
 	return nil
 }
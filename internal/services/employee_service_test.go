@@ -0,0 +1,182 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+)
+
+type stubRoleStore map[string][]string
+
+func (s stubRoleStore) RolePermissions(role string) ([]string, bool) {
+	permissions, ok := s[role]
+	return permissions, ok
+}
+
+func TestEmployeeOrderByWhitelistsFields(t *testing.T) {
+	cases := map[string]string{
+		"":           "employees.last_name, employees.first_name",
+		"hire_date":  "employees.hire_date ASC",
+		"-email":     "users.email DESC",
+		"-last_name": "employees.last_name DESC",
+	}
+	for sort, want := range cases {
+		got, err := employeeOrderBy(sort)
+		if err != nil || got != want {
+			t.Fatalf("sort %q: got %q, %v; want %q", sort, got, err, want)
+		}
+	}
+
+	for _, sort := range []string{"password_hash", "last_name; DROP TABLE users", "--email"} {
+		if _, err := employeeOrderBy(sort); !errors.Is(err, ErrInvalidEmployeeQuery) {
+			t.Fatalf("sort %q: expected ErrInvalidEmployeeQuery, got %v", sort, err)
+		}
+	}
+}
+
+func TestCheckAssignableRoleRefusesEscalation(t *testing.T) {
+	t.Cleanup(func() { authz.SetRoleStore(nil) })
+	authz.SetRoleStore(stubRoleStore{
+		"team_lead":    {authz.PermManageEmployees, authz.PermViewOrgChart},
+		"role_admin":   {authz.PermManageEmployees, authz.PermManageRoles},
+		"impersonator": {authz.PermViewProfile, authz.PermImpersonateUsers},
+	})
+	manager := Scope{Permissions: authz.PermissionsForRole(authz.RoleManager)}
+
+	for _, role := range []string{authz.RoleEmployee, authz.RoleManager, "team_lead"} {
+		if err := checkAssignableRole(role, manager); err != nil {
+			t.Fatalf("%s: expected a manager to assign it, got %v", role, err)
+		}
+	}
+	for _, role := range []string{"role_admin", "impersonator"} {
+		if err := checkAssignableRole(role, manager); !errors.Is(err, errRoleExceedsPermissions) {
+			t.Fatalf("%s: expected errRoleExceedsPermissions, got %v", role, err)
+		}
+	}
+	for _, role := range []string{authz.RoleAdmin, authz.RoleServiceAccount, "unknown"} {
+		if err := checkAssignableRole(role, GlobalScope()); !errors.Is(err, errUnassignableRole) {
+			t.Fatalf("%s: expected errUnassignableRole, got %v", role, err)
+		}
+	}
+
+	// A global scope lifts department limits, not permission limits.
+	global := Scope{Global: true, Permissions: []string{authz.PermManageEmployees, authz.PermManageAllDepartments}}
+	if err := checkAssignableRole("role_admin", global); !errors.Is(err, errRoleExceedsPermissions) {
+		t.Fatalf("expected a global scope without manage_roles to be refused, got %v", err)
+	}
+
+	// The refusal comes before anything is written.
+	svc := &EmployeeService{}
+	departmentID := uuid.New()
+	_, err := svc.CreateEmployee("Eve", "Root", "eve@example.com", "role_admin", departmentID, uuid.New(), Scope{
+		DepartmentIDs: []uuid.UUID{departmentID},
+		Permissions:   manager.Permissions,
+	})
+	if !errors.Is(err, errRoleExceedsPermissions) {
+		t.Fatalf("expected CreateEmployee to refuse the role, got %v", err)
+	}
+}
+
+func TestListEmployeesAcceptsSingleDayHireRange(t *testing.T) {
+	repo := newMemoryEmployeeRepo()
+	svc := &EmployeeService{employeeRepo: repo}
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.ListEmployees(EmployeeQuery{HiredFrom: &day, HiredTo: &day}, GlobalScope()); err != nil {
+		t.Fatalf("expected hired_from == hired_to to be accepted, got %v", err)
+	}
+	if len(repo.searches) != 1 || !repo.searches[0].HiredTo.Equal(day) {
+		t.Fatalf("expected the range to reach the repository unchanged, got %+v", repo.searches)
+	}
+
+	before := day.AddDate(0, 0, -1)
+	if _, err := svc.ListEmployees(EmployeeQuery{HiredFrom: &day, HiredTo: &before}, GlobalScope()); !errors.Is(err, ErrInvalidEmployeeQuery) {
+		t.Fatalf("expected ErrInvalidEmployeeQuery for a reversed range, got %v", err)
+	}
+}
+
+func TestCreateEmployeeWritesHireEventWithTheRecords(t *testing.T) {
+	departmentID := uuid.New()
+	employees := newMemoryEmployeeRepo()
+	invites := &memoryInvites{}
+	svc := &EmployeeService{userRepo: newMemoryUserRepo(), employeeRepo: employees, inviteSvc: invites}
+
+	adminID := uuid.New()
+	admin := Scope{Global: true, Permissions: authz.PermissionsForRole(authz.RoleAdmin)}
+	employee, err := svc.CreateEmployee("Ada", "Lovelace", "ada@example.com", authz.RoleEmployee, departmentID, adminID, admin)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if len(employees.hires) != 1 {
+		t.Fatalf("expected one CreateHires write, got %d", len(employees.hires))
+	}
+	hire := employees.hires[0]
+	if hire.Event == nil || hire.Event.Type != models.EmploymentEventHire || hire.Event.EmployeeID != employee.ID {
+		t.Fatalf("expected the hire event in the same write, got %+v", hire.Event)
+	}
+	if hire.Audit == nil || hire.Audit.Action != "EMPLOYEE_CREATED" {
+		t.Fatalf("expected the audit entry in the same write, got %+v", hire.Audit)
+	}
+	if hire.User.IsActive || len(invites.invited) != 1 {
+		t.Fatalf("expected an inactive, invited user, got active=%v invites=%d", hire.User.IsActive, len(invites.invited))
+	}
+}
+
+func TestUpdateEmployeeWritesNothingWhenAChangeIsRefused(t *testing.T) {
+	departments := newMemoryDepartmentRepo("Sales", "Support")
+	sales, support := departments.departments[0].ID, departments.departments[1].ID
+	history, employees, events := newTestHistory(departments)
+	employee := hireEmployee(employees, sales, authz.RoleEmployee, day("2024-01-15"))
+	svc := &EmployeeService{employeeRepo: employees, history: history, auditSvc: &memoryAudit{}}
+
+	// The transfer is valid but the role change exceeds the caller's permissions.
+	employeeOnly := Scope{Global: true, Permissions: authz.PermissionsForRole(authz.RoleEmployee)}
+	_, err := svc.UpdateEmployee(employee.ID, "Augusta", "King", support, authz.RoleManager, uuid.New(), employeeOnly)
+	if !errors.Is(err, errRoleExceedsPermissions) {
+		t.Fatalf("expected errRoleExceedsPermissions, got %v", err)
+	}
+	if stored := employees.employees[employee.ID]; stored.FirstName != "Ada" || *stored.DepartmentID != sales {
+		t.Fatalf("expected the employee to be unchanged, got %+v", stored)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected no event besides the hire, got %d events", len(events.events))
+	}
+
+	admin := Scope{Global: true, Permissions: authz.PermissionsForRole(authz.RoleAdmin)}
+	updated, err := svc.UpdateEmployee(employee.ID, "Augusta", "King", support, authz.RoleManager, uuid.New(), admin)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	stored := employees.employees[employee.ID]
+	if stored.FirstName != "Augusta" || *stored.DepartmentID != support || stored.User.Role != authz.RoleManager {
+		t.Fatalf("expected names, department and role to be written, got %+v", stored)
+	}
+	if len(events.events) != 3 || *updated.DepartmentID != support {
+		t.Fatalf("expected a transfer and a role change, got %d events", len(events.events))
+	}
+}
+
+func TestManagerCannotDemoteOrDeactivateAnAdmin(t *testing.T) {
+	departments := newMemoryDepartmentRepo("Sales")
+	sales := departments.departments[0].ID
+	history, employees, events := newTestHistory(departments)
+	admin := hireEmployee(employees, sales, authz.RoleAdmin, day("2024-01-15"))
+	svc := &EmployeeService{employeeRepo: employees, history: history, auditSvc: &memoryAudit{}}
+
+	manager := Scope{DepartmentIDs: []uuid.UUID{sales}, Permissions: authz.PermissionsForRole(authz.RoleManager)}
+	if _, err := svc.UpdateEmployee(admin.ID, "Ada", "Lovelace", sales, authz.RoleEmployee, uuid.New(), manager); !errors.Is(err, ErrOutOfScope) {
+		t.Fatalf("expected a manager demoting an admin to be refused, got %v", err)
+	}
+	if err := svc.DeactivateEmployee(admin.ID, uuid.New(), manager); !errors.Is(err, ErrOutOfScope) {
+		t.Fatalf("expected a manager deactivating an admin to be refused, got %v", err)
+	}
+	if stored := employees.employees[admin.ID]; stored.User.Role != authz.RoleAdmin || !stored.User.IsActive || len(events.events) != 1 {
+		t.Fatalf("expected the admin to be untouched, got role %s active %v", stored.User.Role, stored.User.IsActive)
+	}
+}
@@ -0,0 +1,667 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var (
+	errUnknownEventType    = errors.New("type must be one of transfer, promotion, role_change, status_change or rehire")
+	errTerminationRecorded = errors.New("terminations are recorded with a last working day and reason through the terminate endpoint")
+	errBeforeHire          = errors.New("effective_date must not be before the hire date")
+	errNotEmployed         = errors.New("employee is not employed on the effective date")
+	errStillEmployed       = errors.New("only employees who have been terminated or deactivated can be rehired")
+	errEventChangesNothing = errors.New("change does not differ from the employee's state on the effective date")
+	errDepartmentRequired  = errors.New("department_id is required for a transfer")
+	errJobTitleRequired    = errors.New("job_title is required for a promotion")
+	errRoleRequired        = errors.New("role is required for a role change")
+	errInvalidStatus       = errors.New("status must be active, on_leave or inactive")
+	errEventNotPending     = errors.New("only events that have not taken effect can be cancelled")
+)
+
+// Employee statuses outside employment; reaching one cuts off access.
+var endedStatuses = []string{"inactive", "terminated"}
+
+// changeableStatuses are the statuses a status_change may set. Termination
+// has its own event type.
+var changeableStatuses = []string{"active", "on_leave", "inactive"}
+
+// EmploymentChange describes an event to record. Only the fields its type
+// uses are read; a zero EffectiveDate means today.
+type EmploymentChange struct {
+	Type          string
+	EffectiveDate time.Time
+	DepartmentID  *uuid.UUID
+	Role          string
+	Status        string
+	JobTitle      string
+	Reason        string
+}
+
+// EmploymentState is an employee's situation on a given day as derived
+// from their history.
+type EmploymentState struct {
+	EmployeeID   uuid.UUID  `json:"employee_id"`
+	FirstName    string     `json:"first_name,omitempty"`
+	LastName     string     `json:"last_name,omitempty"`
+	AsOf         time.Time  `json:"as_of"`
+	Employed     bool       `json:"employed"`
+	HireDate     time.Time  `json:"hire_date"`
+	DepartmentID *uuid.UUID `json:"department_id"`
+	Role         string     `json:"role"`
+	Status       string     `json:"status"`
+	JobTitle     string     `json:"job_title"`
+}
+
+// DirectoryQuery asks for one page of the directory as it stood on Date.
+// DepartmentID matches the department employees were in that day.
+type DirectoryQuery struct {
+	Date         time.Time
+	DepartmentID *uuid.UUID
+	Page         int
+	PageSize     int
+}
+
+// DirectoryPage is one page of the directory as of a date and the total
+// number of employees employed that day.
+type DirectoryPage struct {
+	Employees []EmploymentState
+	Total     int64
+	Page      int
+	PageSize  int
+}
+
+// EmploymentHistoryService keeps the effective-dated history of every
+// employee and derives their current record from it.
+type EmploymentHistoryService interface {
+	// Record validates and stores a change. Changes effective today or
+	// earlier are applied at once; later ones wait for ApplyDue.
+	Record(employeeID uuid.UUID, change EmploymentChange, actorID uuid.UUID, scope Scope) (*models.EmploymentEvent, error)
+	// Prepare validates a change like Record but returns the event unsaved,
+	// for callers storing it along with records of their own. They hand it
+	// to Settle once stored.
+	Prepare(employeeID uuid.UUID, change EmploymentChange, actorID uuid.UUID, scope Scope) (*models.EmploymentEvent, error)
+	Settle(event *models.EmploymentEvent) error
+	// Track stores a change the caller has already written to the employee
+	// and user records, such as a hire or an accepted invitation.
+	Track(employeeID uuid.UUID, change EmploymentChange, actorID *uuid.UUID) error
+	Cancel(employeeID, eventID, actorID uuid.UUID, scope Scope) error
+	History(employeeID uuid.UUID, scope Scope) ([]models.EmploymentEvent, error)
+	StateAsOf(employeeID uuid.UUID, date time.Time, scope Scope) (*EmploymentState, error)
+	DirectoryAsOf(query DirectoryQuery, scope Scope) (*DirectoryPage, error)
+	// ApplyDue applies pending events whose effective date has come and
+	// returns how many it applied.
+	ApplyDue(now time.Time) (int, error)
+}
+
+type employmentHistoryService struct {
+	repo           repositories.EmploymentEventRepository
+	employeeRepo   repositories.EmployeeRepository
+	departmentRepo repositories.DepartmentRepository
+	auditSvc       AuditService
+	accessRevoker  AccessRevoker
+}
+
+// NewEmploymentHistoryService starts the job applying future-dated events,
+// every EMPLOYMENT_EVENTS_INTERVAL (15 minutes by default) and at every UTC
+// midnight, when events dated that day take effect.
+func NewEmploymentHistoryService(
+	repo repositories.EmploymentEventRepository,
+	employeeRepo repositories.EmployeeRepository,
+	departmentRepo repositories.DepartmentRepository,
+	auditSvc AuditService,
+	accessRevoker AccessRevoker,
+) EmploymentHistoryService {
+	s := &employmentHistoryService{
+		repo:           repo,
+		employeeRepo:   employeeRepo,
+		departmentRepo: departmentRepo,
+		auditSvc:       auditSvc,
+		accessRevoker:  accessRevoker,
+	}
+	go s.applyLoop(utils.GetEnvDuration("EMPLOYMENT_EVENTS_INTERVAL", 15*time.Minute))
+	return s
+}
+
+func (s *employmentHistoryService) Record(
+	employeeID uuid.UUID,
+	change EmploymentChange,
+	actorID uuid.UUID,
+	scope Scope,
+) (*models.EmploymentEvent, error) {
+	// A termination comes with an offboarding; see OffboardingService
+	if change.Type == models.EmploymentEventTermination {
+		return nil, errTerminationRecorded
+	}
+
+	event, err := s.Prepare(employeeID, change, actorID, scope)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(event); err != nil {
+		return nil, err
+	}
+	return event, s.Settle(event)
+}
+
+func (s *employmentHistoryService) Prepare(
+	employeeID uuid.UUID,
+	change EmploymentChange,
+	actorID uuid.UUID,
+	scope Scope,
+) (*models.EmploymentEvent, error) {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.AllowsEmployee(employee, actorID) {
+		return nil, ErrOutOfScope
+	}
+
+	now := time.Now().UTC()
+	effective := employmentDay(change.EffectiveDate)
+	if change.EffectiveDate.IsZero() {
+		effective = employmentDay(now)
+	}
+
+	events, err := s.repo.ListByEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	state, hired := foldEmploymentEvents(events, effective)
+	if !hired {
+		return nil, errBeforeHire
+	}
+	if err := checkEmployment(change.Type, state); err != nil {
+		return nil, err
+	}
+
+	event := &models.EmploymentEvent{
+		EmployeeID:    employeeID,
+		Type:          change.Type,
+		EffectiveDate: effective,
+		Reason:        strings.TrimSpace(change.Reason),
+		RecordedBy:    &actorID,
+	}
+	if err := s.describeChange(event, change, state, scope); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Settle audits a stored event and applies it if it is effective today or
+// earlier.
+func (s *employmentHistoryService) Settle(event *models.EmploymentEvent) error {
+	var actorID uuid.UUID
+	if event.RecordedBy != nil {
+		actorID = *event.RecordedBy
+	}
+	s.auditSvc.Log(actorID, "EMPLOYMENT_EVENT_RECORDED", "employee", &event.EmployeeID, employmentEventMetadata(event))
+
+	now := time.Now().UTC()
+	if event.EffectiveDate.After(employmentDay(now)) {
+		return nil
+	}
+	if err := s.apply(event.EmployeeID, []uuid.UUID{event.ID}, event.RecordedBy, now); err != nil {
+		return err
+	}
+	event.AppliedAt = &now
+	return nil
+}
+
+// checkEmployment rejects changes that do not fit the employee's situation
+// on the effective date. Terminated employees can only be rehired;
+// deactivated ones can also be terminated or have their status changed.
+func checkEmployment(changeType string, state EmploymentState) error {
+	switch {
+	case changeType == models.EmploymentEventRehire:
+		if state.Employed {
+			return errStillEmployed
+		}
+	case state.Employed:
+	case state.Status == "terminated":
+		return errNotEmployed
+	case changeType != models.EmploymentEventTermination && changeType != models.EmploymentEventStatusChange:
+		return errNotEmployed
+	}
+	return nil
+}
+
+// describeChange validates change against the state on its effective date
+// and copies the fields its type sets onto event.
+func (s *employmentHistoryService) describeChange(
+	event *models.EmploymentEvent,
+	change EmploymentChange,
+	state EmploymentState,
+	scope Scope,
+) error {
+	switch change.Type {
+	case models.EmploymentEventTransfer:
+		if change.DepartmentID == nil {
+			return errDepartmentRequired
+		}
+		if _, err := s.departmentRepo.FindByID(change.DepartmentID.String()); err != nil {
+			return err
+		}
+		if !scope.Allows(change.DepartmentID) {
+			return ErrOutOfScope
+		}
+		if state.DepartmentID != nil && *state.DepartmentID == *change.DepartmentID {
+			return errEventChangesNothing
+		}
+		event.DepartmentID = change.DepartmentID
+
+	case models.EmploymentEventPromotion:
+		title := strings.TrimSpace(change.JobTitle)
+		if title == "" {
+			return errJobTitleRequired
+		}
+		event.JobTitle = &title
+		if change.Role != "" {
+			if err := checkAssignableRole(change.Role, scope); err != nil {
+				return err
+			}
+			event.Role = &change.Role
+		}
+
+	case models.EmploymentEventRoleChange:
+		if change.Role == "" {
+			return errRoleRequired
+		}
+		if err := checkAssignableRole(change.Role, scope); err != nil {
+			return err
+		}
+		if change.Role == state.Role {
+			return errEventChangesNothing
+		}
+		event.Role = &change.Role
+
+	case models.EmploymentEventStatusChange:
+		if !slices.Contains(changeableStatuses, change.Status) {
+			return errInvalidStatus
+		}
+		if change.Status == state.Status {
+			return errEventChangesNothing
+		}
+		event.Status = &change.Status
+
+	case models.EmploymentEventTermination:
+		status := "terminated"
+		event.Status = &status
+
+	case models.EmploymentEventRehire:
+		if change.DepartmentID != nil {
+			if _, err := s.departmentRepo.FindByID(change.DepartmentID.String()); err != nil {
+				return err
+			}
+			if !scope.Allows(change.DepartmentID) {
+				return ErrOutOfScope
+			}
+			event.DepartmentID = change.DepartmentID
+		}
+		if change.Role != "" {
+			if err := checkAssignableRole(change.Role, scope); err != nil {
+				return err
+			}
+			event.Role = &change.Role
+		}
+		if title := strings.TrimSpace(change.JobTitle); title != "" {
+			event.JobTitle = &title
+		}
+		status := "active"
+		event.Status = &status
+
+	default:
+		return errUnknownEventType
+	}
+	return nil
+}
+
+func (s *employmentHistoryService) Track(employeeID uuid.UUID, change EmploymentChange, actorID *uuid.UUID) error {
+	return s.repo.Create(newAppliedEvent(employeeID, change, actorID))
+}
+
+// newAppliedEvent builds the event of a change that is already reflected in
+// the employee record.
+func newAppliedEvent(employeeID uuid.UUID, change EmploymentChange, actorID *uuid.UUID) *models.EmploymentEvent {
+	now := time.Now().UTC()
+	event := &models.EmploymentEvent{
+		EmployeeID:    employeeID,
+		Type:          change.Type,
+		EffectiveDate: employmentDay(now),
+		DepartmentID:  change.DepartmentID,
+		Reason:        change.Reason,
+		RecordedBy:    actorID,
+		AppliedAt:     &now,
+	}
+	if !change.EffectiveDate.IsZero() {
+		event.EffectiveDate = employmentDay(change.EffectiveDate)
+	}
+	if change.Role != "" {
+		event.Role = &change.Role
+	}
+	if change.Status != "" {
+		event.Status = &change.Status
+	}
+	if change.JobTitle != "" {
+		event.JobTitle = &change.JobTitle
+	}
+	return event
+}
+
+func (s *employmentHistoryService) Cancel(employeeID, eventID, actorID uuid.UUID, scope Scope) error {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return err
+	}
+	if !scope.AllowsEmployee(employee, actorID) {
+		return ErrOutOfScope
+	}
+
+	event, err := s.repo.FindByID(eventID)
+	if err != nil {
+		return err
+	}
+	if event.EmployeeID != employeeID {
+		return errEventNotPending
+	}
+
+	cancelled, err := s.repo.Cancel(eventID, actorID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return errEventNotPending
+	}
+
+	s.auditSvc.Log(actorID, "EMPLOYMENT_EVENT_CANCELLED", "employee", &employeeID, map[string]interface{}{
+		"event_id": eventID.String(),
+		"type":     event.Type,
+	})
+	return nil
+}
+
+func (s *employmentHistoryService) History(employeeID uuid.UUID, scope Scope) ([]models.EmploymentEvent, error) {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.reaches(employee) {
+		return nil, ErrOutOfScope
+	}
+	return s.repo.ListByEmployee(employeeID)
+}
+
+func (s *employmentHistoryService) StateAsOf(employeeID uuid.UUID, date time.Time, scope Scope) (*EmploymentState, error) {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.reaches(employee) {
+		return nil, ErrOutOfScope
+	}
+
+	events, err := s.repo.ListByEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	state, _ := foldEmploymentEvents(events, employmentDay(date))
+	state.EmployeeID = employee.ID
+	state.FirstName = employee.FirstName
+	state.LastName = employee.LastName
+	return &state, nil
+}
+
+// DirectoryAsOf lists a page of the employees employed on the query date
+// with their state that day. Scoped callers see the departments and reports
+// they reach today. Only the events of the page are loaded.
+func (s *employmentHistoryService) DirectoryAsOf(query DirectoryQuery, scope Scope) (*DirectoryPage, error) {
+	day := employmentDay(query.Date)
+	page := &DirectoryPage{
+		Employees: []EmploymentState{},
+		Page:      max(query.Page, 1),
+		PageSize:  query.PageSize,
+	}
+	if page.PageSize <= 0 {
+		page.PageSize = defaultEmployeePageSize
+	}
+	page.PageSize = min(page.PageSize, maxEmployeePageSize)
+
+	employeeScope, empty := scope.filter()
+	if empty {
+		return page, nil
+	}
+
+	employees, total, err := s.employeeRepo.Search(repositories.EmployeeFilter{
+		Scope:        employeeScope,
+		AsOf:         &day,
+		DepartmentID: query.DepartmentID,
+		OrderBy:      "employees.last_name, employees.first_name",
+		Limit:        page.PageSize,
+		Offset:       (page.Page - 1) * page.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	page.Total = total
+	if len(employees) == 0 {
+		return page, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(employees))
+	for _, employee := range employees {
+		ids = append(ids, employee.ID)
+	}
+	events, err := s.repo.ListEffective(day, ids)
+	if err != nil {
+		return nil, err
+	}
+	byEmployee := make(map[uuid.UUID][]models.EmploymentEvent)
+	for _, event := range events {
+		byEmployee[event.EmployeeID] = append(byEmployee[event.EmployeeID], event)
+	}
+
+	for _, employee := range employees {
+		state, _ := foldEmploymentEvents(byEmployee[employee.ID], day)
+		state.EmployeeID = employee.ID
+		state.FirstName = employee.FirstName
+		state.LastName = employee.LastName
+		page.Employees = append(page.Employees, state)
+	}
+	return page, nil
+}
+
+func (s *employmentHistoryService) ApplyDue(now time.Time) (int, error) {
+	due, err := s.repo.ListDue(now, 500)
+	if err != nil {
+		return 0, err
+	}
+
+	byEmployee := make(map[uuid.UUID][]models.EmploymentEvent)
+	for _, event := range due {
+		byEmployee[event.EmployeeID] = append(byEmployee[event.EmployeeID], event)
+	}
+
+	applied := 0
+	for employeeID, events := range byEmployee {
+		ids := make([]uuid.UUID, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		// The last event's recorder stands in as the actor for the audit trail
+		actorID := events[len(events)-1].RecordedBy
+
+		if err := s.apply(employeeID, ids, actorID, now); err != nil {
+			log.Printf("applying employment events of %s: %v", employeeID, err)
+			continue
+		}
+		applied += len(events)
+
+		if actorID != nil {
+			for _, event := range events {
+				metadata := employmentEventMetadata(&event)
+				metadata["event_id"] = event.ID.String()
+				s.auditSvc.Log(*actorID, "EMPLOYMENT_EVENT_APPLIED", "employee", &employeeID, metadata)
+			}
+		}
+	}
+	return applied, nil
+}
+
+// apply claims eventIDs and rewrites the employee and user records from the
+// history as of today. Claimed events are released again if that fails, so
+// the job retries them.
+func (s *employmentHistoryService) apply(employeeID uuid.UUID, eventIDs []uuid.UUID, actorID *uuid.UUID, now time.Time) error {
+	claimed, err := s.repo.Claim(eventIDs, now)
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return nil // applied elsewhere
+	}
+
+	if err := s.project(employeeID, actorID, now); err != nil {
+		if releaseErr := s.repo.Release(eventIDs); releaseErr != nil {
+			return fmt.Errorf("%v; releasing events: %v", err, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// project derives the employee's department, status, job title and hire
+// date, and the user's role, from the events effective today. An employee
+// whose employment has ended loses access at once.
+func (s *employmentHistoryService) project(employeeID uuid.UUID, actorID *uuid.UUID, now time.Time) error {
+	events, err := s.repo.ListByEmployee(employeeID)
+	if err != nil {
+		return err
+	}
+	state, hired := foldEmploymentEvents(events, employmentDay(now))
+	if !hired {
+		return nil
+	}
+
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return err
+	}
+
+	user := &employee.User
+	account := repositories.AccountChange{UserID: user.ID, At: now, By: actorID}
+	switch {
+	case !state.Employed && user.IsActive:
+		account.Deactivate = true
+	// Reactivated or rehired; invited employees are activated on accepting
+	case state.Status == "active" && !user.IsActive:
+		account.Reactivate = true
+	}
+	if state.Role != "" && state.Role != user.Role {
+		account.Role = state.Role
+	}
+
+	if err := s.employeeRepo.UpdateEmploymentState(employeeID, repositories.EmploymentState{
+		DepartmentID: state.DepartmentID,
+		Status:       state.Status,
+		JobTitle:     state.JobTitle,
+		HireDate:     state.HireDate,
+		Account:      &account,
+	}); err != nil {
+		return err
+	}
+	if account.Deactivate {
+		// The rows are revoked already; this drops the cached token state
+		return s.accessRevoker.RevokeUserAccess(user.ID)
+	}
+	return nil
+}
+
+func (s *employmentHistoryService) applyLoop(interval time.Duration) {
+	for {
+		if _, err := s.ApplyDue(time.Now().UTC()); err != nil {
+			log.Printf("applying employment events: %v", err)
+		}
+		time.Sleep(nextApplyDelay(time.Now().UTC(), interval))
+	}
+}
+
+// nextApplyDelay is interval, cut short at the next UTC midnight so a
+// termination ends access when the day starts rather than on a later run.
+func nextApplyDelay(now time.Time, interval time.Duration) time.Duration {
+	return min(interval, employmentDay(now).AddDate(0, 0, 1).Sub(now))
+}
+
+// foldEmploymentEvents replays events, sorted by effective date, up to and
+// including day. hired is false when no hire had taken effect by then. Once
+// terminated, only a rehire changes the state.
+func foldEmploymentEvents(events []models.EmploymentEvent, day time.Time) (state EmploymentState, hired bool) {
+	state.AsOf = day
+	for _, event := range events {
+		if event.CancelledAt != nil || event.EffectiveDate.After(day) {
+			continue
+		}
+		if state.Status == "terminated" && event.Type != models.EmploymentEventRehire {
+			continue
+		}
+		switch {
+		case event.Type == models.EmploymentEventHire:
+			hired = true
+			state.HireDate = event.EffectiveDate
+		case event.Type == models.EmploymentEventRehire && state.Status == "terminated":
+			state.HireDate = event.EffectiveDate
+		}
+		if event.DepartmentID != nil {
+			state.DepartmentID = event.DepartmentID
+		}
+		if event.Role != nil {
+			state.Role = *event.Role
+		}
+		if event.Status != nil {
+			state.Status = *event.Status
+		}
+		if event.JobTitle != nil {
+			state.JobTitle = *event.JobTitle
+		}
+	}
+	state.Employed = hired && !slices.Contains(endedStatuses, state.Status)
+	return state, hired
+}
+
+func employmentEventMetadata(event *models.EmploymentEvent) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"type":           event.Type,
+		"effective_date": event.EffectiveDate.Format("2006-01-02"),
+	}
+	if event.DepartmentID != nil {
+		metadata["department_id"] = event.DepartmentID.String()
+	}
+	if event.Role != nil {
+		metadata["role"] = *event.Role
+	}
+	if event.Status != nil {
+		metadata["status"] = *event.Status
+	}
+	if event.JobTitle != nil {
+		metadata["job_title"] = *event.JobTitle
+	}
+	if event.Reason != "" {
+		metadata["reason"] = event.Reason
+	}
+	return metadata
+}
+
+// employmentDay is the UTC calendar day events are dated by.
+func employmentDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
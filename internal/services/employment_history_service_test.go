@@ -0,0 +1,220 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+)
+
+// day parses a YYYY-MM-DD date in UTC.
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func text(s string) *string { return &s }
+
+func TestFoldEmploymentEventsReplaysHistoryAsOfDay(t *testing.T) {
+	sales, support := uuid.New(), uuid.New()
+	cancelledAt := day("2024-02-01")
+
+	events := []models.EmploymentEvent{
+		{Type: models.EmploymentEventHire, EffectiveDate: day("2024-01-15"), DepartmentID: &sales, Role: text("employee"), Status: text("active"), JobTitle: text("Sales Rep")},
+		{Type: models.EmploymentEventPromotion, EffectiveDate: day("2024-06-01"), JobTitle: text("Account Executive")},
+		{Type: models.EmploymentEventTransfer, EffectiveDate: day("2024-09-01"), DepartmentID: &support, CancelledAt: &cancelledAt},
+		{Type: models.EmploymentEventTermination, EffectiveDate: day("2025-03-31"), Status: text("terminated")},
+	}
+
+	if _, hired := foldEmploymentEvents(events, day("2024-01-01")); hired {
+		t.Fatal("employee should not be hired before the hire date")
+	}
+
+	state, hired := foldEmploymentEvents(events, day("2024-05-31"))
+	if !hired || !state.Employed || state.JobTitle != "Sales Rep" {
+		t.Fatalf("unexpected state before the promotion: %+v", state)
+	}
+
+	state, _ = foldEmploymentEvents(events, day("2024-12-01"))
+	if state.JobTitle != "Account Executive" || *state.DepartmentID != sales {
+		t.Fatalf("promotion should apply and the cancelled transfer should not: %+v", state)
+	}
+	if !state.HireDate.Equal(day("2024-01-15")) || state.Role != "employee" {
+		t.Fatalf("fields not set by later events should be kept: %+v", state)
+	}
+
+	state, _ = foldEmploymentEvents(events, day("2025-03-31"))
+	if state.Employed || state.Status != "terminated" {
+		t.Fatalf("employee should not be employed after termination: %+v", state)
+	}
+}
+
+func TestRehireStartsNewEmploymentOnlyAfterTermination(t *testing.T) {
+	hire := models.EmploymentEvent{Type: models.EmploymentEventHire, EffectiveDate: day("2020-03-01"), Role: text("employee"), Status: text("active")}
+	terminated := []models.EmploymentEvent{
+		hire,
+		{Type: models.EmploymentEventTermination, EffectiveDate: day("2023-07-01"), Status: text("terminated")},
+		{Type: models.EmploymentEventRehire, EffectiveDate: day("2024-02-01"), Status: text("active")},
+	}
+	state, _ := foldEmploymentEvents(terminated, day("2024-02-01"))
+	if !state.Employed || !state.HireDate.Equal(day("2024-02-01")) {
+		t.Fatalf("rehire after termination should start a new hire date: %+v", state)
+	}
+
+	deactivated := []models.EmploymentEvent{
+		hire,
+		{Type: models.EmploymentEventStatusChange, EffectiveDate: day("2023-07-01"), Status: text("inactive")},
+		{Type: models.EmploymentEventRehire, EffectiveDate: day("2024-02-01"), Status: text("active")},
+	}
+	state, _ = foldEmploymentEvents(deactivated, day("2024-02-01"))
+	if !state.Employed || !state.HireDate.Equal(day("2020-03-01")) {
+		t.Fatalf("reactivation should keep the original hire date: %+v", state)
+	}
+
+	cases := []struct {
+		changeType string
+		status     string
+		want       error
+	}{
+		{models.EmploymentEventRehire, "active", errStillEmployed},
+		{models.EmploymentEventRehire, "terminated", nil},
+		{models.EmploymentEventRehire, "inactive", nil},
+		{models.EmploymentEventTermination, "inactive", nil},
+		{models.EmploymentEventTermination, "terminated", errNotEmployed},
+		{models.EmploymentEventStatusChange, "inactive", nil},
+		{models.EmploymentEventTransfer, "inactive", errNotEmployed},
+	}
+	for _, tc := range cases {
+		state := EmploymentState{Status: tc.status, Employed: tc.status == "active"}
+		if err := checkEmployment(tc.changeType, state); err != tc.want {
+			t.Errorf("%s while %s: got %v, want %v", tc.changeType, tc.status, err, tc.want)
+		}
+	}
+}
+
+func TestFoldIgnoresEverythingButRehireAfterTermination(t *testing.T) {
+	sales, support := uuid.New(), uuid.New()
+	events := []models.EmploymentEvent{
+		{Type: models.EmploymentEventHire, EffectiveDate: day("2024-01-15"), DepartmentID: &sales, Role: text("employee"), Status: text("active")},
+		{Type: models.EmploymentEventTermination, EffectiveDate: day("2024-07-01"), Status: text("terminated")},
+		{Type: models.EmploymentEventTransfer, EffectiveDate: day("2024-08-01"), DepartmentID: &support},
+		{Type: models.EmploymentEventStatusChange, EffectiveDate: day("2024-09-01"), Status: text("active")},
+		{Type: models.EmploymentEventRoleChange, EffectiveDate: day("2024-09-01"), Role: text("manager")},
+	}
+
+	state, _ := foldEmploymentEvents(events, day("2024-10-01"))
+	if state.Employed || state.Status != "terminated" || *state.DepartmentID != sales || state.Role != "employee" {
+		t.Fatalf("events after the termination should be ignored: %+v", state)
+	}
+
+	rehired := append(events, models.EmploymentEvent{Type: models.EmploymentEventRehire, EffectiveDate: day("2024-11-01"), DepartmentID: &support, Status: text("active")})
+	state, _ = foldEmploymentEvents(rehired, day("2024-11-01"))
+	if !state.Employed || *state.DepartmentID != support || !state.HireDate.Equal(day("2024-11-01")) {
+		t.Fatalf("a rehire should start a new employment: %+v", state)
+	}
+}
+
+// newTestHistory wires the history service to in-memory repositories; the
+// employee repository writes its events to the same event store.
+func newTestHistory(departments *memoryDepartmentRepo) (*employmentHistoryService, *memoryEmployeeRepo, *memoryEventRepo) {
+	events := &memoryEventRepo{}
+	employees := newMemoryEmployeeRepo()
+	employees.events = events
+	return &employmentHistoryService{
+		repo:           events,
+		employeeRepo:   employees,
+		departmentRepo: departments,
+		auditSvc:       &memoryAudit{},
+		accessRevoker:  &memoryRevoker{},
+	}, employees, events
+}
+
+// hireEmployee stores an active employee hired on hireDate along with the
+// hire event.
+func hireEmployee(employees *memoryEmployeeRepo, departmentID uuid.UUID, role string, hireDate time.Time) *models.Employee {
+	employee := &models.Employee{FirstName: "Ada", LastName: "Lovelace", DepartmentID: &departmentID, Status: "active", HireDate: hireDate}
+	employee.ID = uuid.New()
+	employee.User = models.User{Role: role, IsActive: true}
+	employee.User.ID = uuid.New()
+	employees.employees[employee.ID] = employee
+	_ = employees.events.Create(newAppliedEvent(employee.ID, EmploymentChange{
+		Type:          models.EmploymentEventHire,
+		EffectiveDate: hireDate,
+		DepartmentID:  &departmentID,
+		Role:          role,
+		Status:        "active",
+	}, nil))
+	return employee
+}
+
+func TestDirectoryAsOfLoadsOnlyThePage(t *testing.T) {
+	departments := newMemoryDepartmentRepo("Sales")
+	sales := departments.departments[0].ID
+	history, employees, events := newTestHistory(departments)
+
+	hired := day("2024-01-15")
+	first := hireEmployee(employees, sales, authz.RoleEmployee, hired)
+	second := hireEmployee(employees, sales, authz.RoleEmployee, hired)
+	hireEmployee(employees, sales, authz.RoleEmployee, hired)
+	employees.page = []*models.Employee{first, second}
+
+	counting := &countingEventRepo{memoryEventRepo: events}
+	history.repo = counting
+
+	page, err := history.DirectoryAsOf(DirectoryQuery{Date: day("2024-06-01").Add(12 * time.Hour), DepartmentID: &sales, Page: 3, PageSize: 2}, GlobalScope())
+	if err != nil {
+		t.Fatalf("directory: %v", err)
+	}
+
+	filter := employees.searches[0]
+	if filter.AsOf == nil || !filter.AsOf.Equal(day("2024-06-01")) {
+		t.Fatalf("expected the search to filter by the day, got %v", filter.AsOf)
+	}
+	if filter.DepartmentID == nil || *filter.DepartmentID != sales || filter.Limit != 2 || filter.Offset != 4 {
+		t.Fatalf("expected department and paging in the search, got %+v", filter)
+	}
+	if counting.loaded != 2 {
+		t.Fatalf("expected the events of the 2 employees on the page, loaded %d", counting.loaded)
+	}
+	if len(page.Employees) != 2 || page.Employees[0].EmployeeID != first.ID || !page.Employees[1].Employed {
+		t.Fatalf("unexpected page: %+v", page.Employees)
+	}
+}
+
+// countingEventRepo counts the employees whose effective events are loaded.
+type countingEventRepo struct {
+	*memoryEventRepo
+	loaded int
+}
+
+func (r *countingEventRepo) ListEffective(asOf time.Time, employeeIDs []uuid.UUID) ([]models.EmploymentEvent, error) {
+	r.loaded += len(employeeIDs)
+	return r.memoryEventRepo.ListEffective(asOf, employeeIDs)
+}
+
+func TestNextApplyDelayStopsAtMidnight(t *testing.T) {
+	interval := 15 * time.Minute
+	if got := nextApplyDelay(day("2024-07-01").Add(10*time.Hour), interval); got != interval {
+		t.Fatalf("expected the interval during the day, got %s", got)
+	}
+	lateEvening := day("2024-07-01").Add(23*time.Hour + 55*time.Minute)
+	if got := nextApplyDelay(lateEvening, interval); got != 5*time.Minute {
+		t.Fatalf("expected to wake at midnight, got %s", got)
+	}
+
+	// Applying at the cutoff makes a termination dated that day due.
+	svc, employee, events, _ := newTestOffboarding()
+	_ = events.Create(&models.EmploymentEvent{EmployeeID: employee.ID, Type: models.EmploymentEventTermination, EffectiveDate: day("2024-07-02"), Status: text("terminated")})
+	if applied, err := svc.history.ApplyDue(lateEvening.Add(nextApplyDelay(lateEvening, interval))); err != nil || applied != 1 {
+		t.Fatalf("expected the termination to apply at midnight, got %d, %v", applied, err)
+	}
+	if stored, _ := svc.employeeRepo.FindByID(employee.ID); stored.User.IsActive {
+		t.Fatal("expected the account to be deactivated at the cutoff")
+	}
+}
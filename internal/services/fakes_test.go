@@ -0,0 +1,419 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+// In-memory stand-ins for the repositories and services the tests touch.
+// Each embeds its interface, so calling a method a test did not expect
+// panics instead of silently passing.
+
+type memoryAudit struct {
+	AuditService
+	actions []string
+}
+
+func (a *memoryAudit) Log(userID uuid.UUID, action, entity string, entityID *uuid.UUID, metadata map[string]interface{}) {
+	a.actions = append(a.actions, action)
+}
+
+func (a *memoryAudit) logged(action string) bool {
+	return slices.Contains(a.actions, action)
+}
+
+type memoryUserRepo struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func newMemoryUserRepo(users ...*models.User) *memoryUserRepo {
+	repo := &memoryUserRepo{users: map[uuid.UUID]*models.User{}}
+	for _, user := range users {
+		if user.ID == uuid.Nil {
+			user.ID = uuid.New()
+		}
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *memoryUserRepo) Create(user *models.User) error {
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepo) Update(user *models.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepo) FindByID(id uuid.UUID) (*models.User, error) {
+	if user, ok := r.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryUserRepo) FindByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryUserRepo) UpdateRole(id uuid.UUID, role string) error {
+	if user, ok := r.users[id]; ok {
+		user.Role = role
+		user.PermissionVersion++
+	}
+	return nil
+}
+
+// hmacTokenKeys signs with a shared secret instead of rotating key pairs.
+type hmacTokenKeys struct {
+	utils.HMACKey
+}
+
+func (hmacTokenKeys) JWKS() utils.JWKS { return utils.JWKS{} }
+
+func (hmacTokenKeys) Rotate() error { return nil }
+
+type memoryDepartmentRepo struct {
+	repositories.DepartmentRepository
+	departments []models.Department
+}
+
+func newMemoryDepartmentRepo(names ...string) *memoryDepartmentRepo {
+	repo := &memoryDepartmentRepo{}
+	for _, name := range names {
+		department := models.Department{Name: name}
+		department.ID = uuid.New()
+		repo.departments = append(repo.departments, department)
+	}
+	return repo
+}
+
+func (r *memoryDepartmentRepo) List() ([]models.Department, error) {
+	return append([]models.Department{}, r.departments...), nil
+}
+
+func (r *memoryDepartmentRepo) FindByID(id string) (*models.Department, error) {
+	for _, department := range r.departments {
+		if department.ID.String() == id {
+			copied := department
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// memoryEmployeeRepo stores the events it writes along with employees in
+// events, when set, as the real repository writes them in one transaction.
+type memoryEmployeeRepo struct {
+	repositories.EmployeeRepository
+	employees map[uuid.UUID]*models.Employee
+	events    *memoryEventRepo
+	hires     []repositories.NewHire
+	searches  []repositories.EmployeeFilter
+	page      []*models.Employee
+	states    []repositories.EmploymentState
+}
+
+func newMemoryEmployeeRepo(employees ...*models.Employee) *memoryEmployeeRepo {
+	repo := &memoryEmployeeRepo{employees: map[uuid.UUID]*models.Employee{}}
+	for _, employee := range employees {
+		if employee.ID == uuid.Nil {
+			employee.ID = uuid.New()
+		}
+		repo.employees[employee.ID] = employee
+	}
+	return repo
+}
+
+func (r *memoryEmployeeRepo) FindByID(id uuid.UUID) (*models.Employee, error) {
+	if employee, ok := r.employees[id]; ok {
+		copied := *employee
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Search returns page, whatever the filter.
+func (r *memoryEmployeeRepo) Search(filter repositories.EmployeeFilter) ([]*models.Employee, int64, error) {
+	r.searches = append(r.searches, filter)
+	return r.page, int64(len(r.page)), nil
+}
+
+func (r *memoryEmployeeRepo) UpdateDetails(employee *models.Employee, events []*models.EmploymentEvent) error {
+	stored := r.employees[employee.ID]
+	stored.FirstName, stored.LastName = employee.FirstName, employee.LastName
+	for _, event := range events {
+		_ = r.events.Create(event)
+	}
+	return nil
+}
+
+func (r *memoryEmployeeRepo) UpdateEmploymentState(employeeID uuid.UUID, state repositories.EmploymentState) error {
+	r.states = append(r.states, state)
+	employee := r.employees[employeeID]
+	employee.DepartmentID = state.DepartmentID
+	employee.Status = state.Status
+	employee.JobTitle = state.JobTitle
+	employee.HireDate = state.HireDate
+	if account := state.Account; account != nil {
+		if account.Deactivate {
+			employee.User.IsActive = false
+		}
+		if account.Reactivate {
+			employee.User.IsActive = true
+		}
+		if account.Role != "" {
+			employee.User.Role = account.Role
+		}
+	}
+	return nil
+}
+
+// CreateHires stores every hire, as the real repository does in one
+// transaction.
+func (r *memoryEmployeeRepo) CreateHires(hires []repositories.NewHire) error {
+	for _, hire := range hires {
+		if hire.User.ID == uuid.Nil {
+			hire.User.ID = uuid.New()
+		}
+		hire.Employee.UserID = hire.User.ID
+		if hire.Employee.ID == uuid.Nil {
+			hire.Employee.ID = uuid.New()
+		}
+		if hire.Event != nil {
+			hire.Event.EmployeeID = hire.Employee.ID
+			if r.events != nil {
+				_ = r.events.Create(hire.Event)
+			}
+		}
+		if hire.Audit != nil {
+			hire.Audit.EntityID = &hire.Employee.ID
+		}
+		r.employees[hire.Employee.ID] = hire.Employee
+		r.hires = append(r.hires, hire)
+	}
+	return nil
+}
+
+type memoryEventRepo struct {
+	repositories.EmploymentEventRepository
+	events []*models.EmploymentEvent
+}
+
+func (r *memoryEventRepo) Create(event *models.EmploymentEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryEventRepo) FindByID(id uuid.UUID) (*models.EmploymentEvent, error) {
+	for _, event := range r.events {
+		if event.ID == id {
+			copied := *event
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListByEmployee keeps the order events were created in within a day.
+func (r *memoryEventRepo) ListByEmployee(employeeID uuid.UUID) ([]models.EmploymentEvent, error) {
+	var events []models.EmploymentEvent
+	for _, event := range r.events {
+		if event.EmployeeID == employeeID {
+			events = append(events, *event)
+		}
+	}
+	slices.SortStableFunc(events, func(a, b models.EmploymentEvent) int {
+		return a.EffectiveDate.Compare(b.EffectiveDate)
+	})
+	return events, nil
+}
+
+func (r *memoryEventRepo) ListEffective(asOf time.Time, employeeIDs []uuid.UUID) ([]models.EmploymentEvent, error) {
+	var events []models.EmploymentEvent
+	for _, employeeID := range employeeIDs {
+		history, _ := r.ListByEmployee(employeeID)
+		for _, event := range history {
+			if event.CancelledAt == nil && !event.EffectiveDate.After(asOf) {
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
+
+func (r *memoryEventRepo) ListDue(asOf time.Time, limit int) ([]models.EmploymentEvent, error) {
+	var due []models.EmploymentEvent
+	for _, event := range r.events {
+		if event.Pending() && !event.EffectiveDate.After(asOf) && len(due) < limit {
+			due = append(due, *event)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryEventRepo) Claim(ids []uuid.UUID, at time.Time) (int64, error) {
+	var claimed int64
+	for _, event := range r.events {
+		if slices.Contains(ids, event.ID) && event.Pending() {
+			event.AppliedAt = &at
+			claimed++
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memoryEventRepo) Cancel(id, cancelledBy uuid.UUID, at time.Time) (bool, error) {
+	for _, event := range r.events {
+		if event.ID == id && event.Pending() {
+			event.CancelledAt = &at
+			event.CancelledBy = &cancelledBy
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryRevoker struct {
+	revoked []uuid.UUID
+}
+
+func (r *memoryRevoker) RevokeUserAccess(userID uuid.UUID) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+type memoryInvites struct {
+	InvitationService
+	invited []uuid.UUID
+}
+
+func (s *memoryInvites) Invite(user *models.User, employee *models.Employee, adminID uuid.UUID) error {
+	s.invited = append(s.invited, employee.ID)
+	return nil
+}
+
+// memoryOffboardingRepo writes termination events to events and cancels the
+// pending ones they supersede, like the real repository.
+type memoryOffboardingRepo struct {
+	repositories.OffboardingRepository
+	events       *memoryEventRepo
+	offboardings []*models.Offboarding
+	templates    []models.OffboardingTaskTemplate
+}
+
+func (r *memoryOffboardingRepo) Create(offboarding *models.Offboarding, at time.Time) ([]models.EmploymentEvent, error) {
+	event := &offboarding.Event
+	var superseded []models.EmploymentEvent
+	for _, pending := range r.events.events {
+		if pending.EmployeeID == event.EmployeeID && pending.Pending() && !pending.EffectiveDate.Before(event.EffectiveDate) {
+			pending.CancelledAt, pending.CancelledBy = &at, event.RecordedBy
+			superseded = append(superseded, *pending)
+		}
+	}
+	_ = r.events.Create(event)
+	offboarding.ID = uuid.New()
+	offboarding.EventID = event.ID
+	for i := range offboarding.Tasks {
+		offboarding.Tasks[i].ID = uuid.New()
+		offboarding.Tasks[i].OffboardingID = offboarding.ID
+	}
+	r.offboardings = append(r.offboardings, offboarding)
+	return superseded, nil
+}
+
+// FindCurrent reads the event back, so cancellations show.
+func (r *memoryOffboardingRepo) FindCurrent(employeeID uuid.UUID) (*models.Offboarding, error) {
+	for i := len(r.offboardings) - 1; i >= 0; i-- {
+		offboarding := *r.offboardings[i]
+		event, _ := r.events.FindByID(offboarding.EventID)
+		if offboarding.EmployeeID == employeeID && event.CancelledAt == nil {
+			offboarding.Event = *event
+			return &offboarding, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindTask loads the task with its offboarding and event, as preloaded by
+// the real repository.
+func (r *memoryOffboardingRepo) FindTask(id uuid.UUID) (*models.OffboardingTask, error) {
+	for _, offboarding := range r.offboardings {
+		for _, task := range offboarding.Tasks {
+			if task.ID == id {
+				loaded := *offboarding
+				event, _ := r.events.FindByID(offboarding.EventID)
+				loaded.Event = *event
+				task.Offboarding = &loaded
+				return &task, nil
+			}
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOffboardingRepo) CompleteTask(id, completedBy uuid.UUID, at time.Time) (bool, error) {
+	for _, offboarding := range r.offboardings {
+		for i := range offboarding.Tasks {
+			task := &offboarding.Tasks[i]
+			if task.ID == id && task.CompletedAt == nil {
+				task.CompletedAt, task.CompletedBy = &at, &completedBy
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryOffboardingRepo) ListTemplates() ([]models.OffboardingTaskTemplate, error) {
+	return r.templates, nil
+}
+
+type memoryAPIKeyRepo struct {
+	repositories.APIKeyRepository
+	accounts map[uuid.UUID]*models.ServiceAccount
+	keys     []*models.APIKey
+}
+
+func (r *memoryAPIKeyRepo) FindServiceAccount(id uuid.UUID) (*models.ServiceAccount, error) {
+	if account, ok := r.accounts[id]; ok {
+		copied := *account
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAPIKeyRepo) Create(key *models.APIKey) error {
+	key.ID = uuid.New()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+// adminScope is the scope ScopeFor gives an admin.
+func adminScope() Scope {
+	return Scope{Global: true, Permissions: authz.PermissionsForRole(authz.RoleAdmin)}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var (
+	errImpersonateSelf   = errors.New("cannot impersonate yourself")
+	errImpersonateTarget = errors.New("this user cannot be impersonated")
+)
+
+// ImpersonationToken is a short-lived access token that acts as the target
+// user on behalf of the real actor. It cannot be refreshed.
+type ImpersonationToken struct {
+	AccessToken string    `json:"access_token"`
+	UserID      uuid.UUID `json:"user_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ReadOnly    bool      `json:"read_only"`
+}
+
+// ImpersonationService lets an admin see the API exactly as another user
+// does. Everything done with the token is attributed to the real actor.
+type ImpersonationService interface {
+	Start(actorID, targetUserID uuid.UUID, reason, ipAddress string) (*ImpersonationToken, error)
+	Stop(actorID, targetUserID uuid.UUID, jti string, expiresAt time.Time) error
+	ReadOnly() bool
+	RecordRequest(actorID, targetUserID uuid.UUID, method, path string, status int, ipAddress string)
+}
+
+type impersonationService struct {
+	userRepo     repositories.UserRepository
+	employeeRepo repositories.EmployeeRepository
+	denylist     TokenDenylist
+	tokenKeys    TokenKeyService
+	auditSvc     AuditService
+	ttl          time.Duration
+	readOnly     bool
+}
+
+func NewImpersonationService(
+	userRepo repositories.UserRepository,
+	employeeRepo repositories.EmployeeRepository,
+	denylist TokenDenylist,
+	tokenKeys TokenKeyService,
+	auditSvc AuditService,
+) ImpersonationService {
+	return &impersonationService{
+		userRepo:     userRepo,
+		employeeRepo: employeeRepo,
+		denylist:     denylist,
+		tokenKeys:    tokenKeys,
+		auditSvc:     auditSvc,
+		ttl:          utils.GetEnvDuration("IMPERSONATION_TTL", 15*time.Minute),
+		readOnly:     !strings.EqualFold(utils.GetEnv("IMPERSONATION_ALLOW_WRITES", "false"), "true"),
+	}
+}
+
+// Start issues an impersonation token for an active employee account. Admins,
+// service accounts and anyone who may impersonate cannot be impersonated, so
+// impersonation never chains.
+func (s *impersonationService) Start(actorID, targetUserID uuid.UUID, reason, ipAddress string) (*ImpersonationToken, error) {
+	if actorID == targetUserID {
+		return nil, errImpersonateSelf
+	}
+
+	target, err := s.userRepo.FindByID(targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !target.IsActive ||
+		authz.HasRole(target.Role, authz.RoleAdmin, authz.RoleServiceAccount) ||
+		authz.HasPermission(authz.PermissionsForRole(target.Role), authz.PermImpersonateUsers) {
+		return nil, errImpersonateTarget
+	}
+
+	employee, err := s.employeeRepo.FindByUserID(target.ID)
+	if err != nil {
+		return nil, errors.New("employee record not found")
+	}
+
+	expiresAt := time.Now().UTC().Add(s.ttl)
+	claims := &utils.JWTClaims{
+		UserID:            target.ID.String(),
+		Role:              target.Role,
+		EmployeeID:        employee.ID.String(),
+		Permissions:       authz.PermissionsForRole(target.Role),
+		TokenType:         utils.TokenTypeAccess,
+		ImpersonatorID:    actorID.String(),
+		PermissionVersion: target.PermissionVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := s.tokenKeys.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(actorID, "IMPERSONATION_STARTED", "user", &target.ID, map[string]interface{}{
+		"token_id":   claims.ID,
+		"reason":     strings.TrimSpace(reason),
+		"read_only":  s.readOnly,
+		"expires_at": expiresAt,
+		"ip_address": ipAddress,
+	})
+
+	return &ImpersonationToken{
+		AccessToken: token,
+		UserID:      target.ID,
+		ExpiresAt:   expiresAt,
+		ReadOnly:    s.readOnly,
+	}, nil
+}
+
+// Stop revokes the impersonation token before it expires.
+func (s *impersonationService) Stop(actorID, targetUserID uuid.UUID, jti string, expiresAt time.Time) error {
+	if err := s.denylist.RevokeToken(jti, targetUserID, expiresAt); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(actorID, "IMPERSONATION_ENDED", "user", &targetUserID, map[string]interface{}{
+		"token_id": jti,
+	})
+	return nil
+}
+
+// ReadOnly reports whether write requests are refused under impersonation.
+func (s *impersonationService) ReadOnly() bool {
+	return s.readOnly
+}
+
+// RecordRequest logs a call made under impersonation against the real actor.
+func (s *impersonationService) RecordRequest(actorID, targetUserID uuid.UUID, method, path string, status int, ipAddress string) {
+	s.auditSvc.Log(actorID, "IMPERSONATED_REQUEST", "user", &targetUserID, map[string]interface{}{
+		"method":     method,
+		"path":       path,
+		"status":     status,
+		"ip_address": ipAddress,
+	})
+}
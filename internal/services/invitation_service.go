@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/mailer"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var (
+	errInvalidInvitation = errors.New("invalid or expired invitation")
+	errNoPendingInvite   = errors.New("employee has no pending invitation")
+)
+
+// InvitationStatus describes the latest invitation of an employee.
+type InvitationStatus struct {
+	InvitationID uuid.UUID  `json:"invitation_id"`
+	EmployeeID   uuid.UUID  `json:"employee_id"`
+	Email        string     `json:"email"`
+	Status       string     `json:"status"`
+	SentAt       time.Time  `json:"sent_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+}
+
+// InvitationService onboards new hires: they receive a single-use link and
+// choose their own password, so admins never know initial credentials.
+type InvitationService interface {
+	Invite(user *models.User, employee *models.Employee, adminID uuid.UUID) error
+	Accept(token, password string) error
+	Resend(employeeID, adminID uuid.UUID, scope Scope) error
+	Revoke(employeeID, adminID uuid.UUID, scope Scope) error
+	Status(employeeID uuid.UUID, scope Scope) (*InvitationStatus, error)
+	List(scope Scope) ([]InvitationStatus, error)
+}
+
+type invitationService struct {
+	inviteRepo   repositories.InvitationRepository
+	userRepo     repositories.UserRepository
+	employeeRepo repositories.EmployeeRepository
+	passwordSvc  PasswordService
+	auditSvc     AuditService
+	mailer       mailer.Mailer
+	baseURL      string
+	ttl          time.Duration
+}
+
+func NewInvitationService(
+	inviteRepo repositories.InvitationRepository,
+	userRepo repositories.UserRepository,
+	employeeRepo repositories.EmployeeRepository,
+	passwordSvc PasswordService,
+	auditSvc AuditService,
+	mail mailer.Mailer,
+) InvitationService {
+	return &invitationService{
+		inviteRepo:   inviteRepo,
+		userRepo:     userRepo,
+		employeeRepo: employeeRepo,
+		passwordSvc:  passwordSvc,
+		auditSvc:     auditSvc,
+		mailer:       mail,
+		baseURL:      strings.TrimRight(utils.GetEnv("APP_BASE_URL", "http://localhost:5173"), "/"),
+		ttl:          utils.GetEnvDuration("INVITE_TTL", 72*time.Hour),
+	}
+}
+
+// Invite revokes any pending invitation of the user and mails a new one.
+func (s *invitationService) Invite(user *models.User, employee *models.Employee, adminID uuid.UUID) error {
+	now := time.Now().UTC()
+	if err := s.inviteRepo.RevokePendingForUser(user.ID, now); err != nil {
+		return err
+	}
+
+	rawToken, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return err
+	}
+
+	invitation := &models.Invitation{
+		UserID:     user.ID,
+		EmployeeID: employee.ID,
+		Email:      user.Email,
+		TokenHash:  utils.HashToken(rawToken),
+		ExpiresAt:  now.Add(s.ttl),
+		InvitedBy:  &adminID,
+	}
+	if err := s.inviteRepo.Create(invitation); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/accept-invite?token=" + url.QueryEscape(rawToken)
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "You're invited to StaffPoint",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nAn account has been created for you on StaffPoint.\n\n"+
+				"Open the link below to choose your password. It expires in %s and can be used once.\n\n%s\n",
+			employee.FirstName, s.ttl, link,
+		),
+	}); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(adminID, "INVITE_SENT", "employee", &employee.ID, map[string]interface{}{
+		"invitation_id": invitation.ID.String(),
+		"email":         user.Email,
+	})
+	return nil
+}
+
+// Accept consumes the invitation, sets the password chosen by the new hire
+// and activates the account.
+func (s *invitationService) Accept(token, password string) error {
+	invitation, err := s.inviteRepo.FindByHash(utils.HashToken(token))
+	if err != nil || invitation.Status(time.Now()) != models.InvitationPending {
+		return errInvalidInvitation
+	}
+
+	user, err := s.userRepo.FindByID(invitation.UserID)
+	if err != nil {
+		return errInvalidInvitation
+	}
+	employee, err := s.employeeRepo.FindByID(invitation.EmployeeID)
+	if err != nil || employee.Status != "invited" {
+		return errInvalidInvitation
+	}
+
+	// Reject a weak password before the invitation is spent
+	if err := s.passwordSvc.Validate(user, password); err != nil {
+		return err
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	// Spend the invitation, set the password and activate the account
+	// together, so a failure leaves the invitation usable
+	accepted, err := s.inviteRepo.Accept(repositories.InvitationAcceptance{
+		InvitationID:     invitation.ID,
+		UserID:           user.ID,
+		EmployeeID:       employee.ID,
+		PasswordHash:     hash,
+		RememberPassword: s.passwordSvc.Policy().HistorySize > 0,
+		Event: newAppliedEvent(employee.ID, EmploymentChange{
+			Type:   models.EmploymentEventStatusChange,
+			Status: "active",
+			Reason: "invitation accepted",
+		}, &user.ID),
+		At: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return errInvalidInvitation
+	}
+
+	s.auditSvc.Log(user.ID, "INVITE_ACCEPTED", "employee", &employee.ID, map[string]interface{}{
+		"invitation_id": invitation.ID.String(),
+	})
+	return nil
+}
+
+func (s *invitationService) Resend(employeeID, adminID uuid.UUID, scope Scope) error {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return err
+	}
+	if !scope.AllowsEmployee(employee, adminID) {
+		return ErrOutOfScope
+	}
+	if employee.Status != "invited" {
+		return errNoPendingInvite
+	}
+
+	user, err := s.userRepo.FindByID(employee.UserID)
+	if err != nil {
+		return err
+	}
+
+	return s.Invite(user, employee, adminID)
+}
+
+// Revoke invalidates the pending invitation. The account stays inactive
+// until a new invitation is sent and accepted.
+func (s *invitationService) Revoke(employeeID, adminID uuid.UUID, scope Scope) error {
+	if err := s.checkScope(employeeID, adminID, scope); err != nil {
+		return err
+	}
+
+	invitation, err := s.inviteRepo.FindLatestForEmployee(employeeID)
+	if err != nil || invitation.Status(time.Now()) != models.InvitationPending {
+		return errNoPendingInvite
+	}
+
+	if err := s.inviteRepo.RevokePendingForUser(invitation.UserID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(adminID, "INVITE_REVOKED", "employee", &employeeID, map[string]interface{}{
+		"invitation_id": invitation.ID.String(),
+	})
+	return nil
+}
+
+func (s *invitationService) Status(employeeID uuid.UUID, scope Scope) (*InvitationStatus, error) {
+	if err := s.checkScope(employeeID, uuid.Nil, scope); err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.inviteRepo.FindLatestForEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	status := invitationStatus(invitation, time.Now())
+	return &status, nil
+}
+
+func (s *invitationService) List(scope Scope) ([]InvitationStatus, error) {
+	employeeScope, empty := scope.filter()
+	if empty {
+		return []InvitationStatus{}, nil
+	}
+
+	invitations, err := s.inviteRepo.ListLatest(employeeScope)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	statuses := make([]InvitationStatus, 0, len(invitations))
+	for i := range invitations {
+		statuses = append(statuses, invitationStatus(&invitations[i], now))
+	}
+	return statuses, nil
+}
+
+func (s *invitationService) checkScope(employeeID, actorID uuid.UUID, scope Scope) error {
+	if scope.Global {
+		return nil
+	}
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return err
+	}
+	if !scope.AllowsEmployee(employee, actorID) {
+		return ErrOutOfScope
+	}
+	return nil
+}
+
+func invitationStatus(invitation *models.Invitation, now time.Time) InvitationStatus {
+	return InvitationStatus{
+		InvitationID: invitation.ID,
+		EmployeeID:   invitation.EmployeeID,
+		Email:        invitation.Email,
+		Status:       invitation.Status(now),
+		SentAt:       invitation.CreatedAt,
+		ExpiresAt:    invitation.ExpiresAt,
+		AcceptedAt:   invitation.AcceptedAt,
+	}
+}
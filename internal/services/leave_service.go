@@ -14,19 +14,25 @@ import (
 type LeaveService interface {
 	RequestLeave(userID, employeeID uuid.UUID, startDate, endDate time.Time, reason string) (*models.LeaveRequest, error)
 	ListMine(userID uuid.UUID, limit int) ([]models.LeaveRequest, error)
-	ListAll(status string, limit int) ([]models.LeaveRequest, error)
-	ReviewLeave(leaveID, reviewerID uuid.UUID, status string) (*models.LeaveRequest, error)
+	ListAll(status string, limit int, scope Scope) ([]models.LeaveRequest, error)
+	ListAssigned(reviewerID uuid.UUID, status string, limit int, scope Scope) ([]models.LeaveRequest, error)
+	ReviewLeave(leaveID, reviewerID uuid.UUID, status string, scope Scope) (*models.LeaveRequest, error)
 	CancelMyLeave(leaveID, userID uuid.UUID) (*models.LeaveRequest, error)
-	PendingCount() (int64, error)
+	PendingCount(scope Scope) (int64, error)
 }
 
 type leaveService struct {
-	repo     repositories.LeaveRepository
-	auditSvc AuditService
+	repo         repositories.LeaveRepository
+	employeeRepo repositories.EmployeeRepository
+	auditSvc     AuditService
 }
 
-func NewLeaveService(repo repositories.LeaveRepository, auditSvc AuditService) LeaveService {
-	return &leaveService{repo: repo, auditSvc: auditSvc}
+func NewLeaveService(
+	repo repositories.LeaveRepository,
+	employeeRepo repositories.EmployeeRepository,
+	auditSvc AuditService,
+) LeaveService {
+	return &leaveService{repo: repo, employeeRepo: employeeRepo, auditSvc: auditSvc}
 }
 
 func (s *leaveService) RequestLeave(userID, employeeID uuid.UUID, startDate, endDate time.Time, reason string) (*models.LeaveRequest, error) {
@@ -37,6 +43,11 @@ func (s *leaveService) RequestLeave(userID, employeeID uuid.UUID, startDate, end
 		return nil, errors.New("end_date must be on or after start_date")
 	}
 
+	approverID, err := s.approverFor(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
 	leave := &models.LeaveRequest{
 		UserID:     userID,
 		EmployeeID: employeeID,
@@ -44,29 +55,68 @@ func (s *leaveService) RequestLeave(userID, employeeID uuid.UUID, startDate, end
 		EndDate:    endDate.UTC(),
 		Reason:     strings.TrimSpace(reason),
 		Status:     "pending",
+		ApproverID: approverID,
 	}
 
 	if err := s.repo.Create(leave); err != nil {
 		return nil, err
 	}
 
-	s.auditSvc.Log(userID, "LEAVE_REQUESTED", "leave_request", &leave.ID, map[string]interface{}{
+	metadata := map[string]interface{}{
 		"start_date": leave.StartDate.Format("2006-01-02"),
 		"end_date":   leave.EndDate.Format("2006-01-02"),
-	})
+	}
+	if approverID != nil {
+		metadata["approver_id"] = approverID.String()
+	}
+	s.auditSvc.Log(userID, "LEAVE_REQUESTED", "leave_request", &leave.ID, metadata)
 
 	return leave, nil
 }
 
+// approverFor routes a request to the employee's manager. Requests of
+// employees without an active manager go to their department's managers.
+func (s *leaveService) approverFor(employeeID uuid.UUID) (*uuid.UUID, error) {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if employee.ManagerID == nil {
+		return nil, nil
+	}
+
+	manager, err := s.employeeRepo.FindByID(*employee.ManagerID)
+	if err != nil {
+		return nil, err
+	}
+	if !manager.User.IsActive {
+		return nil, nil
+	}
+	return &manager.UserID, nil
+}
+
 func (s *leaveService) ListMine(userID uuid.UUID, limit int) ([]models.LeaveRequest, error) {
 	return s.repo.ListByUser(userID, limit)
 }
 
-func (s *leaveService) ListAll(status string, limit int) ([]models.LeaveRequest, error) {
-	return s.repo.ListAll(status, limit)
+func (s *leaveService) ListAll(status string, limit int, scope Scope) ([]models.LeaveRequest, error) {
+	employeeScope, empty := scope.filter()
+	if empty {
+		return []models.LeaveRequest{}, nil
+	}
+	return s.repo.ListAll(status, limit, employeeScope)
+}
+
+// ListAssigned returns the requests routed to the reviewer or to anyone who
+// delegated to them.
+func (s *leaveService) ListAssigned(reviewerID uuid.UUID, status string, limit int, scope Scope) ([]models.LeaveRequest, error) {
+	approverIDs := append([]uuid.UUID{reviewerID}, scope.OnBehalfOf...)
+	return s.repo.ListByApprover(approverIDs, status, limit)
 }
 
-func (s *leaveService) ReviewLeave(leaveID, reviewerID uuid.UUID, status string) (*models.LeaveRequest, error) {
+// ReviewLeave approves or rejects a pending request of an employee within
+// the reviewer's scope, or one routed to the reviewer or their delegators.
+func (s *leaveService) ReviewLeave(leaveID, reviewerID uuid.UUID, status string, scope Scope) (*models.LeaveRequest, error) {
 	normalized := strings.ToLower(strings.TrimSpace(status))
 	if normalized != "approved" && normalized != "rejected" {
 		return nil, errors.New("status must be approved or rejected")
@@ -79,6 +129,9 @@ func (s *leaveService) ReviewLeave(leaveID, reviewerID uuid.UUID, status string)
 	if leave.UserID == reviewerID {
 		return nil, errors.New("you cannot review your own leave request")
 	}
+	if !scope.AllowsEmployee(&leave.Employee, reviewerID) && !isAssignedApprover(leave, reviewerID, scope) {
+		return nil, ErrOutOfScope
+	}
 
 	if leave.Status != "pending" {
 		return nil, errors.New("leave request has already been reviewed")
@@ -97,6 +150,15 @@ func (s *leaveService) ReviewLeave(leaveID, reviewerID uuid.UUID, status string)
 	return leave, nil
 }
 
+// isAssignedApprover reports whether leave was routed to the reviewer or to
+// someone who delegated to them. A delegator's own request never counts.
+func isAssignedApprover(leave *models.LeaveRequest, reviewerID uuid.UUID, scope Scope) bool {
+	if leave.ApproverID == nil || containsUUID(scope.OnBehalfOf, leave.UserID) {
+		return false
+	}
+	return *leave.ApproverID == reviewerID || containsUUID(scope.OnBehalfOf, *leave.ApproverID)
+}
+
 func (s *leaveService) CancelMyLeave(leaveID, userID uuid.UUID) (*models.LeaveRequest, error) {
 	leave, err := s.repo.FindByID(leaveID)
 	if err != nil {
@@ -122,6 +184,10 @@ func (s *leaveService) CancelMyLeave(leaveID, userID uuid.UUID) (*models.LeaveRe
 	return leave, nil
 }
 
-func (s *leaveService) PendingCount() (int64, error) {
-	return s.repo.CountPending()
+func (s *leaveService) PendingCount(scope Scope) (int64, error) {
+	employeeScope, empty := scope.filter()
+	if empty {
+		return 0, nil
+	}
+	return s.repo.CountPending(employeeScope)
 }
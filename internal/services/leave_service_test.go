@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestIsAssignedApproverHonoursDelegation(t *testing.T) {
+	reviewer, delegator, requester := uuid.New(), uuid.New(), uuid.New()
+	leave := func(userID uuid.UUID, approverID *uuid.UUID) *models.LeaveRequest {
+		return &models.LeaveRequest{UserID: userID, ApproverID: approverID}
+	}
+	delegated := Scope{OnBehalfOf: []uuid.UUID{delegator}}
+
+	cases := []struct {
+		name  string
+		leave *models.LeaveRequest
+		scope Scope
+		want  bool
+	}{
+		{"routed to the reviewer", leave(requester, &reviewer), Scope{}, true},
+		{"routed to someone else", leave(requester, &delegator), Scope{}, false},
+		{"not routed", leave(requester, nil), delegated, false},
+		{"routed to a delegator", leave(requester, &delegator), delegated, true},
+		{"the delegator's own request", leave(delegator, &reviewer), delegated, false},
+	}
+	for _, tc := range cases {
+		if got := isAssignedApprover(tc.leave, reviewer, tc.scope); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
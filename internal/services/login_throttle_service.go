@@ -0,0 +1,194 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+// LoginThrottlePolicy configures how failed logins slow down and lock out
+// further attempts.
+type LoginThrottlePolicy struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	FailureWindow      time.Duration
+	LockoutDuration    time.Duration
+	BackoffBase        time.Duration
+	BackoffMax         time.Duration
+}
+
+// LoginThrottlePolicyFromEnv reads the LOGIN_* variables, falling back to
+// conservative defaults.
+func LoginThrottlePolicyFromEnv() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		MaxAccountFailures: utils.GetEnvInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+		MaxIPFailures:      utils.GetEnvInt("LOGIN_MAX_IP_FAILURES", 20),
+		FailureWindow:      utils.GetEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LockoutDuration:    utils.GetEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		BackoffBase:        utils.GetEnvDuration("LOGIN_BACKOFF_BASE", time.Second),
+		BackoffMax:         utils.GetEnvDuration("LOGIN_BACKOFF_MAX", time.Minute),
+	}
+}
+
+// LoginThrottledError is returned while an account or IP is locked out or
+// still inside its backoff delay.
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account temporarily locked"
+	}
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+type LoginThrottleService interface {
+	Check(email, ip string) error
+	RecordFailure(userID uuid.UUID, email, ip, reason string)
+	RecordSuccess(email string)
+	Unlock(userID, adminID uuid.UUID, ip string) error
+}
+
+type loginThrottleService struct {
+	repo     repositories.LoginThrottleRepository
+	userRepo repositories.UserRepository
+	auditSvc AuditService
+	policy   LoginThrottlePolicy
+}
+
+func NewLoginThrottleService(
+	repo repositories.LoginThrottleRepository,
+	userRepo repositories.UserRepository,
+	auditSvc AuditService,
+	policy LoginThrottlePolicy,
+) LoginThrottleService {
+	return &loginThrottleService{repo: repo, userRepo: userRepo, auditSvc: auditSvc, policy: policy}
+}
+
+// Check returns a *LoginThrottledError if the attempt must be rejected
+// before the password is even verified.
+func (s *loginThrottleService) Check(email, ip string) error {
+	now := time.Now().UTC()
+
+	if throttle, err := s.repo.Find(models.LoginThrottleScopeIP, ip); err == nil {
+		if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+			return &LoginThrottledError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+		}
+	}
+
+	throttle, err := s.repo.Find(models.LoginThrottleScopeAccount, normalizeEmail(email))
+	if err != nil {
+		return nil
+	}
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return &LoginThrottledError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	if now.Sub(throttle.LastFailureAt) > s.policy.FailureWindow {
+		return nil
+	}
+
+	nextAttempt := throttle.LastFailureAt.Add(s.backoff(throttle.Failures))
+	if now.Before(nextAttempt) {
+		return &LoginThrottledError{RetryAfter: nextAttempt.Sub(now)}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt against both the account and the IP
+// and locks whichever crosses its threshold. userID is uuid.Nil for unknown
+// emails, which are audited without a user.
+func (s *loginThrottleService) RecordFailure(userID uuid.UUID, email, ip, reason string) {
+	email = normalizeEmail(email)
+
+	s.auditSvc.Log(userID, "LOGIN_FAILED", "user", nil, map[string]interface{}{
+		"email":  email,
+		"ip":     ip,
+		"reason": reason,
+	})
+
+	if throttle, err := s.repo.RecordFailure(models.LoginThrottleScopeAccount, email, s.policy.FailureWindow); err == nil {
+		if s.policy.MaxAccountFailures > 0 && throttle.Failures >= s.policy.MaxAccountFailures {
+			s.lock(throttle, userID, ip)
+		}
+	}
+
+	if ip == "" {
+		return
+	}
+	if throttle, err := s.repo.RecordFailure(models.LoginThrottleScopeIP, ip, s.policy.FailureWindow); err == nil {
+		if s.policy.MaxIPFailures > 0 && throttle.Failures >= s.policy.MaxIPFailures {
+			s.lock(throttle, userID, ip)
+		}
+	}
+}
+
+// RecordSuccess clears the account counter. The IP counter is left alone so
+// one valid login cannot reset a password-spraying source.
+func (s *loginThrottleService) RecordSuccess(email string) {
+	_ = s.repo.Delete(models.LoginThrottleScopeAccount, normalizeEmail(email))
+}
+
+func (s *loginThrottleService) Unlock(userID, adminID uuid.UUID, ip string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(models.LoginThrottleScopeAccount, normalizeEmail(user.Email)); err != nil {
+		return err
+	}
+	if ip != "" {
+		if err := s.repo.Delete(models.LoginThrottleScopeIP, ip); err != nil {
+			return err
+		}
+	}
+
+	s.auditSvc.Log(adminID, "ACCOUNT_UNLOCKED", "user", &user.ID, map[string]interface{}{
+		"ip": ip,
+	})
+	return nil
+}
+
+func (s *loginThrottleService) lock(throttle *models.LoginThrottle, userID uuid.UUID, ip string) {
+	until := time.Now().UTC().Add(s.policy.LockoutDuration)
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(until) {
+		return
+	}
+	if err := s.repo.Lock(throttle.ID, until); err != nil {
+		return
+	}
+
+	s.auditSvc.Log(userID, "ACCOUNT_LOCKED", "user", nil, map[string]interface{}{
+		"scope":        throttle.Scope,
+		"identifier":   throttle.Identifier,
+		"ip":           ip,
+		"failures":     throttle.Failures,
+		"locked_until": until.Format(time.RFC3339),
+	})
+}
+
+// backoff doubles the delay with every consecutive failure, capped at
+// BackoffMax.
+func (s *loginThrottleService) backoff(failures int) time.Duration {
+	if failures <= 0 || s.policy.BackoffBase <= 0 {
+		return 0
+	}
+	delay := float64(s.policy.BackoffBase) * math.Pow(2, float64(failures-1))
+	if delay > float64(s.policy.BackoffMax) {
+		return s.policy.BackoffMax
+	}
+	return time.Duration(delay)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+type memoryThrottleRepo struct {
+	repositories.LoginThrottleRepository
+	throttles map[string]*models.LoginThrottle
+}
+
+func newMemoryThrottleRepo() *memoryThrottleRepo {
+	return &memoryThrottleRepo{throttles: map[string]*models.LoginThrottle{}}
+}
+
+func (r *memoryThrottleRepo) Find(scope, identifier string) (*models.LoginThrottle, error) {
+	if throttle, ok := r.throttles[scope+":"+identifier]; ok {
+		copied := *throttle
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryThrottleRepo) RecordFailure(scope, identifier string, window time.Duration) (*models.LoginThrottle, error) {
+	now := time.Now().UTC()
+	throttle, ok := r.throttles[scope+":"+identifier]
+	switch {
+	case !ok:
+		throttle = &models.LoginThrottle{Scope: scope, Identifier: identifier}
+		throttle.ID = uuid.New()
+		r.throttles[scope+":"+identifier] = throttle
+		throttle.Failures = 1
+	case throttle.LastFailureAt.Before(now.Add(-window)):
+		throttle.Failures = 1
+	default:
+		throttle.Failures++
+	}
+	throttle.LastFailureAt = now
+	return r.Find(scope, identifier)
+}
+
+func (r *memoryThrottleRepo) Lock(id uuid.UUID, until time.Time) error {
+	for _, throttle := range r.throttles {
+		if throttle.ID == id {
+			throttle.LockedUntil = &until
+		}
+	}
+	return nil
+}
+
+func (r *memoryThrottleRepo) Delete(scope, identifier string) error {
+	delete(r.throttles, scope+":"+identifier)
+	return nil
+}
+
+func newTestLoginThrottle(policy LoginThrottlePolicy, users ...*models.User) (*loginThrottleService, *memoryThrottleRepo, *memoryAudit) {
+	repo := newMemoryThrottleRepo()
+	audit := &memoryAudit{}
+	return &loginThrottleService{repo: repo, userRepo: newMemoryUserRepo(users...), auditSvc: audit, policy: policy}, repo, audit
+}
+
+func TestLoginThrottleLocksAccountAfterMaxFailures(t *testing.T) {
+	svc, _, audit := newTestLoginThrottle(LoginThrottlePolicy{
+		MaxAccountFailures: 3,
+		FailureWindow:      time.Hour,
+		LockoutDuration:    time.Hour,
+	})
+
+	for i := 0; i < 2; i++ {
+		svc.RecordFailure(uuid.Nil, "Alice@Example.com", "", "invalid_password")
+	}
+	if err := svc.Check("alice@example.com", ""); err != nil {
+		t.Fatalf("expected no lockout below the threshold, got %v", err)
+	}
+
+	svc.RecordFailure(uuid.Nil, "alice@example.com ", "", "invalid_password")
+	var throttled *LoginThrottledError
+	if err := svc.Check("ALICE@example.com", ""); !errors.As(err, &throttled) || !throttled.Locked {
+		t.Fatalf("expected the account to be locked, got %v", err)
+	}
+	if throttled.RetryAfter <= 59*time.Minute {
+		t.Fatalf("expected a retry after about the lockout duration, got %s", throttled.RetryAfter)
+	}
+	if !audit.logged("ACCOUNT_LOCKED") {
+		t.Fatal("expected the lockout to be audited")
+	}
+}
+
+func TestLoginThrottleLocksIPAcrossAccounts(t *testing.T) {
+	svc, _, _ := newTestLoginThrottle(LoginThrottlePolicy{
+		MaxAccountFailures: 10,
+		MaxIPFailures:      3,
+		FailureWindow:      time.Hour,
+		LockoutDuration:    time.Hour,
+	})
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		svc.RecordFailure(uuid.Nil, email, "203.0.113.7", "unknown_email")
+	}
+
+	var throttled *LoginThrottledError
+	if err := svc.Check("d@example.com", "203.0.113.7"); !errors.As(err, &throttled) || !throttled.Locked {
+		t.Fatalf("expected the IP to be locked, got %v", err)
+	}
+	if err := svc.Check("d@example.com", "198.51.100.1"); err != nil {
+		t.Fatalf("expected other IPs to be unaffected, got %v", err)
+	}
+}
+
+func TestLoginThrottleBacksOffExponentially(t *testing.T) {
+	svc, repo, _ := newTestLoginThrottle(LoginThrottlePolicy{
+		FailureWindow: time.Hour,
+		BackoffBase:   time.Minute,
+		BackoffMax:    5 * time.Minute,
+	})
+
+	for failures, want := range map[int]time.Duration{0: 0, 1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 4: 5 * time.Minute} {
+		if got := svc.backoff(failures); got != want {
+			t.Fatalf("backoff(%d): expected %s, got %s", failures, want, got)
+		}
+	}
+
+	svc.RecordFailure(uuid.Nil, "alice@example.com", "", "invalid_password")
+	svc.RecordFailure(uuid.Nil, "alice@example.com", "", "invalid_password")
+	var throttled *LoginThrottledError
+	if err := svc.Check("alice@example.com", ""); !errors.As(err, &throttled) || throttled.Locked {
+		t.Fatalf("expected a backoff delay, got %v", err)
+	}
+	if throttled.RetryAfter <= time.Minute || throttled.RetryAfter > 2*time.Minute {
+		t.Fatalf("expected a delay of about two minutes, got %s", throttled.RetryAfter)
+	}
+
+	// Failures older than the window no longer slow the account down.
+	repo.throttles[models.LoginThrottleScopeAccount+":alice@example.com"].LastFailureAt = time.Now().UTC().Add(-2 * time.Hour)
+	if err := svc.Check("alice@example.com", ""); err != nil {
+		t.Fatalf("expected stale failures to be ignored, got %v", err)
+	}
+}
+
+func TestLoginThrottleSuccessKeepsIPCounter(t *testing.T) {
+	svc, repo, _ := newTestLoginThrottle(LoginThrottlePolicy{FailureWindow: time.Hour})
+
+	svc.RecordFailure(uuid.Nil, "alice@example.com", "203.0.113.7", "invalid_password")
+	svc.RecordSuccess("alice@example.com")
+
+	if _, err := repo.Find(models.LoginThrottleScopeAccount, "alice@example.com"); err == nil {
+		t.Fatal("expected a successful login to clear the account counter")
+	}
+	if _, err := repo.Find(models.LoginThrottleScopeIP, "203.0.113.7"); err != nil {
+		t.Fatal("expected a successful login to leave the IP counter alone")
+	}
+}
+
+func TestLoginThrottleAdminUnlock(t *testing.T) {
+	user := &models.User{Email: "alice@example.com"}
+	svc, _, audit := newTestLoginThrottle(LoginThrottlePolicy{
+		MaxAccountFailures: 1,
+		MaxIPFailures:      1,
+		FailureWindow:      time.Hour,
+		LockoutDuration:    time.Hour,
+	}, user)
+
+	svc.RecordFailure(user.ID, user.Email, "203.0.113.7", "invalid_password")
+	if err := svc.Check(user.Email, "203.0.113.7"); err == nil {
+		t.Fatal("expected the account to be locked")
+	}
+
+	if err := svc.Unlock(user.ID, uuid.New(), "203.0.113.7"); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if err := svc.Check(user.Email, "203.0.113.7"); err != nil {
+		t.Fatalf("expected the unlock to clear both locks, got %v", err)
+	}
+	if !audit.logged("ACCOUNT_UNLOCKED") {
+		t.Fatal("expected the unlock to be audited")
+	}
+
+	if err := svc.Unlock(uuid.New(), uuid.New(), ""); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected unknown users to be reported, got %v", err)
+	}
+}
+
+// fkAuditRepo rejects entries for users that do not exist, like the
+// audit_logs.user_id foreign key.
+type fkAuditRepo struct {
+	repositories.AuditRepository
+	users   map[uuid.UUID]bool
+	entries []*models.AuditLog
+}
+
+func (r *fkAuditRepo) Create(entry *models.AuditLog) error {
+	if entry.UserID != nil && !r.users[*entry.UserID] {
+		return errors.New("violates foreign key constraint on audit_logs.user_id")
+	}
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestFailuresForUnknownEmailsAreAuditedWithoutAUser(t *testing.T) {
+	auditRepo := &fkAuditRepo{}
+	svc, _, _ := newTestLoginThrottle(LoginThrottlePolicy{
+		MaxAccountFailures: 2,
+		FailureWindow:      time.Hour,
+		LockoutDuration:    time.Hour,
+	})
+	svc.auditSvc = NewAuditService(auditRepo)
+
+	for i := 0; i < 2; i++ {
+		svc.RecordFailure(uuid.Nil, "nobody@example.com", "203.0.113.7", "unknown_email")
+	}
+
+	var actions []string
+	for _, entry := range auditRepo.entries {
+		if entry.UserID != nil {
+			t.Fatalf("%s: expected no user, got %s", entry.Action, entry.UserID)
+		}
+		actions = append(actions, entry.Action)
+	}
+	if !slices.Contains(actions, "LOGIN_FAILED") || !slices.Contains(actions, "ACCOUNT_LOCKED") {
+		t.Fatalf("expected the failures and the lockout to be written, got %v", actions)
+	}
+}
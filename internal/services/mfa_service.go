@@ -0,0 +1,302 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+const (
+	mfaIssuer          = "StaffPoint"
+	mfaSkewSteps       = 1
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 5 // random bytes, 8 base32 characters
+)
+
+var (
+	errInvalidMFACode  = errors.New("invalid mfa code")
+	errMFANotEnrolled  = errors.New("mfa is not enrolled")
+	errMFAAlreadyOn    = errors.New("mfa is already enabled")
+	errMFAMandatory    = errors.New("mfa is mandatory for your permissions")
+	recoveryCodeFormat = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// MFAEnrollment is returned when a user starts TOTP enrollment. The URI is
+// meant to be rendered as a QR code by the client.
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFAService interface {
+	IsEnabled(userID uuid.UUID) (bool, error)
+	// IsRequired reports whether permissions, the caller's effective ones
+	// including temporary grants, make MFA mandatory.
+	IsRequired(permissions []string) bool
+	// IsRequiredFor resolves the user's effective permissions first.
+	IsRequiredFor(userID uuid.UUID, role string) bool
+	BeginEnrollment(userID uuid.UUID) (*MFAEnrollment, error)
+	ConfirmEnrollment(userID uuid.UUID, code string) ([]string, error)
+	Verify(userID uuid.UUID, code string) error
+	UseRecoveryCode(userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error)
+	Disable(userID uuid.UUID, permissions []string, code string) error
+	Reset(userID, adminID uuid.UUID) error
+}
+
+type mfaService struct {
+	repo            repositories.MFARepository
+	userRepo        repositories.UserRepository
+	grants          PermissionGrantService
+	auditSvc        AuditService
+	encryptionKey   string
+	enforceForRoles bool
+}
+
+// NewMFAService encrypts TOTP secrets with encryptionKey. When
+// MFA_ENFORCE_SENSITIVE_ROLES is true, users holding payslip or audit
+// permissions, whether from their role or a grant, must complete MFA on
+// every login.
+func NewMFAService(
+	repo repositories.MFARepository,
+	userRepo repositories.UserRepository,
+	grants PermissionGrantService,
+	auditSvc AuditService,
+	encryptionKey string,
+) MFAService {
+	return &mfaService{
+		repo:            repo,
+		userRepo:        userRepo,
+		grants:          grants,
+		auditSvc:        auditSvc,
+		encryptionKey:   encryptionKey,
+		enforceForRoles: strings.EqualFold(utils.GetEnv("MFA_ENFORCE_SENSITIVE_ROLES", "false"), "true"),
+	}
+}
+
+func (s *mfaService) IsEnabled(userID uuid.UUID) (bool, error) {
+	factor, err := s.repo.FindFactor(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return factor.ConfirmedAt != nil, nil
+}
+
+func (s *mfaService) IsRequired(permissions []string) bool {
+	return s.enforceForRoles && authz.IsMFASensitive(permissions)
+}
+
+// IsRequiredFor merges the grants in force into the role's permissions, as
+// the auth middleware does. It fails closed: if the grants cannot be loaded,
+// MFA is required whenever it is enforced at all.
+func (s *mfaService) IsRequiredFor(userID uuid.UUID, role string) bool {
+	if !s.enforceForRoles {
+		return false
+	}
+	permissions := authz.PermissionsForRole(role)
+	if s.grants != nil {
+		effective, err := s.grants.Effective(userID)
+		if err != nil {
+			return true
+		}
+		permissions = append(permissions, effective.Permissions...)
+	}
+	return authz.IsMFASensitive(permissions)
+}
+
+// BeginEnrollment creates (or replaces) a pending factor. It is not used for
+// logins until ConfirmEnrollment succeeds.
+func (s *mfaService) BeginEnrollment(userID uuid.UUID) (*MFAEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	factor, err := s.repo.FindFactor(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if factor != nil && factor.ConfirmedAt != nil {
+		return nil, errMFAAlreadyOn
+	}
+	if factor == nil {
+		factor = &models.MFAFactor{UserID: userID}
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptString(secret, s.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	factor.SecretEncrypted = encrypted
+	factor.LastUsedStep = 0
+	if err := s.repo.SaveFactor(factor); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment activates a pending factor and returns a fresh set of
+// recovery codes. The codes are only ever shown here.
+func (s *mfaService) ConfirmEnrollment(userID uuid.UUID, code string) ([]string, error) {
+	factor, err := s.repo.FindFactor(userID)
+	if err != nil {
+		return nil, errMFANotEnrolled
+	}
+	if factor.ConfirmedAt != nil {
+		return nil, errMFAAlreadyOn
+	}
+	if err := s.checkCode(factor, code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	factor.ConfirmedAt = &now
+	if err := s.repo.SaveFactor(factor); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(userID, "MFA_ENABLED", "user", &userID, nil)
+	return codes, nil
+}
+
+func (s *mfaService) Verify(userID uuid.UUID, code string) error {
+	factor, err := s.repo.FindFactor(userID)
+	if err != nil || factor.ConfirmedAt == nil {
+		return errMFANotEnrolled
+	}
+	return s.checkCode(factor, code)
+}
+
+func (s *mfaService) UseRecoveryCode(userID uuid.UUID, code string) error {
+	used, err := s.repo.UseRecoveryCode(userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errInvalidMFACode
+	}
+
+	s.auditSvc.Log(userID, "MFA_RECOVERY_CODE_USED", "user", &userID, nil)
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(userID, "MFA_RECOVERY_CODES_REGENERATED", "user", &userID, nil)
+	return codes, nil
+}
+
+// Disable turns MFA off for the caller after verifying a current code.
+// Users whose permissions require MFA cannot opt out.
+func (s *mfaService) Disable(userID uuid.UUID, permissions []string, code string) error {
+	if s.IsRequired(permissions) {
+		return errMFAMandatory
+	}
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteForUser(userID); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(userID, "MFA_DISABLED", "user", &userID, nil)
+	return nil
+}
+
+// Reset removes a user's factor so they can enroll again, e.g. after losing
+// their device and recovery codes.
+func (s *mfaService) Reset(userID, adminID uuid.UUID) error {
+	if err := s.repo.DeleteForUser(userID); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(adminID, "MFA_RESET", "user", &userID, nil)
+	return nil
+}
+
+func (s *mfaService) checkCode(factor *models.MFAFactor, code string) error {
+	secret, err := utils.DecryptString(factor.SecretEncrypted, s.encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), mfaSkewSteps)
+	if !ok {
+		return errInvalidMFACode
+	}
+
+	// Each code may be used once
+	advanced, err := s.repo.AdvanceStep(factor.ID, step)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return errInvalidMFACode
+	}
+	factor.LastUsedStep = step
+	return nil
+}
+
+func (s *mfaService) issueRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		encoded := strings.ToLower(recoveryCodeFormat.EncodeToString(raw))
+		codes = append(codes, encoded[:4]+"-"+encoded[4:])
+		records = append(records, models.MFARecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(encoded),
+		})
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+)
+
+type memoryGrants struct {
+	PermissionGrantService
+	effective map[uuid.UUID]EffectiveGrants
+	err       error
+}
+
+func (g *memoryGrants) Effective(userID uuid.UUID) (EffectiveGrants, error) {
+	return g.effective[userID], g.err
+}
+
+func TestMFARequiredByGrantedPermissions(t *testing.T) {
+	granted, plain := uuid.New(), uuid.New()
+	grants := &memoryGrants{effective: map[uuid.UUID]EffectiveGrants{
+		granted: {Permissions: []string{authz.PermManagePayslips}},
+	}}
+	svc := &mfaService{grants: grants, enforceForRoles: true}
+
+	if !svc.IsRequiredFor(uuid.New(), authz.RoleManager) {
+		t.Fatal("expected a role holding manage_payslips to require MFA")
+	}
+	if svc.IsRequiredFor(plain, authz.RoleEmployee) {
+		t.Fatal("expected a plain employee not to require MFA")
+	}
+	if !svc.IsRequiredFor(granted, authz.RoleEmployee) {
+		t.Fatal("expected a temporary manage_payslips grant to require MFA")
+	}
+
+	grants.err = errors.New("database unavailable")
+	if !svc.IsRequiredFor(plain, authz.RoleEmployee) {
+		t.Fatal("expected a failed grant lookup to require MFA")
+	}
+
+	svc.enforceForRoles = false
+	if svc.IsRequiredFor(granted, authz.RoleEmployee) {
+		t.Fatal("expected nothing to be required while enforcement is off")
+	}
+}
+
+func TestMFADisableRefusedForSensitivePermissions(t *testing.T) {
+	svc := &mfaService{enforceForRoles: true}
+
+	permissions := append(authz.PermissionsForRole(authz.RoleEmployee), authz.PermViewAuditLogs)
+	if err := svc.Disable(uuid.New(), permissions, "123456"); !errors.Is(err, errMFAMandatory) {
+		t.Fatalf("expected errMFAMandatory, got %v", err)
+	}
+}
@@ -24,7 +24,7 @@ type NotificationPayload struct {
 }
 
 type NotificationService interface {
-	GetNotifications(userID uuid.UUID, role string) (NotificationPayload, error)
+	GetNotifications(userID uuid.UUID, permissions []string, scope Scope) (NotificationPayload, error)
 }
 
 type notificationService struct {
@@ -35,21 +35,28 @@ func NewNotificationService(leaveRepo repositories.LeaveRepository) Notification
 	return &notificationService{leaveRepo: leaveRepo}
 }
 
-func (s *notificationService) GetNotifications(userID uuid.UUID, role string) (NotificationPayload, error) {
+// GetNotifications shows reviewers the pending leave requests within scope
+// and everyone else the status of their own requests.
+func (s *notificationService) GetNotifications(userID uuid.UUID, permissions []string, scope Scope) (NotificationPayload, error) {
 	payload := NotificationPayload{
 		UnreadCount: 0,
 		Items:       make([]NotificationItem, 0),
 	}
 
-	isReviewer := authz.HasPermission(authz.PermissionsForRole(role), authz.PermReviewLeaves)
+	isReviewer := authz.HasPermission(permissions, authz.PermReviewLeaves)
 	if isReviewer {
-		pendingCount, err := s.leaveRepo.CountPending()
+		employeeScope, empty := scope.filter()
+		if empty {
+			return payload, nil
+		}
+
+		pendingCount, err := s.leaveRepo.CountPending(employeeScope)
 		if err != nil {
 			return payload, err
 		}
 		payload.UnreadCount = pendingCount
 
-		leaves, err := s.leaveRepo.ListAll("pending", 5)
+		leaves, err := s.leaveRepo.ListAll("pending", 5, employeeScope)
 		if err != nil {
 			return payload, err
 		}
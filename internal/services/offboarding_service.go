@@ -0,0 +1,292 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+var (
+	errInvalidReasonCode    = errors.New("reason_code must be one of resignation, dismissal, redundancy, retirement, end_of_contract or other")
+	errLastWorkingDay       = errors.New("last_working_day is required")
+	errTerminationPending   = errors.New("employee already has a pending termination")
+	errNoPendingTermination = errors.New("employee has no pending termination")
+	errOffboardingCancelled = errors.New("the termination of this offboarding was cancelled")
+	errTaskCompleted        = errors.New("task is already completed")
+	errTaskTitleRequired    = errors.New("title is required")
+)
+
+// terminationReasons are the reason codes a termination may give.
+var terminationReasons = []string{"resignation", "dismissal", "redundancy", "retirement", "end_of_contract", "other"}
+
+// Termination describes the end of an employee's employment. Access is cut
+// at the end of LastWorkingDay, at 00:00 UTC the next day.
+type Termination struct {
+	LastWorkingDay time.Time
+	ReasonCode     string
+	Notes          string
+}
+
+// OffboardingService terminates employees and tracks the checklist each
+// department works through when someone leaves.
+type OffboardingService interface {
+	Terminate(employeeID uuid.UUID, termination Termination, actorID uuid.UUID, scope Scope) (*models.Offboarding, error)
+	CancelTermination(employeeID, actorID uuid.UUID, scope Scope) error
+	Get(employeeID uuid.UUID, scope Scope) (*models.Offboarding, error)
+	ListTasks(scope Scope, includeCompleted bool) ([]models.OffboardingTask, error)
+	CompleteTask(taskID, actorID uuid.UUID, scope Scope) (*models.OffboardingTask, error)
+	ListTemplates() ([]models.OffboardingTaskTemplate, error)
+	CreateTemplate(departmentID uuid.UUID, title string, position int, actorID uuid.UUID) (*models.OffboardingTaskTemplate, error)
+	DeleteTemplate(id, actorID uuid.UUID) error
+}
+
+type offboardingService struct {
+	repo           repositories.OffboardingRepository
+	employeeRepo   repositories.EmployeeRepository
+	departmentRepo repositories.DepartmentRepository
+	history        EmploymentHistoryService
+	auditSvc       AuditService
+}
+
+func NewOffboardingService(
+	repo repositories.OffboardingRepository,
+	employeeRepo repositories.EmployeeRepository,
+	departmentRepo repositories.DepartmentRepository,
+	history EmploymentHistoryService,
+	auditSvc AuditService,
+) OffboardingService {
+	return &offboardingService{
+		repo:           repo,
+		employeeRepo:   employeeRepo,
+		departmentRepo: departmentRepo,
+		history:        history,
+		auditSvc:       auditSvc,
+	}
+}
+
+// Terminate records a termination effective the day after the last working
+// day and opens the checklist from the task templates. A last working day
+// in the past takes effect at once. Pending events effective on or after
+// the termination are cancelled along with it.
+func (s *offboardingService) Terminate(
+	employeeID uuid.UUID,
+	termination Termination,
+	actorID uuid.UUID,
+	scope Scope,
+) (*models.Offboarding, error) {
+	if termination.LastWorkingDay.IsZero() {
+		return nil, errLastWorkingDay
+	}
+	if !slices.Contains(terminationReasons, termination.ReasonCode) {
+		return nil, errInvalidReasonCode
+	}
+
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.AllowsEmployee(employee, actorID) {
+		return nil, ErrOutOfScope
+	}
+	if err := checkTargetRole(employee, scope); err != nil {
+		return nil, err
+	}
+
+	// A pending termination is reported as such, whichever day the new one
+	// would take effect
+	current, err := s.repo.FindCurrent(employeeID)
+	if err == nil && current.Event.Pending() {
+		return nil, errTerminationPending
+	}
+
+	lastDay := employmentDay(termination.LastWorkingDay)
+	event, err := s.history.Prepare(employeeID, EmploymentChange{
+		Type:          models.EmploymentEventTermination,
+		EffectiveDate: lastDay.AddDate(0, 0, 1),
+		Reason:        termination.ReasonCode,
+	}, actorID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.repo.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]models.OffboardingTask, 0, len(templates))
+	for _, template := range templates {
+		tasks = append(tasks, models.OffboardingTask{
+			DepartmentID: template.DepartmentID,
+			Title:        template.Title,
+			Position:     template.Position,
+		})
+	}
+
+	offboarding := &models.Offboarding{
+		EmployeeID:     employeeID,
+		LastWorkingDay: lastDay,
+		ReasonCode:     termination.ReasonCode,
+		Notes:          strings.TrimSpace(termination.Notes),
+		InitiatedBy:    &actorID,
+		Event:          *event,
+		Tasks:          tasks,
+	}
+	superseded, err := s.repo.Create(offboarding, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(actorID, "EMPLOYEE_TERMINATED", "employee", &employeeID, map[string]interface{}{
+		"offboarding_id":   offboarding.ID.String(),
+		"last_working_day": lastDay.Format("2006-01-02"),
+		"reason_code":      termination.ReasonCode,
+		"tasks":            len(tasks),
+	})
+	for _, cancelled := range superseded {
+		s.auditSvc.Log(actorID, "EMPLOYMENT_EVENT_CANCELLED", "employee", &employeeID, map[string]interface{}{
+			"event_id":       cancelled.ID.String(),
+			"type":           cancelled.Type,
+			"offboarding_id": offboarding.ID.String(),
+		})
+	}
+
+	if err := s.history.Settle(&offboarding.Event); err != nil {
+		return offboarding, err
+	}
+	return offboarding, nil
+}
+
+// CancelTermination withdraws a termination that has not taken effect; its
+// checklist goes with it.
+func (s *offboardingService) CancelTermination(employeeID, actorID uuid.UUID, scope Scope) error {
+	if err := s.checkScope(employeeID, actorID, scope); err != nil {
+		return err
+	}
+
+	offboarding, err := s.repo.FindCurrent(employeeID)
+	if err != nil || !offboarding.Event.Pending() {
+		return errNoPendingTermination
+	}
+	return s.history.Cancel(employeeID, offboarding.EventID, actorID, scope)
+}
+
+// Get returns the employee's latest offboarding that was not cancelled.
+func (s *offboardingService) Get(employeeID uuid.UUID, scope Scope) (*models.Offboarding, error) {
+	if err := s.checkScope(employeeID, uuid.Nil, scope); err != nil {
+		return nil, err
+	}
+	return s.repo.FindCurrent(employeeID)
+}
+
+// ListTasks returns the checklist items of the departments within scope.
+func (s *offboardingService) ListTasks(scope Scope, includeCompleted bool) ([]models.OffboardingTask, error) {
+	var departmentIDs []uuid.UUID
+	if !scope.Global {
+		departmentIDs = append([]uuid.UUID{}, scope.DepartmentIDs...)
+	}
+	return s.repo.ListTasks(departmentIDs, includeCompleted)
+}
+
+func (s *offboardingService) CompleteTask(taskID, actorID uuid.UUID, scope Scope) (*models.OffboardingTask, error) {
+	task, err := s.repo.FindTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.Allows(&task.DepartmentID) {
+		return nil, ErrOutOfScope
+	}
+	if task.Offboarding != nil && task.Offboarding.Event.CancelledAt != nil {
+		return nil, errOffboardingCancelled
+	}
+
+	now := time.Now().UTC()
+	completed, err := s.repo.CompleteTask(taskID, actorID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, errTaskCompleted
+	}
+	task.CompletedAt = &now
+	task.CompletedBy = &actorID
+
+	var employeeID *uuid.UUID
+	if task.Offboarding != nil {
+		employeeID = &task.Offboarding.EmployeeID
+	}
+	s.auditSvc.Log(actorID, "OFFBOARDING_TASK_COMPLETED", "employee", employeeID, map[string]interface{}{
+		"task_id":        taskID.String(),
+		"offboarding_id": task.OffboardingID.String(),
+		"department_id":  task.DepartmentID.String(),
+		"title":          task.Title,
+	})
+	return task, nil
+}
+
+func (s *offboardingService) ListTemplates() ([]models.OffboardingTaskTemplate, error) {
+	return s.repo.ListTemplates()
+}
+
+// CreateTemplate adds a task to the checklist of future offboardings.
+func (s *offboardingService) CreateTemplate(
+	departmentID uuid.UUID,
+	title string,
+	position int,
+	actorID uuid.UUID,
+) (*models.OffboardingTaskTemplate, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, errTaskTitleRequired
+	}
+	department, err := s.departmentRepo.FindByID(departmentID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.OffboardingTaskTemplate{
+		DepartmentID: departmentID,
+		Title:        title,
+		Position:     position,
+	}
+	if err := s.repo.CreateTemplate(template); err != nil {
+		return nil, err
+	}
+	template.Department = *department
+
+	s.auditSvc.Log(actorID, "OFFBOARDING_TEMPLATE_CREATED", "department", &departmentID, map[string]interface{}{
+		"template_id": template.ID.String(),
+		"title":       title,
+	})
+	return template, nil
+}
+
+func (s *offboardingService) DeleteTemplate(id, actorID uuid.UUID) error {
+	deleted, err := s.repo.DeleteTemplate(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.auditSvc.Log(actorID, "OFFBOARDING_TEMPLATE_DELETED", "offboarding_task_template", &id, nil)
+	return nil
+}
+
+func (s *offboardingService) checkScope(employeeID, actorID uuid.UUID, scope Scope) error {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return err
+	}
+	if !scope.AllowsEmployee(employee, actorID) {
+		return ErrOutOfScope
+	}
+	return nil
+}
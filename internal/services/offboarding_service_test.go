@@ -0,0 +1,182 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+)
+
+// newTestOffboarding returns an offboarding service over the history of a
+// single employee hired in Sales in 2024.
+func newTestOffboarding() (*offboardingService, *models.Employee, *memoryEventRepo, *memoryAudit) {
+	departments := newMemoryDepartmentRepo("Sales")
+	history, employees, events := newTestHistory(departments)
+	employee := hireEmployee(employees, departments.departments[0].ID, authz.RoleEmployee, day("2024-01-15"))
+	audit := &memoryAudit{}
+	history.auditSvc = audit
+	return &offboardingService{
+		repo:           &memoryOffboardingRepo{events: events},
+		employeeRepo:   employees,
+		departmentRepo: departments,
+		history:        history,
+		auditSvc:       audit,
+	}, employee, events, audit
+}
+
+func TestTerminateCancelsPendingEventsFromTheTerminationOn(t *testing.T) {
+	svc, employee, events, audit := newTestOffboarding()
+	today := employmentDay(time.Now())
+	actorID := uuid.New()
+
+	promotion := &models.EmploymentEvent{EmployeeID: employee.ID, Type: models.EmploymentEventPromotion, EffectiveDate: today.AddDate(0, 0, 5), JobTitle: text("Lead")}
+	transfer := &models.EmploymentEvent{EmployeeID: employee.ID, Type: models.EmploymentEventTransfer, EffectiveDate: today.AddDate(0, 0, 11), DepartmentID: employee.DepartmentID}
+	_ = events.Create(promotion)
+	_ = events.Create(transfer)
+
+	offboarding, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: today.AddDate(0, 0, 10), ReasonCode: "resignation"}, actorID, adminScope())
+	if err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+
+	if transfer.CancelledAt == nil || *transfer.CancelledBy != actorID {
+		t.Fatal("a transfer effective on the termination date should be cancelled")
+	}
+	if promotion.CancelledAt != nil {
+		t.Fatal("a promotion before the termination should stay pending")
+	}
+	if !audit.logged("EMPLOYMENT_EVENT_CANCELLED") {
+		t.Fatal("expected the cancellation to be audited")
+	}
+	if offboarding.Event.AppliedAt != nil {
+		t.Fatal("a future termination should wait for its effective date")
+	}
+}
+
+func TestTerminateTakesEffectTheDayAfterTheLastWorkingDay(t *testing.T) {
+	svc, employee, _, audit := newTestOffboarding()
+	it, finance := uuid.New(), uuid.New()
+	svc.repo.(*memoryOffboardingRepo).templates = []models.OffboardingTaskTemplate{
+		{DepartmentID: it, Title: "Recover laptop", Position: 1},
+		{DepartmentID: finance, Title: "Close payroll", Position: 2},
+	}
+	today := employmentDay(time.Now())
+
+	offboarding, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: today.Add(15 * time.Hour), ReasonCode: "retirement"}, uuid.New(), adminScope())
+	if err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	if !offboarding.LastWorkingDay.Equal(today) || !offboarding.Event.EffectiveDate.Equal(today.AddDate(0, 0, 1)) {
+		t.Fatalf("expected last working day %s to end the day after, got %s and %s", today, offboarding.LastWorkingDay, offboarding.Event.EffectiveDate)
+	}
+	if offboarding.Event.AppliedAt != nil {
+		t.Fatal("access should last until the end of the last working day")
+	}
+	if len(offboarding.Tasks) != 2 || offboarding.Tasks[0].DepartmentID != it || offboarding.Tasks[1].Title != "Close payroll" {
+		t.Fatalf("expected a task per template, got %+v", offboarding.Tasks)
+	}
+	if !audit.logged("EMPLOYEE_TERMINATED") {
+		t.Fatal("expected the termination to be audited")
+	}
+
+	for _, lastDay := range []time.Time{today.AddDate(0, 0, -1), today.AddDate(0, 0, 3)} {
+		_, err = svc.Terminate(employee.ID, Termination{LastWorkingDay: lastDay, ReasonCode: "resignation"}, uuid.New(), adminScope())
+		if err != errTerminationPending {
+			t.Fatalf("last working day %s: expected a second termination to be refused, got %v", lastDay.Format("2006-01-02"), err)
+		}
+	}
+}
+
+func TestTerminateInThePastCutsAccessAtOnce(t *testing.T) {
+	svc, employee, _, _ := newTestOffboarding()
+
+	offboarding, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: day("2024-06-30"), ReasonCode: "dismissal"}, uuid.New(), adminScope())
+	if err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	if offboarding.Event.AppliedAt == nil {
+		t.Fatal("a termination dated in the past should apply at once")
+	}
+	stored, _ := svc.employeeRepo.FindByID(employee.ID)
+	if stored.User.IsActive || stored.Status != "terminated" {
+		t.Fatalf("expected a deactivated, terminated employee, got active=%v status=%s", stored.User.IsActive, stored.Status)
+	}
+}
+
+func TestCancelTerminationOnlyBeforeItTakesEffect(t *testing.T) {
+	svc, employee, _, audit := newTestOffboarding()
+	today := employmentDay(time.Now())
+	actorID := uuid.New()
+
+	if err := svc.CancelTermination(employee.ID, actorID, adminScope()); err != errNoPendingTermination {
+		t.Fatalf("expected errNoPendingTermination without a termination, got %v", err)
+	}
+
+	if _, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: today.AddDate(0, 0, 7), ReasonCode: "resignation"}, actorID, adminScope()); err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	otherDepartment := Scope{DepartmentIDs: []uuid.UUID{uuid.New()}}
+	if err := svc.CancelTermination(employee.ID, actorID, otherDepartment); err != ErrOutOfScope {
+		t.Fatalf("expected ErrOutOfScope, got %v", err)
+	}
+	if err := svc.CancelTermination(employee.ID, actorID, adminScope()); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if !audit.logged("EMPLOYMENT_EVENT_CANCELLED") {
+		t.Fatal("expected the cancellation to be audited")
+	}
+	if _, err := svc.Get(employee.ID, adminScope()); err == nil {
+		t.Fatal("a cancelled termination should no longer be current")
+	}
+
+	if _, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: day("2024-06-30"), ReasonCode: "resignation"}, actorID, adminScope()); err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	if err := svc.CancelTermination(employee.ID, actorID, adminScope()); err != errNoPendingTermination {
+		t.Fatalf("expected an applied termination to stay, got %v", err)
+	}
+}
+
+func TestCompleteTaskChecksTheTaskDepartment(t *testing.T) {
+	svc, employee, _, _ := newTestOffboarding()
+	it := uuid.New()
+	svc.repo.(*memoryOffboardingRepo).templates = []models.OffboardingTaskTemplate{{DepartmentID: it, Title: "Recover laptop"}}
+
+	offboarding, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: employmentDay(time.Now()).AddDate(0, 0, 7), ReasonCode: "resignation"}, uuid.New(), adminScope())
+	if err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	taskID := offboarding.Tasks[0].ID
+
+	// The leaver's own department does not own the task.
+	if _, err := svc.CompleteTask(taskID, uuid.New(), Scope{DepartmentIDs: []uuid.UUID{*employee.DepartmentID}}); err != ErrOutOfScope {
+		t.Fatalf("expected ErrOutOfScope, got %v", err)
+	}
+
+	itStaff := Scope{DepartmentIDs: []uuid.UUID{it}}
+	task, err := svc.CompleteTask(taskID, uuid.New(), itStaff)
+	if err != nil || task.CompletedAt == nil {
+		t.Fatalf("expected the task to be completed, got %+v, %v", task, err)
+	}
+	if _, err := svc.CompleteTask(taskID, uuid.New(), itStaff); err != errTaskCompleted {
+		t.Fatalf("expected errTaskCompleted, got %v", err)
+	}
+}
+
+func TestManagerCannotTerminateAnAdmin(t *testing.T) {
+	svc, employee, events, _ := newTestOffboarding()
+	stored := svc.employeeRepo.(*memoryEmployeeRepo).employees[employee.ID]
+	stored.User.Role = authz.RoleAdmin
+
+	manager := Scope{DepartmentIDs: []uuid.UUID{*employee.DepartmentID}, Permissions: authz.PermissionsForRole(authz.RoleManager)}
+	_, err := svc.Terminate(employee.ID, Termination{LastWorkingDay: day("2024-06-30"), ReasonCode: "dismissal"}, uuid.New(), manager)
+	if err != ErrOutOfScope {
+		t.Fatalf("expected ErrOutOfScope, got %v", err)
+	}
+	if len(events.events) != 1 || !stored.User.IsActive {
+		t.Fatal("expected the admin to stay employed")
+	}
+}
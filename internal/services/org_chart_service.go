@@ -0,0 +1,233 @@
+package services
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+// OrgChartNode is one employee in the org chart and everyone reporting to
+// them. Status is personal data: it is null and named in RedactedFields
+// unless the caller may see it.
+type OrgChartNode struct {
+	EmployeeID     uuid.UUID       `json:"employee_id"`
+	FirstName      string          `json:"first_name"`
+	LastName       string          `json:"last_name"`
+	DepartmentID   *uuid.UUID      `json:"department_id"`
+	Department     string          `json:"department,omitempty"`
+	ManagerID      *uuid.UUID      `json:"manager_id"`
+	Status         *string         `json:"status"`
+	RedactedFields []string        `json:"redacted_fields,omitempty"`
+	Reports        []*OrgChartNode `json:"reports"`
+}
+
+// OrgReport is an employee in someone's reporting line. Depth is 1 for
+// direct reports, 2 for their reports and so on. Status is redacted as in
+// OrgChartNode.
+type OrgReport struct {
+	EmployeeID     uuid.UUID  `json:"employee_id"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	DepartmentID   *uuid.UUID `json:"department_id"`
+	Department     string     `json:"department,omitempty"`
+	ManagerID      *uuid.UUID `json:"manager_id"`
+	Status         *string    `json:"status"`
+	RedactedFields []string   `json:"redacted_fields,omitempty"`
+	Depth          int        `json:"depth"`
+}
+
+type OrgChartService struct {
+	employeeRepo repositories.EmployeeRepository
+}
+
+func NewOrgChartService(employeeRepo repositories.EmployeeRepository) *OrgChartService {
+	return &OrgChartService{employeeRepo}
+}
+
+// Reports returns the employees reporting to employeeID, only the direct
+// ones unless indirect is set, ordered by depth and name.
+func (s *OrgChartService) Reports(employeeID uuid.UUID, indirect bool, access FieldAccess) ([]OrgReport, error) {
+	manager, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []OrgReport{}
+	ids, err := s.employeeRepo.ReportIDs([]uuid.UUID{manager.UserID})
+	if err != nil || len(ids) == 0 {
+		return reports, err
+	}
+	employees, err := s.employeeRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	managerOf := make(map[uuid.UUID]*uuid.UUID, len(employees))
+	for _, employee := range employees {
+		managerOf[employee.ID] = employee.ManagerID
+	}
+
+	for _, employee := range employees {
+		depth := reportDepth(employee.ID, manager.ID, managerOf)
+		if depth == 1 || (indirect && depth > 1) {
+			reports = append(reports, newOrgReport(employee, depth, access))
+		}
+	}
+	// Employees come sorted by name; keep that order within each level
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Depth < reports[j].Depth
+	})
+	return reports, nil
+}
+
+// reportDepth counts the steps from employeeID up to managerID, or returns 0
+// when the chain does not lead there.
+func reportDepth(employeeID, managerID uuid.UUID, managerOf map[uuid.UUID]*uuid.UUID) int {
+	current := employeeID
+	for depth := 1; depth <= len(managerOf); depth++ {
+		parent := managerOf[current]
+		if parent == nil {
+			return 0
+		}
+		if *parent == managerID {
+			return depth
+		}
+		current = *parent
+	}
+	return 0
+}
+
+func newOrgReport(employee *models.Employee, depth int, access FieldAccess) OrgReport {
+	report := OrgReport{
+		EmployeeID:   employee.ID,
+		FirstName:    employee.FirstName,
+		LastName:     employee.LastName,
+		DepartmentID: employee.DepartmentID,
+		ManagerID:    employee.ManagerID,
+		Depth:        depth,
+	}
+	report.Status, report.RedactedFields = orgStatus(employee, access)
+	if employee.Department != nil {
+		report.Department = employee.Department.Name
+	}
+	return report
+}
+
+// orgStatus returns the employee's status, or nil and the redacted field
+// when access hides their personal data.
+func orgStatus(employee *models.Employee, access FieldAccess) (*string, []string) {
+	if !access.PersonalDataOf(employee.UserID) {
+		return nil, []string{"status"}
+	}
+	status := employee.Status
+	return &status, nil
+}
+
+// Tree returns the org chart of employees who have not been deactivated,
+// with everyone without a manager at the top, or only the subtree of rootID
+// when given.
+func (s *OrgChartService) Tree(rootID *uuid.UUID, access FieldAccess) ([]*OrgChartNode, error) {
+	employees, err := s.employeeRepo.ListForOrgChart()
+	if err != nil {
+		return nil, err
+	}
+
+	roots, nodes := buildOrgChart(employees, access)
+	if rootID == nil {
+		return roots, nil
+	}
+	node, ok := nodes[*rootID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return []*OrgChartNode{node}, nil
+}
+
+// buildOrgChart links employees to their managers. Employees whose manager
+// is missing from the list, such as a deactivated one, become roots, as do
+// employees on a loop, so the tree is always finite.
+func buildOrgChart(employees []*models.Employee, access FieldAccess) ([]*OrgChartNode, map[uuid.UUID]*OrgChartNode) {
+	nodes := make(map[uuid.UUID]*OrgChartNode, len(employees))
+	managerOf := make(map[uuid.UUID]*uuid.UUID, len(employees))
+	for _, employee := range employees {
+		node := &OrgChartNode{
+			EmployeeID:   employee.ID,
+			FirstName:    employee.FirstName,
+			LastName:     employee.LastName,
+			DepartmentID: employee.DepartmentID,
+			ManagerID:    employee.ManagerID,
+			Reports:      []*OrgChartNode{},
+		}
+		node.Status, node.RedactedFields = orgStatus(employee, access)
+		if employee.Department != nil {
+			node.Department = employee.Department.Name
+		}
+		nodes[employee.ID] = node
+		managerOf[employee.ID] = employee.ManagerID
+	}
+
+	roots := []*OrgChartNode{}
+	for _, employee := range employees {
+		node := nodes[employee.ID]
+		if employee.ManagerID != nil {
+			parent, ok := nodes[*employee.ManagerID]
+			if ok && reportDepth(*employee.ManagerID, employee.ID, managerOf) == 0 {
+				parent.Reports = append(parent.Reports, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nodes
+}
+
+// ExportPDF writes the org chart as an indented list. Statuses the caller
+// may not see are left out.
+func (s *OrgChartService) ExportPDF(w io.Writer, rootID *uuid.UUID, access FieldAccess) error {
+	roots, err := s.Tree(rootID, access)
+	if err != nil {
+		return err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(40, 10, "Org Chart")
+	pdf.Ln(12)
+
+	var write func(node *OrgChartNode, level int)
+	write = func(node *OrgChartNode, level int) {
+		line := strings.TrimSpace(node.FirstName + " " + node.LastName)
+		if node.Department != "" {
+			line += " | " + node.Department
+		}
+		if node.Status != nil && *node.Status != "active" {
+			line += " (" + *node.Status + ")"
+		}
+
+		pdf.SetFont("Arial", "", 10)
+		if level == 0 {
+			pdf.SetFont("Arial", "B", 10)
+		}
+		pdf.SetX(10 + float64(min(level, 20))*6)
+		pdf.Cell(0, 6, translate(line))
+		pdf.Ln(6)
+
+		for _, report := range node.Reports {
+			write(report, level+1)
+		}
+	}
+	for _, root := range roots {
+		write(root, 0)
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestBuildOrgChartNestsReportsAndBreaksLoops(t *testing.T) {
+	employee := func(managerID *uuid.UUID) *models.Employee {
+		e := &models.Employee{ManagerID: managerID}
+		e.ID = uuid.New()
+		return e
+	}
+	ceo := employee(nil)
+	vp := employee(&ceo.ID)
+	engineer := employee(&vp.ID)
+	deactivatedID := uuid.New()
+	orphan := employee(&deactivatedID)
+
+	loopA := employee(nil)
+	loopB := employee(&loopA.ID)
+	loopA.ManagerID = &loopB.ID
+
+	selfManaged := employee(nil)
+	selfManaged.ManagerID = &selfManaged.ID
+
+	roots, nodes := buildOrgChart([]*models.Employee{engineer, vp, ceo, orphan, loopA, loopB, selfManaged}, FieldAccess{PersonalData: true})
+
+	if len(roots) != 5 {
+		t.Fatalf("expected ceo, orphan, both loop members and the self-managed employee as roots, got %d", len(roots))
+	}
+	if got := nodes[ceo.ID].Reports; len(got) != 1 || got[0].EmployeeID != vp.ID {
+		t.Fatal("vp should report to the ceo")
+	}
+	if got := nodes[vp.ID].Reports; len(got) != 1 || got[0].EmployeeID != engineer.ID {
+		t.Fatal("engineer should report to the vp")
+	}
+	if got := nodes[orphan.ID]; got.ManagerID == nil || *got.ManagerID != deactivatedID {
+		t.Fatal("an orphan should keep its manager id while shown as a root")
+	}
+	for _, member := range []*models.Employee{loopA, loopB, selfManaged} {
+		if got := nodes[member.ID].Reports; len(got) != 0 {
+			t.Fatalf("loop members should not be nested under each other, got %d reports", len(got))
+		}
+	}
+}
+
+func TestReportDepthFollowsTheChainOnly(t *testing.T) {
+	ceo, vp, engineer, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	loopA, loopB := uuid.New(), uuid.New()
+	managerOf := map[uuid.UUID]*uuid.UUID{
+		ceo:      nil,
+		vp:       &ceo,
+		engineer: &vp,
+		outsider: nil,
+		loopA:    &loopB,
+		loopB:    &loopA,
+	}
+
+	cases := []struct {
+		name              string
+		employee, manager uuid.UUID
+		want              int
+	}{
+		{"direct report", vp, ceo, 1},
+		{"indirect report", engineer, ceo, 2},
+		{"manager of the manager", ceo, vp, 0},
+		{"different chain", outsider, ceo, 0},
+		{"loop that never reaches the manager", loopA, ceo, 0},
+		{"loop member", loopA, loopB, 1},
+	}
+	for _, tc := range cases {
+		if got := reportDepth(tc.employee, tc.manager, managerOf); got != tc.want {
+			t.Errorf("%s: got depth %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOrgChartRedactsStatusWithoutPersonalDataAccess(t *testing.T) {
+	viewer, colleague := &models.Employee{Status: "active"}, &models.Employee{Status: "on_leave"}
+	viewer.ID, viewer.UserID = uuid.New(), uuid.New()
+	colleague.ID, colleague.UserID = uuid.New(), uuid.New()
+	colleague.ManagerID = &viewer.ID
+
+	_, nodes := buildOrgChart([]*models.Employee{viewer, colleague}, FieldAccess{ViewerID: viewer.UserID})
+	if got := nodes[colleague.ID]; got.Status != nil || len(got.RedactedFields) != 1 || got.RedactedFields[0] != "status" {
+		t.Fatalf("expected the colleague's status to be redacted, got %v %v", got.Status, got.RedactedFields)
+	}
+	if got := nodes[viewer.ID]; got.Status == nil || *got.Status != "active" {
+		t.Fatal("viewers should see their own status")
+	}
+
+	report := newOrgReport(colleague, 1, FieldAccess{ViewerID: viewer.UserID})
+	if report.Status != nil || len(report.RedactedFields) != 1 {
+		t.Fatalf("expected the report's status to be redacted, got %+v", report)
+	}
+	report = newOrgReport(colleague, 1, FieldAccess{ViewerID: viewer.UserID, PersonalData: true})
+	if report.Status == nil || *report.Status != "on_leave" || report.RedactedFields != nil {
+		t.Fatalf("expected view_personal_data to reveal the status, got %+v", report)
+	}
+}
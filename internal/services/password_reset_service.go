@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/mailer"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var errInvalidResetToken = errors.New("invalid or expired reset token")
+
+type PasswordResetService interface {
+	RequestReset(email, ipAddress string) error
+	ResetPassword(token, newPassword string) error
+}
+
+// ResetRequestLimits caps how many reset links may be requested per email
+// and per client IP within Window.
+type ResetRequestLimits struct {
+	MaxPerEmail int
+	MaxPerIP    int
+	Window      time.Duration
+}
+
+// ResetRequestLimitsFromEnv reads the PASSWORD_RESET_MAX_* variables.
+func ResetRequestLimitsFromEnv() ResetRequestLimits {
+	return ResetRequestLimits{
+		MaxPerEmail: utils.GetEnvInt("PASSWORD_RESET_MAX_PER_EMAIL", 3),
+		MaxPerIP:    utils.GetEnvInt("PASSWORD_RESET_MAX_PER_IP", 20),
+		Window:      utils.GetEnvDuration("PASSWORD_RESET_WINDOW", time.Hour),
+	}
+}
+
+type passwordResetService struct {
+	resetRepo     repositories.PasswordResetRepository
+	throttleRepo  repositories.LoginThrottleRepository
+	userRepo      repositories.UserRepository
+	auditSvc      AuditService
+	accessRevoker AccessRevoker
+	passwordSvc   PasswordService
+	mailer        mailer.Mailer
+	baseURL       string
+	ttl           time.Duration
+	limits        ResetRequestLimits
+}
+
+func NewPasswordResetService(
+	resetRepo repositories.PasswordResetRepository,
+	throttleRepo repositories.LoginThrottleRepository,
+	userRepo repositories.UserRepository,
+	auditSvc AuditService,
+	accessRevoker AccessRevoker,
+	passwordSvc PasswordService,
+	mail mailer.Mailer,
+) PasswordResetService {
+	return &passwordResetService{
+		resetRepo:     resetRepo,
+		throttleRepo:  throttleRepo,
+		userRepo:      userRepo,
+		auditSvc:      auditSvc,
+		accessRevoker: accessRevoker,
+		passwordSvc:   passwordSvc,
+		mailer:        mail,
+		baseURL:       strings.TrimRight(utils.GetEnv("APP_BASE_URL", "http://localhost:5173"), "/"),
+		ttl:           utils.GetEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		limits:        ResetRequestLimitsFromEnv(),
+	}
+}
+
+// RequestReset emails a reset link when the email belongs to an active user.
+// It reports success either way so callers cannot probe for accounts: the
+// rate limit counts every request, and failures after the lookup are only
+// logged. A *LoginThrottledError is returned once a limit is exceeded.
+func (s *passwordResetService) RequestReset(email, ipAddress string) error {
+	if err := s.throttle(normalizeEmail(email), ipAddress); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil || !user.IsActive || user.Role == authz.RoleServiceAccount {
+		return nil
+	}
+
+	if err := s.sendResetLink(user, ipAddress); err != nil {
+		log.Printf("sending password reset link to %s: %v", user.ID, err)
+		return nil
+	}
+
+	s.auditSvc.Log(user.ID, "PASSWORD_RESET_REQUESTED", "user", &user.ID, map[string]interface{}{
+		"ip": ipAddress,
+	})
+	return nil
+}
+
+// throttle counts the request against the email and the client IP, whether
+// or not the email belongs to anyone.
+func (s *passwordResetService) throttle(email, ipAddress string) error {
+	limits := []struct {
+		scope, identifier string
+		max               int
+	}{
+		{models.LoginThrottleScopeResetIP, ipAddress, s.limits.MaxPerIP},
+		{models.LoginThrottleScopeResetEmail, email, s.limits.MaxPerEmail},
+	}
+	for _, limit := range limits {
+		if limit.identifier == "" || limit.max <= 0 {
+			continue
+		}
+		throttle, err := s.throttleRepo.RecordFailure(limit.scope, limit.identifier, s.limits.Window)
+		if err != nil {
+			return err
+		}
+		if throttle.Failures > limit.max {
+			return &LoginThrottledError{RetryAfter: s.limits.Window}
+		}
+	}
+	return nil
+}
+
+func (s *passwordResetService) sendResetLink(user *models.User, ipAddress string) error {
+	// Only the most recent link stays valid
+	if err := s.resetRepo.InvalidateForUser(user.ID); err != nil {
+		return err
+	}
+
+	rawToken, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return err
+	}
+
+	record := &models.PasswordResetToken{
+		UserID:      user.ID,
+		TokenHash:   utils.HashToken(rawToken),
+		ExpiresAt:   time.Now().UTC().Add(s.ttl),
+		RequestedIP: ipAddress,
+	}
+	if err := s.resetRepo.Create(record); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/reset-password?token=" + url.QueryEscape(rawToken)
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your StaffPoint password",
+		Body: fmt.Sprintf(
+			"A password reset was requested for your StaffPoint account.\n\n"+
+				"Open the link below to choose a new password. It expires in %s and can be used once.\n\n%s\n\n"+
+				"If you did not request this, you can ignore this email.\n",
+			s.ttl, link,
+		),
+	})
+}
+
+// ResetPassword consumes the token, sets the new password and signs the user
+// out of every existing session.
+func (s *passwordResetService) ResetPassword(token, newPassword string) error {
+	record, err := s.resetRepo.FindByHash(utils.HashToken(token))
+	if err != nil || record.UsedAt != nil || time.Now().After(record.ExpiresAt) {
+		return errInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil || !user.IsActive {
+		return errInvalidResetToken
+	}
+
+	// Reject a weak or reused password before the link is spent
+	if err := s.passwordSvc.Validate(user, newPassword); err != nil {
+		return err
+	}
+
+	consumed, err := s.resetRepo.MarkUsed(record.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return errInvalidResetToken
+	}
+
+	if err := s.passwordSvc.ChangePassword(user, newPassword); err != nil {
+		return err
+	}
+
+	if err := s.accessRevoker.RevokeUserAccess(user.ID); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(user.ID, "PASSWORD_RESET", "user", &user.ID, nil)
+	return nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/mailer"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+type memoryResetRepo struct {
+	repositories.PasswordResetRepository
+	tokens []*models.PasswordResetToken
+}
+
+func (r *memoryResetRepo) Create(token *models.PasswordResetToken) error {
+	token.ID = uuid.New()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryResetRepo) InvalidateForUser(userID uuid.UUID) error {
+	return nil
+}
+
+type memoryMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (m *memoryMailer) Send(msg mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func newTestPasswordReset(mail *memoryMailer, limits ResetRequestLimits, users ...*models.User) *passwordResetService {
+	return &passwordResetService{
+		resetRepo:    &memoryResetRepo{},
+		throttleRepo: newMemoryThrottleRepo(),
+		userRepo:     newMemoryUserRepo(users...),
+		auditSvc:     &memoryAudit{},
+		mailer:       mail,
+		ttl:          time.Hour,
+		limits:       limits,
+	}
+}
+
+func TestRequestResetHidesMailFailures(t *testing.T) {
+	mail := &memoryMailer{err: errors.New("smtp unavailable")}
+	svc := newTestPasswordReset(mail, ResetRequestLimits{}, &models.User{Email: "alice@example.com", IsActive: true})
+
+	if err := svc.RequestReset("alice@example.com", "203.0.113.7"); err != nil {
+		t.Fatalf("expected a mail failure to look like success, got %v", err)
+	}
+	if err := svc.RequestReset("nobody@example.com", "203.0.113.7"); err != nil {
+		t.Fatalf("expected unknown emails to succeed, got %v", err)
+	}
+
+	mail.err = nil
+	if err := svc.RequestReset("alice@example.com", "203.0.113.7"); err != nil {
+		t.Fatalf("request reset: %v", err)
+	}
+	if len(mail.sent) != 1 || mail.sent[0].To != "alice@example.com" {
+		t.Fatalf("expected one reset email to alice, got %+v", mail.sent)
+	}
+}
+
+func TestRequestResetLimitsPerEmailAndPerIP(t *testing.T) {
+	mail := &memoryMailer{}
+	svc := newTestPasswordReset(mail, ResetRequestLimits{MaxPerEmail: 2, MaxPerIP: 4, Window: time.Hour},
+		&models.User{Email: "alice@example.com", IsActive: true})
+
+	// Unknown and known emails are limited alike, so the limit does not
+	// reveal which accounts exist.
+	for _, email := range []string{"alice@example.com", "nobody@example.com"} {
+		for i := 0; i < 2; i++ {
+			if err := svc.RequestReset(email, "203.0.113.7"); err != nil {
+				t.Fatalf("request %d for %s: %v", i, email, err)
+			}
+		}
+		var throttled *LoginThrottledError
+		if err := svc.RequestReset(" "+email, "198.51.100.1"); !errors.As(err, &throttled) {
+			t.Fatalf("expected %s to be limited, got %v", email, err)
+		}
+	}
+	if len(mail.sent) != 2 {
+		t.Fatalf("expected the limited request not to send mail, got %d emails", len(mail.sent))
+	}
+
+	var throttled *LoginThrottledError
+	if err := svc.RequestReset("carol@example.com", "203.0.113.7"); !errors.As(err, &throttled) {
+		t.Fatalf("expected the IP to be limited across emails, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+// ErrPasswordReused is returned when a new password matches the current one
+// or one of the last PasswordPolicy.HistorySize passwords.
+var ErrPasswordReused = errors.New("password was used recently")
+
+// PasswordService applies the password policy and history to every place a
+// user-chosen password is set.
+type PasswordService interface {
+	Policy() utils.PasswordPolicy
+	HashNewPassword(password, email string) (string, error)
+	Validate(user *models.User, password string) error
+	ChangePassword(user *models.User, newPassword string) error
+	Remember(userID uuid.UUID, hash string) error
+	IsExpired(user *models.User) bool
+}
+
+type passwordService struct {
+	historyRepo repositories.PasswordHistoryRepository
+	userRepo    repositories.UserRepository
+	policy      utils.PasswordPolicy
+}
+
+func NewPasswordService(
+	historyRepo repositories.PasswordHistoryRepository,
+	userRepo repositories.UserRepository,
+	policy utils.PasswordPolicy,
+) PasswordService {
+	return &passwordService{historyRepo: historyRepo, userRepo: userRepo, policy: policy}
+}
+
+func (s *passwordService) Policy() utils.PasswordPolicy {
+	return s.policy
+}
+
+// HashNewPassword validates the password of an account that does not exist
+// yet and returns its hash. Call Remember once the user is created.
+func (s *passwordService) HashNewPassword(password, email string) (string, error) {
+	if err := s.policy.Validate(password, email); err != nil {
+		return "", err
+	}
+	return utils.HashPassword(password)
+}
+
+// Validate checks the policy and the user's password history.
+func (s *passwordService) Validate(user *models.User, password string) error {
+	if err := s.policy.Validate(password, user.Email); err != nil {
+		return err
+	}
+
+	if utils.CheckPassword(user.PasswordHash, password) == nil {
+		return ErrPasswordReused
+	}
+	if s.policy.HistorySize <= 0 {
+		return nil
+	}
+
+	history, err := s.historyRepo.ListRecent(user.ID, s.policy.HistorySize)
+	if err != nil {
+		return err
+	}
+	for _, entry := range history {
+		if utils.CheckPassword(entry.PasswordHash, password) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// ChangePassword validates and stores a new password for an existing user.
+func (s *passwordService) ChangePassword(user *models.User, newPassword string) error {
+	if err := s.Validate(user, newPassword); err != nil {
+		return err
+	}
+
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	user.PasswordHash = hashed
+	user.PasswordChangedAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.Remember(user.ID, hashed)
+}
+
+// Remember records hash in the user's history and drops entries beyond the
+// configured history size.
+func (s *passwordService) Remember(userID uuid.UUID, hash string) error {
+	if s.policy.HistorySize <= 0 {
+		return nil
+	}
+	if err := s.historyRepo.Create(&models.PasswordHistory{UserID: userID, PasswordHash: hash}); err != nil {
+		return err
+	}
+	return s.historyRepo.Prune(userID, s.policy.HistorySize)
+}
+
+func (s *passwordService) IsExpired(user *models.User) bool {
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return s.policy.IsExpired(changedAt)
+}
@@ -11,10 +11,12 @@ import (
 )
 
 type PayslipService interface {
-	Generate(employeeID uuid.UUID, month, year int, basicPay, allowances, deductions float64, currency string, generatedBy uuid.UUID) (*models.Payslip, error)
+	Generate(employeeID uuid.UUID, month, year int, basicPay, allowances, deductions float64, currency string, generatedBy uuid.UUID, scope Scope) (*models.Payslip, error)
 	ListMine(userID uuid.UUID, limit int) ([]models.Payslip, error)
-	ListAll(limit int, employeeID *uuid.UUID, month, year *int) ([]models.Payslip, error)
-	GetByID(id uuid.UUID) (*models.Payslip, error)
+	ListAll(limit int, employeeID *uuid.UUID, month, year *int, scope Scope) ([]models.Payslip, error)
+	// GetByID returns the viewer's own payslip, or, when scope is non-nil,
+	// any payslip within it.
+	GetByID(id, viewerID uuid.UUID, scope *Scope) (*models.Payslip, error)
 }
 
 type payslipService struct {
@@ -27,7 +29,7 @@ func NewPayslipService(payslipRepo repositories.PayslipRepository, employeeRepo
 	return &payslipService{payslipRepo: payslipRepo, employeeRepo: employeeRepo, auditSvc: auditSvc}
 }
 
-func (s *payslipService) Generate(employeeID uuid.UUID, month, year int, basicPay, allowances, deductions float64, currency string, generatedBy uuid.UUID) (*models.Payslip, error) {
+func (s *payslipService) Generate(employeeID uuid.UUID, month, year int, basicPay, allowances, deductions float64, currency string, generatedBy uuid.UUID, scope Scope) (*models.Payslip, error) {
 	if month < 1 || month > 12 {
 		return nil, errors.New("month must be between 1 and 12")
 	}
@@ -42,6 +44,9 @@ func (s *payslipService) Generate(employeeID uuid.UUID, month, year int, basicPa
 	if err != nil {
 		return nil, err
 	}
+	if !scope.AllowsEmployee(employee, generatedBy) {
+		return nil, ErrOutOfScope
+	}
 
 	net := basicPay + allowances - deductions
 	if net < 0 {
@@ -90,10 +95,24 @@ func (s *payslipService) ListMine(userID uuid.UUID, limit int) ([]models.Payslip
 	return s.payslipRepo.ListByUser(userID, limit)
 }
 
-func (s *payslipService) ListAll(limit int, employeeID *uuid.UUID, month, year *int) ([]models.Payslip, error) {
-	return s.payslipRepo.ListAll(limit, employeeID, month, year)
+func (s *payslipService) ListAll(limit int, employeeID *uuid.UUID, month, year *int, scope Scope) ([]models.Payslip, error) {
+	employeeScope, empty := scope.filter()
+	if empty {
+		return []models.Payslip{}, nil
+	}
+	return s.payslipRepo.ListAll(limit, employeeID, month, year, employeeScope)
 }
 
-func (s *payslipService) GetByID(id uuid.UUID) (*models.Payslip, error) {
-	return s.payslipRepo.FindByID(id)
+func (s *payslipService) GetByID(id, viewerID uuid.UUID, scope *Scope) (*models.Payslip, error) {
+	payslip, err := s.payslipRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if payslip.UserID == viewerID {
+		return payslip, nil
+	}
+	if scope == nil || !scope.reaches(&payslip.Employee) {
+		return nil, ErrOutOfScope
+	}
+	return payslip, nil
 }
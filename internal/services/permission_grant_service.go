@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var (
+	errGrantTarget       = errors.New("exactly one of permission or delegator_id is required")
+	errGrantReason       = errors.New("a reason is required")
+	errGrantWindow       = errors.New("ends_at must be in the future and after starts_at")
+	errGrantTooLong      = errors.New("grant is longer than the maximum allowed duration")
+	errGrantSelf         = errors.New("you cannot grant permissions to yourself")
+	errGrantGrantee      = errors.New("grantee must be an active user")
+	errGrantDelegator    = errors.New("delegator must be another active, non-admin user")
+	errGrantNotGrantable = errors.New("this permission cannot be granted temporarily")
+	errGrantRevoked      = errors.New("grant is already revoked")
+)
+
+// GrantRequest describes a temporary grant of one permission, or a
+// delegation of everything DelegatorID may do.
+type GrantRequest struct {
+	GranteeID   uuid.UUID
+	Permission  string
+	DelegatorID *uuid.UUID
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Reason      string
+}
+
+// EffectiveGrants is what a user's grants in force add to their role.
+type EffectiveGrants struct {
+	Permissions  []string
+	DelegatorIDs []uuid.UUID
+}
+
+// PermissionGrantService manages time-boxed grants and delegations.
+// Effective is consulted on every authenticated request.
+type PermissionGrantService interface {
+	Grant(req GrantRequest, adminID uuid.UUID) (*models.PermissionGrant, error)
+	List(granteeID *uuid.UUID, activeOnly bool) ([]models.PermissionGrant, error)
+	Revoke(id, adminID uuid.UUID) error
+	Effective(userID uuid.UUID) (EffectiveGrants, error)
+}
+
+type cachedGrants struct {
+	grants EffectiveGrants
+	until  time.Time
+}
+
+type permissionGrantService struct {
+	repo        repositories.PermissionGrantRepository
+	userRepo    repositories.UserRepository
+	auditSvc    AuditService
+	ttl         time.Duration
+	maxDuration time.Duration
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedGrants
+}
+
+func NewPermissionGrantService(
+	repo repositories.PermissionGrantRepository,
+	userRepo repositories.UserRepository,
+	auditSvc AuditService,
+) PermissionGrantService {
+	return &permissionGrantService{
+		repo:        repo,
+		userRepo:    userRepo,
+		auditSvc:    auditSvc,
+		ttl:         utils.GetEnvDuration("GRANT_CACHE_TTL", 30*time.Second),
+		maxDuration: utils.GetEnvDuration("GRANT_MAX_DURATION", 90*24*time.Hour),
+		cache:       make(map[uuid.UUID]cachedGrants),
+	}
+}
+
+func (s *permissionGrantService) Grant(req GrantRequest, adminID uuid.UUID) (*models.PermissionGrant, error) {
+	now := time.Now().UTC()
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errGrantReason
+	}
+	if (req.Permission == "") == (req.DelegatorID == nil) {
+		return nil, errGrantTarget
+	}
+	if req.StartsAt.IsZero() {
+		req.StartsAt = now
+	}
+	if !req.EndsAt.After(req.StartsAt) || !req.EndsAt.After(now) {
+		return nil, errGrantWindow
+	}
+	if req.EndsAt.Sub(req.StartsAt) > s.maxDuration {
+		return nil, errGrantTooLong
+	}
+	if req.GranteeID == adminID {
+		return nil, errGrantSelf
+	}
+
+	grantee, err := s.userRepo.FindByID(req.GranteeID)
+	if err != nil || !grantee.IsActive || grantee.Role == authz.RoleServiceAccount {
+		return nil, errGrantGrantee
+	}
+
+	metadata := map[string]interface{}{
+		"grantee_id": grantee.ID,
+		"starts_at":  req.StartsAt,
+		"ends_at":    req.EndsAt,
+		"reason":     reason,
+	}
+	if req.DelegatorID != nil {
+		delegator, err := s.userRepo.FindByID(*req.DelegatorID)
+		if err != nil || !delegator.IsActive || delegator.ID == grantee.ID ||
+			authz.HasRole(delegator.Role, authz.RoleAdmin, authz.RoleServiceAccount) {
+			return nil, errGrantDelegator
+		}
+		metadata["delegator_id"] = delegator.ID
+	} else {
+		if !authz.IsGrantable(req.Permission) {
+			return nil, errGrantNotGrantable
+		}
+		metadata["permission"] = req.Permission
+	}
+
+	grant := &models.PermissionGrant{
+		GranteeID:   grantee.ID,
+		Permission:  req.Permission,
+		DelegatorID: req.DelegatorID,
+		StartsAt:    req.StartsAt.UTC(),
+		EndsAt:      req.EndsAt.UTC(),
+		Reason:      reason,
+		GrantedBy:   adminID,
+	}
+	if err := s.repo.Create(grant); err != nil {
+		return nil, err
+	}
+	s.invalidate(grantee.ID)
+
+	s.auditSvc.Log(adminID, "PERMISSION_GRANTED", "permission_grant", &grant.ID, metadata)
+	return grant, nil
+}
+
+func (s *permissionGrantService) List(granteeID *uuid.UUID, activeOnly bool) ([]models.PermissionGrant, error) {
+	var activeAt *time.Time
+	if activeOnly {
+		now := time.Now().UTC()
+		activeAt = &now
+	}
+	return s.repo.List(granteeID, activeAt)
+}
+
+func (s *permissionGrantService) Revoke(id, adminID uuid.UUID) error {
+	grant, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if grant.RevokedAt != nil {
+		return errGrantRevoked
+	}
+
+	if err := s.repo.Revoke(id, adminID, time.Now().UTC()); err != nil {
+		return err
+	}
+	s.invalidate(grant.GranteeID)
+
+	s.auditSvc.Log(adminID, "PERMISSION_GRANT_REVOKED", "permission_grant", &grant.ID, map[string]interface{}{
+		"grantee_id": grant.GranteeID,
+	})
+	return nil
+}
+
+// Effective resolves the grants in force for userID. Delegations carry the
+// delegator's current role permissions. Results are cached until the ttl
+// passes or a grant starts or ends, whichever comes first.
+func (s *permissionGrantService) Effective(userID uuid.UUID) (EffectiveGrants, error) {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.until) {
+		return cached.grants, nil
+	}
+
+	grants, err := s.repo.ListUnexpired(userID, now)
+	if err != nil {
+		return EffectiveGrants{}, err
+	}
+
+	var effective EffectiveGrants
+	until := now.Add(s.ttl)
+	for _, grant := range grants {
+		if grant.StartsAt.After(now) {
+			until = earliestTime(until, grant.StartsAt)
+			continue
+		}
+		until = earliestTime(until, grant.EndsAt)
+
+		granted := []string{grant.Permission}
+		if grant.DelegatorID != nil {
+			delegator, err := s.userRepo.FindByID(*grant.DelegatorID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return EffectiveGrants{}, err
+			}
+			if !delegator.IsActive {
+				continue
+			}
+			effective.DelegatorIDs = append(effective.DelegatorIDs, delegator.ID)
+			granted = authz.PermissionsForRole(delegator.Role)
+		}
+
+		for _, permission := range granted {
+			if authz.IsGrantable(permission) && !authz.HasPermission(effective.Permissions, permission) {
+				effective.Permissions = append(effective.Permissions, permission)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[userID] = cachedGrants{grants: effective, until: until}
+	s.mu.Unlock()
+	return effective, nil
+}
+
+func (s *permissionGrantService) invalidate(userID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	s.mu.Unlock()
+}
+
+func earliestTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
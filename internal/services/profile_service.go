@@ -1,7 +1,6 @@
 package services
 
 import (
-	"golang.org/x/crypto/bcrypt"
 	"github.com/google/uuid"
 
 	"go-backend/internal/models"
@@ -12,6 +11,7 @@ type ProfileService struct {
 	userRepo     repositories.UserRepository
 	employeeRepo repositories.EmployeeRepository
 	auditSvc     AuditService
+	passwordSvc  PasswordService
 }
 
 // NewProfileService
@@ -19,11 +19,13 @@ func NewProfileService(
 	userRepo repositories.UserRepository,
 	employeeRepo repositories.EmployeeRepository,
 	auditSvc AuditService,
+	passwordSvc PasswordService,
 ) *ProfileService {
 	return &ProfileService{
 		userRepo: userRepo,
 		employeeRepo: employeeRepo,
 		auditSvc: auditSvc,
+		passwordSvc: passwordSvc,
 	}
 }
 
@@ -58,12 +60,7 @@ func (s *ProfileService) UpdateProfile(
 
 	// Update password if provided
 	if newPassword != "" {
-		hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-		if err != nil {
-			return nil, err
-		}
-		user.PasswordHash = string(hashed)
-		if err := s.userRepo.Update(user); err != nil {
+		if err := s.passwordSvc.ChangePassword(user, newPassword); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+)
+
+// RedactedValue replaces hidden text in exports.
+const RedactedValue = "[redacted]"
+
+// FieldAccess says which sensitive fields a caller may read about other
+// people. Callers always see their own data.
+type FieldAccess struct {
+	ViewerID     uuid.UUID
+	Compensation bool
+	PersonalData bool
+}
+
+func FieldAccessFor(viewerID uuid.UUID, permissions []string) FieldAccess {
+	return FieldAccess{
+		ViewerID:     viewerID,
+		Compensation: authz.HasPermission(permissions, authz.PermViewCompensation),
+		PersonalData: authz.HasPermission(permissions, authz.PermViewPersonalData),
+	}
+}
+
+// CompensationOf reports whether pay amounts of userID are visible.
+func (a FieldAccess) CompensationOf(userID uuid.UUID) bool {
+	return a.Compensation || userID == a.ViewerID
+}
+
+// PersonalDataOf reports whether email and hire date of userID are visible.
+func (a FieldAccess) PersonalDataOf(userID uuid.UUID) bool {
+	return a.PersonalData || userID == a.ViewerID
+}
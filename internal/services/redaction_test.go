@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/authz"
+)
+
+func TestFieldAccessFollowsPermissions(t *testing.T) {
+	viewerID := uuid.New()
+	otherID := uuid.New()
+
+	manager := FieldAccessFor(viewerID, authz.PermissionsForRole(authz.RoleManager))
+	if manager.CompensationOf(otherID) {
+		t.Fatal("managing payslips alone should not reveal pay amounts")
+	}
+	if !manager.CompensationOf(viewerID) {
+		t.Fatal("everyone should see their own pay")
+	}
+	if !manager.PersonalDataOf(otherID) {
+		t.Fatal("managers should keep seeing personal data by default")
+	}
+
+	admin := FieldAccessFor(viewerID, authz.PermissionsForRole(authz.RoleAdmin))
+	if !admin.CompensationOf(otherID) || !admin.PersonalDataOf(otherID) {
+		t.Fatal("admins should see every field")
+	}
+
+	reviewer := FieldAccessFor(viewerID, []string{authz.PermReviewLeaves})
+	if reviewer.PersonalDataOf(otherID) || !reviewer.PersonalDataOf(viewerID) {
+		t.Fatal("without view_personal_data only the caller's own details should show")
+	}
+}
@@ -19,7 +19,8 @@ func NewReportService(repo repositories.ReportRepository) *ReportService {
 	return &ReportService{repo}
 }
 
-func (s *ReportService) ExportCSV(w io.Writer, from, to time.Time) error {
+// ExportCSV writes the attendance report, hiding emails the caller may not see.
+func (s *ReportService) ExportCSV(w io.Writer, from, to time.Time, access FieldAccess) error {
 	rows, err := s.repo.AttendanceReport(from, to)
 	if err != nil {
 		return err
@@ -33,7 +34,7 @@ func (s *ReportService) ExportCSV(w io.Writer, from, to time.Time) error {
 	for _, r := range rows {
 		writer.Write([]string{
 			r.Date.Format("2006-01-02"),
-			r.Email,
+			reportEmail(r, access),
 			formatTime(r.ClockIn),
 			formatTime(r.ClockOut),
 		})
@@ -42,6 +43,13 @@ func (s *ReportService) ExportCSV(w io.Writer, from, to time.Time) error {
 	return nil
 }
 
+func reportEmail(row repositories.AttendanceReportRow, access FieldAccess) string {
+	if !access.PersonalDataOf(row.UserID) {
+		return RedactedValue
+	}
+	return row.Email
+}
+
 func formatTime(t *time.Time) string {
 	if t == nil {
 		return ""
@@ -50,7 +58,7 @@ func formatTime(t *time.Time) string {
 }
 
 
-func (s *ReportService) ExportPDF(w io.Writer, from, to time.Time) error {
+func (s *ReportService) ExportPDF(w io.Writer, from, to time.Time, access FieldAccess) error {
 	rows, err := s.repo.AttendanceReport(from, to)
 	if err != nil {
 		return err
@@ -67,7 +75,7 @@ func (s *ReportService) ExportPDF(w io.Writer, from, to time.Time) error {
 	for _, r := range rows {
 		pdf.Cell(0, 8,
 			r.Date.Format("2006-01-02")+" | "+
-				r.Email+" | "+
+				reportEmail(r, access)+" | "+
 				formatTime(r.ClockIn)+" - "+
 				formatTime(r.ClockOut),
 		)
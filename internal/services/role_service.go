@@ -0,0 +1,262 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var (
+	ErrRoleExists      = errors.New("role already exists")
+	errInvalidRoleName = errors.New("name must be 2-50 lowercase letters, digits or underscores, starting with a letter")
+	roleNamePattern    = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+)
+
+// RoleDefinition is a role together with the names of its permissions.
+type RoleDefinition struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"`
+	Permissions []string  `json:"permissions"`
+}
+
+// RoleService manages roles stored in the database. It is also the
+// authz.RoleStore, serving role lookups from a cache refreshed every ttl.
+type RoleService interface {
+	authz.RoleStore
+
+	List() ([]RoleDefinition, error)
+	Get(name string) (*RoleDefinition, error)
+	ListPermissions() ([]models.Permission, error)
+	Create(name, description string, permissions []string, adminID uuid.UUID) (*RoleDefinition, error)
+	Update(name, description string, permissions []string, adminID uuid.UUID) (*RoleDefinition, error)
+	Delete(name string, adminID uuid.UUID) error
+}
+
+type roleService struct {
+	repo     repositories.RoleRepository
+	auditSvc AuditService
+	ttl      time.Duration
+
+	mu       sync.Mutex
+	cache    map[string][]string
+	loadedAt time.Time
+}
+
+func NewRoleService(repo repositories.RoleRepository, auditSvc AuditService) RoleService {
+	return &roleService{
+		repo:     repo,
+		auditSvc: auditSvc,
+		ttl:      utils.GetEnvDuration("ROLE_CACHE_TTL", 30*time.Second),
+	}
+}
+
+// RolePermissions answers from the cache. When the database cannot be read
+// the last loaded roles keep being served; before the first load succeeds
+// ok is false and authz falls back to its defaults.
+func (s *roleService) RolePermissions(role string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil || time.Since(s.loadedAt) >= s.ttl {
+		if roles, err := s.repo.List(); err == nil {
+			s.cache = make(map[string][]string, len(roles))
+			for i := range roles {
+				s.cache[roles[i].Name] = roleDefinition(&roles[i]).Permissions
+			}
+			s.loadedAt = time.Now()
+		}
+	}
+	if s.cache == nil {
+		return nil, false
+	}
+
+	permissions, ok := s.cache[role]
+	return permissions, ok
+}
+
+func (s *roleService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *roleService) List() ([]RoleDefinition, error) {
+	roles, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]RoleDefinition, 0, len(roles))
+	for i := range roles {
+		definitions = append(definitions, roleDefinition(&roles[i]))
+	}
+	return definitions, nil
+}
+
+func (s *roleService) Get(name string) (*RoleDefinition, error) {
+	role, err := s.repo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	definition := roleDefinition(role)
+	return &definition, nil
+}
+
+func (s *roleService) ListPermissions() ([]models.Permission, error) {
+	return s.repo.ListPermissions()
+}
+
+func (s *roleService) Create(name, description string, permissions []string, adminID uuid.UUID) (*RoleDefinition, error) {
+	name = strings.TrimSpace(name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, errInvalidRoleName
+	}
+	if _, err := s.repo.FindByName(name); err == nil {
+		return nil, ErrRoleExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	scope, err := validatePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{Name: name, Description: strings.TrimSpace(description)}
+	if err := s.repo.Create(role, scope); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.auditSvc.Log(adminID, "ROLE_CREATED", "role", &role.ID, map[string]interface{}{
+		"name":        name,
+		"permissions": scope,
+	})
+	definition := roleDefinition(role)
+	return &definition, nil
+}
+
+// Update replaces the description and permission set. The admin role is
+// locked so nobody can remove their own way back in, and the service
+// account role stays empty because API keys carry their own scope.
+func (s *roleService) Update(name, description string, permissions []string, adminID uuid.UUID) (*RoleDefinition, error) {
+	role, err := s.repo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := validatePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := roleDefinition(role).Permissions
+	switch role.Name {
+	case authz.RoleAdmin:
+		if !samePermissions(previous, scope) {
+			return nil, errors.New("the admin role's permissions cannot be changed")
+		}
+	case authz.RoleServiceAccount:
+		if len(scope) > 0 {
+			return nil, errors.New("the service account role cannot hold permissions")
+		}
+	}
+
+	role.Description = strings.TrimSpace(description)
+	if err := s.repo.Update(role, scope); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.auditSvc.Log(adminID, "ROLE_UPDATED", "role", &role.ID, map[string]interface{}{
+		"name":    role.Name,
+		"added":   permissionDiff(scope, previous),
+		"removed": permissionDiff(previous, scope),
+	})
+	definition := roleDefinition(role)
+	return &definition, nil
+}
+
+func (s *roleService) Delete(name string, adminID uuid.UUID) error {
+	role, err := s.repo.FindByName(name)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return errors.New("system roles cannot be deleted")
+	}
+
+	users, err := s.repo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return fmt.Errorf("role is still assigned to %d user(s)", users)
+	}
+
+	if err := s.repo.Delete(role.ID); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	s.auditSvc.Log(adminID, "ROLE_DELETED", "role", &role.ID, map[string]interface{}{
+		"name": role.Name,
+	})
+	return nil
+}
+
+func roleDefinition(role *models.Role) RoleDefinition {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, grant := range role.Permissions {
+		permissions = append(permissions, grant.PermissionName)
+	}
+	return RoleDefinition{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: permissions,
+	}
+}
+
+// validatePermissions rejects unknown permissions and drops duplicates.
+func validatePermissions(permissions []string) ([]string, error) {
+	scope := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !authz.IsPermission(permission) {
+			return nil, errors.New("unknown permission: " + permission)
+		}
+		if !authz.HasPermission(scope, permission) {
+			scope = append(scope, permission)
+		}
+	}
+	return scope, nil
+}
+
+func samePermissions(a, b []string) bool {
+	return len(permissionDiff(a, b)) == 0 && len(permissionDiff(b, a)) == 0
+}
+
+// permissionDiff returns the permissions in a that are not in b.
+func permissionDiff(a, b []string) []string {
+	diff := []string{}
+	for _, permission := range a {
+		if !authz.HasPermission(b, permission) {
+			diff = append(diff, permission)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+// ErrOutOfScope is returned when an actor reaches for an employee outside
+// the departments they manage.
+var ErrOutOfScope = errors.New("employee is outside your departments")
+
+// Scope limits which employees an actor may act on. A global scope reaches
+// everyone; otherwise only employees in DepartmentIDs and those in ReportIDs,
+// who report directly or indirectly to the actor, are reachable.
+// OnBehalfOf lists the users who delegated to the actor. Permissions are the
+// actor's effective permissions, which bound the roles they may hand out.
+type Scope struct {
+	Global        bool
+	DepartmentIDs []uuid.UUID
+	ReportIDs     []uuid.UUID
+	OnBehalfOf    []uuid.UUID
+	Permissions   []string
+}
+
+// GlobalScope is the scope of actors holding authz.PermManageAllDepartments.
+func GlobalScope() Scope {
+	return Scope{Global: true}
+}
+
+// Allows reports whether an employee in departmentID is within the scope.
+// Employees without a department are only reachable globally.
+func (s Scope) Allows(departmentID *uuid.UUID) bool {
+	if s.Global {
+		return true
+	}
+	if departmentID == nil {
+		return false
+	}
+	for _, id := range s.DepartmentIDs {
+		if id == *departmentID {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsEmployee reports whether actorID may act on employee. Scoped actors
+// never reach their own record, so a manager cannot change their own role,
+// approve their own pay or deactivate themselves. The same holds for the
+// records of anyone who delegated to them.
+func (s Scope) AllowsEmployee(employee *models.Employee, actorID uuid.UUID) bool {
+	if s.Global {
+		return true
+	}
+	if employee.UserID == actorID {
+		return false
+	}
+	for _, delegatorID := range s.OnBehalfOf {
+		if employee.UserID == delegatorID {
+			return false
+		}
+	}
+	return s.reaches(employee)
+}
+
+// reaches reports whether employee is in a scoped department or reporting
+// line, without excluding the actor's own record.
+func (s Scope) reaches(employee *models.Employee) bool {
+	return s.Allows(employee.DepartmentID) || containsUUID(s.ReportIDs, employee.ID)
+}
+
+// filter returns the employees list queries are restricted to, nil meaning
+// unrestricted. empty is true when the scope reaches nobody, in which case
+// callers should skip the query.
+func (s Scope) filter() (scope *repositories.EmployeeScope, empty bool) {
+	if s.Global {
+		return nil, false
+	}
+	scope = &repositories.EmployeeScope{DepartmentIDs: s.DepartmentIDs, EmployeeIDs: s.ReportIDs}
+	return scope, len(s.DepartmentIDs) == 0 && len(s.ReportIDs) == 0
+}
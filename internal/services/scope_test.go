@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestScopeLimitsManagersToTheirDepartments(t *testing.T) {
+	managed := uuid.New()
+	other := uuid.New()
+	managerID := uuid.New()
+	scope := Scope{DepartmentIDs: []uuid.UUID{managed}}
+
+	report := &models.Employee{UserID: uuid.New(), DepartmentID: &managed}
+	if !scope.AllowsEmployee(report, managerID) {
+		t.Fatal("manager should reach employees in their department")
+	}
+	if scope.AllowsEmployee(&models.Employee{UserID: uuid.New(), DepartmentID: &other}, managerID) {
+		t.Fatal("manager should not reach other departments")
+	}
+	if scope.AllowsEmployee(&models.Employee{UserID: uuid.New()}, managerID) {
+		t.Fatal("manager should not reach employees without a department")
+	}
+	if scope.AllowsEmployee(&models.Employee{UserID: managerID, DepartmentID: &managed}, managerID) {
+		t.Fatal("manager should not act on their own record")
+	}
+	if !GlobalScope().AllowsEmployee(&models.Employee{UserID: managerID}, managerID) {
+		t.Fatal("global scope should reach everyone")
+	}
+
+	if _, empty := (Scope{}).filter(); !empty {
+		t.Fatal("a scope without departments or reports should reach nobody")
+	}
+	if filter, empty := GlobalScope().filter(); filter != nil || empty {
+		t.Fatal("global scope should not filter")
+	}
+}
+
+func TestScopeReachesReportingLines(t *testing.T) {
+	managerID := uuid.New()
+	otherDepartment := uuid.New()
+	report := &models.Employee{UserID: uuid.New(), DepartmentID: &otherDepartment}
+	report.ID = uuid.New()
+	scope := Scope{ReportIDs: []uuid.UUID{report.ID}}
+
+	if !scope.AllowsEmployee(report, managerID) {
+		t.Fatal("manager should reach reports outside their departments")
+	}
+	if scope.Allows(&otherDepartment) {
+		t.Fatal("a report should not open up their whole department")
+	}
+	if _, empty := scope.filter(); empty {
+		t.Fatal("a scope with reports should reach them in list queries")
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+var errSessionNotFound = errors.New("session not found")
+
+// SessionService lists a user's active sessions and revokes them remotely.
+type SessionService interface {
+	ListForUser(userID uuid.UUID) ([]models.Session, error)
+	Revoke(userID, sessionID, actorID uuid.UUID) error
+}
+
+type sessionService struct {
+	sessionRepo      repositories.SessionRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	denylist         TokenDenylist
+	auditSvc         AuditService
+}
+
+func NewSessionService(
+	sessionRepo repositories.SessionRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	denylist TokenDenylist,
+	auditSvc AuditService,
+) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		denylist:         denylist,
+		auditSvc:         auditSvc,
+	}
+}
+
+func (s *sessionService) ListForUser(userID uuid.UUID) ([]models.Session, error) {
+	return s.sessionRepo.ListActiveForUser(userID, time.Now().UTC())
+}
+
+// Revoke ends one session of userID: its refresh tokens stop working and its
+// access tokens are rejected by AuthMiddleware. actorID is the user or the
+// admin doing it.
+func (s *sessionService) Revoke(userID, sessionID, actorID uuid.UUID) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return errSessionNotFound
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(session.ID); err != nil {
+		return err
+	}
+	if err := s.denylist.RevokeSession(session.ID, &actorID); err != nil {
+		return err
+	}
+
+	s.auditSvc.Log(actorID, "SESSION_REVOKED", "session", &session.ID, map[string]interface{}{
+		"user_id": userID.String(),
+		"device":  session.Device,
+	})
+	return nil
+}
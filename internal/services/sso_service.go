@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/oidc"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+var (
+	ErrSSODisabled        = errors.New("single sign-on is not configured")
+	ErrSSONoAccount       = errors.New("no account is linked to this identity")
+	errInvalidSSOState    = errors.New("invalid or expired login state")
+	errSSOProviderFailure = errors.New("identity provider login failed")
+)
+
+// ssoRolePrecedence decides which role wins when a user's IdP groups map to
+// more than one role.
+var ssoRolePrecedence = []string{authz.RoleAdmin, authz.RoleManager, authz.RoleEmployee}
+
+// OIDCConfig configures OpenID Connect single sign-on.
+type OIDCConfig struct {
+	Provider oidc.Config
+
+	// RoleMapping maps IdP group names to StaffPoint roles.
+	RoleMapping map[string]string
+	// DefaultRole is given to provisioned users none of whose groups map.
+	DefaultRole string
+	// DefaultDepartmentID is the department provisioned employees join.
+	// Without one they stay unassigned until an admin places them.
+	DefaultDepartmentID *uuid.UUID
+	// JITProvisioning creates a user and employee record on first login
+	// when no account matches the identity.
+	JITProvisioning bool
+	// SyncRoles re-applies RoleMapping on every login.
+	SyncRoles bool
+	StateTTL  time.Duration
+}
+
+// OIDCConfigFromEnv reads the OIDC_* variables. SSO stays disabled unless
+// OIDC_ISSUER and OIDC_CLIENT_ID are set. OIDC_ROLE_MAPPING is a comma
+// separated list of group=role pairs and OIDC_DEFAULT_DEPARTMENT the ID of
+// the department provisioned employees join.
+func OIDCConfigFromEnv() OIDCConfig {
+	var defaultDepartmentID *uuid.UUID
+	if id, err := uuid.Parse(utils.GetEnv("OIDC_DEFAULT_DEPARTMENT", "")); err == nil {
+		defaultDepartmentID = &id
+	}
+
+	return OIDCConfig{
+		Provider: oidc.Config{
+			Issuer:       utils.GetEnv("OIDC_ISSUER", ""),
+			ClientID:     utils.GetEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: utils.GetEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  utils.GetEnv("OIDC_REDIRECT_URL", "http://localhost:5173/auth/callback"),
+			Scopes:       strings.Fields(utils.GetEnv("OIDC_SCOPES", "openid email profile")),
+			GroupsClaim:  utils.GetEnv("OIDC_GROUPS_CLAIM", "groups"),
+		},
+		RoleMapping:         ParseRoleMapping(utils.GetEnv("OIDC_ROLE_MAPPING", "")),
+		DefaultRole:         utils.GetEnv("OIDC_DEFAULT_ROLE", authz.RoleEmployee),
+		DefaultDepartmentID: defaultDepartmentID,
+		JITProvisioning:     strings.EqualFold(utils.GetEnv("OIDC_JIT_PROVISIONING", "false"), "true"),
+		SyncRoles:           strings.EqualFold(utils.GetEnv("OIDC_SYNC_ROLES", "false"), "true"),
+		StateTTL:            utils.GetEnvDuration("OIDC_STATE_TTL", 10*time.Minute),
+	}
+}
+
+func (c OIDCConfig) Enabled() bool {
+	return c.Provider.Issuer != "" && c.Provider.ClientID != ""
+}
+
+// ParseRoleMapping parses "group=role,group=role". Entries naming an
+// unknown role are ignored.
+func ParseRoleMapping(raw string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" || !authz.RoleExists(role) || role == authz.RoleServiceAccount {
+			continue
+		}
+		mapping[group] = role
+	}
+	return mapping
+}
+
+// MapGroupsToRole returns the highest-precedence role any of the groups maps
+// to, or "" when none does. Custom roles rank below manager and above
+// employee, in the order the groups are listed.
+func MapGroupsToRole(mapping map[string]string, groups []string) string {
+	matched := make(map[string]bool)
+	custom := ""
+	for _, group := range groups {
+		if role, ok := mapping[group]; ok {
+			matched[role] = true
+			if custom == "" && !authz.IsSystemRole(role) {
+				custom = role
+			}
+		}
+	}
+	for _, role := range ssoRolePrecedence {
+		if role == authz.RoleEmployee && custom != "" {
+			return custom
+		}
+		if matched[role] {
+			return role
+		}
+	}
+	return custom
+}
+
+// SSOService signs users in through an external OpenID provider using the
+// authorization-code flow with PKCE. A successful callback yields the same
+// LoginResult as a password login, including any MFA challenge.
+type SSOService interface {
+	AuthorizationURL() (string, error)
+	Callback(code, state, userAgent, ipAddress string) (*LoginResult, error)
+}
+
+type ssoService struct {
+	cfg          OIDCConfig
+	provider     *oidc.Provider
+	oidcRepo     repositories.OIDCRepository
+	userRepo     repositories.UserRepository
+	employeeRepo repositories.EmployeeRepository
+	authSvc      *AuthService
+	auditSvc     AuditService
+	history      EmploymentHistoryService
+}
+
+func NewSSOService(
+	cfg OIDCConfig,
+	provider *oidc.Provider,
+	oidcRepo repositories.OIDCRepository,
+	userRepo repositories.UserRepository,
+	employeeRepo repositories.EmployeeRepository,
+	authSvc *AuthService,
+	auditSvc AuditService,
+	history EmploymentHistoryService,
+) SSOService {
+	return &ssoService{
+		cfg:          cfg,
+		provider:     provider,
+		oidcRepo:     oidcRepo,
+		userRepo:     userRepo,
+		employeeRepo: employeeRepo,
+		authSvc:      authSvc,
+		auditSvc:     auditSvc,
+		history:      history,
+	}
+}
+
+// AuthorizationURL starts a login attempt. The state, nonce and PKCE
+// verifier are kept server side until the callback consumes them.
+func (s *ssoService) AuthorizationURL() (string, error) {
+	if s.provider == nil {
+		return "", ErrSSODisabled
+	}
+
+	state, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := utils.GenerateOpaqueToken(48)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	_ = s.oidcRepo.DeleteExpiredStates(now)
+
+	if err := s.oidcRepo.CreateState(&models.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(s.cfg.StateTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	return s.provider.AuthCodeURL(context.Background(), state, nonce, oidc.PKCEChallenge(verifier))
+}
+
+func (s *ssoService) Callback(code, state, userAgent, ipAddress string) (*LoginResult, error) {
+	if s.provider == nil {
+		return nil, ErrSSODisabled
+	}
+
+	loginState, err := s.oidcRepo.ConsumeState(utils.HashToken(state))
+	if err != nil || time.Now().After(loginState.ExpiresAt) {
+		return nil, errInvalidSSOState
+	}
+
+	identity, err := s.provider.Exchange(context.Background(), code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, errSSOProviderFailure
+	}
+
+	user, err := s.resolveUser(identity, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
+
+	if s.cfg.SyncRoles {
+		if role := MapGroupsToRole(s.cfg.RoleMapping, identity.Groups); role != "" && role != user.Role {
+			oldRole := user.Role
+			if err := s.userRepo.UpdateRole(user.ID, role); err != nil {
+				return nil, err
+			}
+			user.Role = role
+			user.PermissionVersion++
+			if employee, err := s.employeeRepo.FindByUserID(user.ID); err == nil {
+				if err := s.history.Track(employee.ID, EmploymentChange{
+					Type:   models.EmploymentEventRoleChange,
+					Role:   role,
+					Reason: "synced from identity provider groups",
+				}, &user.ID); err != nil {
+					return nil, err
+				}
+			}
+			s.auditSvc.Log(user.ID, "SSO_ROLE_SYNCED", "user", &user.ID, map[string]interface{}{
+				"old_role": oldRole,
+				"new_role": role,
+			})
+		}
+	}
+
+	s.auditSvc.Log(user.ID, "SSO_LOGIN", "user", &user.ID, map[string]interface{}{
+		"issuer":     identity.Issuer,
+		"ip_address": ipAddress,
+	})
+
+	return s.authSvc.completeLogin(user, userAgent, ipAddress)
+}
+
+// resolveUser finds the user for an identity: first by an existing link,
+// then by verified email (creating the link), then by provisioning. Service
+// accounts authenticate with API keys only, as with password login.
+func (s *ssoService) resolveUser(identity *oidc.Identity, ipAddress string) (*models.User, error) {
+	link, err := s.oidcRepo.FindIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(link.UserID)
+		if err == nil && user.Role == authz.RoleServiceAccount {
+			return nil, ErrSSONoAccount
+		}
+		return user, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Only a verified email may claim an existing account.
+	email := strings.TrimSpace(identity.Email)
+	if email == "" || !identity.EmailVerified {
+		return nil, ErrSSONoAccount
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	switch {
+	case err == nil && user.Role == authz.RoleServiceAccount:
+		return nil, ErrSSONoAccount
+	case err == nil:
+	case errors.Is(err, gorm.ErrRecordNotFound) && s.cfg.JITProvisioning:
+		if user, err = s.provisionUser(identity, email); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, ErrSSONoAccount
+	default:
+		return nil, err
+	}
+
+	if err := s.oidcRepo.CreateIdentity(&models.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   email,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.auditSvc.Log(user.ID, "SSO_IDENTITY_LINKED", "user", &user.ID, map[string]interface{}{
+		"issuer":     identity.Issuer,
+		"subject":    identity.Subject,
+		"ip_address": ipAddress,
+	})
+	return user, nil
+}
+
+// provisionUser creates a user and employee record for a first-time SSO
+// login. The random password keeps password login unusable until the user
+// resets it.
+func (s *ssoService) provisionUser(identity *oidc.Identity, email string) (*models.User, error) {
+	role := MapGroupsToRole(s.cfg.RoleMapping, identity.Groups)
+	if role == "" {
+		role = s.cfg.DefaultRole
+	}
+
+	password, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:        email,
+		PasswordHash: hash,
+		Role:         role,
+		IsActive:     true,
+	}
+	// The ID is needed up front: the user is the actor of their own hire
+	user.ID = uuid.New()
+
+	firstName := identity.GivenName
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(email, "@")
+	}
+
+	employee := &models.Employee{
+		DepartmentID: s.cfg.DefaultDepartmentID,
+		Status:       "active",
+		HireDate:     employmentDay(time.Now()),
+		FirstName:    firstName,
+		LastName:     identity.FamilyName,
+	}
+
+	// User, employee, hire event and audit entry are written together so a
+	// failed login cannot leave an account without its employee record.
+	err = s.employeeRepo.CreateHires([]repositories.NewHire{{
+		User:     user,
+		Employee: employee,
+		Event:    newAppliedEvent(uuid.Nil, newHireChange(employee, role), &user.ID),
+		Audit: newAuditLog(user.ID, "SSO_USER_PROVISIONED", "employee", nil, map[string]interface{}{
+			"issuer": identity.Issuer,
+			"email":  email,
+			"role":   role,
+		}),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/authz"
+	"go-backend/internal/models"
+	"go-backend/internal/oidc"
+	"go-backend/internal/repositories"
+)
+
+type memoryOIDCRepo struct {
+	repositories.OIDCRepository
+	identities []models.UserIdentity
+}
+
+func (r *memoryOIDCRepo) FindIdentity(issuer, subject string) (*models.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			found := identity
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOIDCRepo) CreateIdentity(identity *models.UserIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func TestProvisionUserWritesHireAtOnceIntoDefaultDepartment(t *testing.T) {
+	departmentID := uuid.New()
+	employees := newMemoryEmployeeRepo()
+	svc := &ssoService{
+		cfg: OIDCConfig{
+			RoleMapping:         map[string]string{"staff": authz.RoleEmployee},
+			DefaultRole:         authz.RoleEmployee,
+			DefaultDepartmentID: &departmentID,
+		},
+		employeeRepo: employees,
+	}
+
+	user, err := svc.provisionUser(&oidc.Identity{
+		Issuer:     "https://idp.example.com",
+		GivenName:  "Ada",
+		FamilyName: "Lovelace",
+		Groups:     []string{"staff"},
+	}, "ada@example.com")
+	if err != nil {
+		t.Fatalf("provision: %v", err)
+	}
+
+	if len(employees.hires) != 1 {
+		t.Fatalf("expected one CreateHires call, got %d", len(employees.hires))
+	}
+	hire := employees.hires[0]
+	if hire.User != user || !user.IsActive || user.Role != authz.RoleEmployee {
+		t.Fatalf("expected an active employee account, got %+v", user)
+	}
+	if want := employmentDay(time.Now()); !hire.Employee.HireDate.Equal(want) {
+		t.Fatalf("expected the hire date to be today in UTC, got %v", hire.Employee.HireDate)
+	}
+	if hire.Employee.DepartmentID == nil || *hire.Employee.DepartmentID != departmentID {
+		t.Fatalf("expected the default department, got %v", hire.Employee.DepartmentID)
+	}
+	if hire.Event == nil || hire.Event.Type != models.EmploymentEventHire || hire.Event.DepartmentID == nil {
+		t.Fatalf("expected a hire event into the department, got %+v", hire.Event)
+	}
+	if hire.Audit == nil || hire.Audit.Action != "SSO_USER_PROVISIONED" || hire.Audit.UserID == nil || *hire.Audit.UserID != user.ID {
+		t.Fatalf("expected the provisioning to be audited in the same write, got %+v", hire.Audit)
+	}
+}
+
+func TestResolveUserRefusesServiceAccounts(t *testing.T) {
+	account := &models.User{Email: "ci@example.com", Role: authz.RoleServiceAccount, IsActive: true}
+	users := newMemoryUserRepo(account)
+	identities := &memoryOIDCRepo{}
+	svc := &ssoService{userRepo: users, oidcRepo: identities, auditSvc: &memoryAudit{}}
+
+	identity := &oidc.Identity{Issuer: "https://idp.example.com", Subject: "ci", Email: "ci@example.com", EmailVerified: true}
+	if _, err := svc.resolveUser(identity, "127.0.0.1"); !errors.Is(err, ErrSSONoAccount) {
+		t.Fatalf("expected a service account email to be refused, got %v", err)
+	}
+	if len(identities.identities) != 0 {
+		t.Fatalf("expected no identity to be linked, got %+v", identities.identities)
+	}
+
+	identities.identities = []models.UserIdentity{{UserID: account.ID, Issuer: identity.Issuer, Subject: identity.Subject}}
+	if _, err := svc.resolveUser(identity, "127.0.0.1"); !errors.Is(err, ErrSSONoAccount) {
+		t.Fatalf("expected a linked service account to be refused, got %v", err)
+	}
+}
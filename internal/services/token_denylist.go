@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+)
+
+// denylistRecheckInterval bounds how long another instance may keep
+// accepting a token after it was revoked elsewhere.
+const denylistRecheckInterval = 5 * time.Second
+
+// sessionTouchInterval limits last-seen bookkeeping to one write per session
+// per interval.
+const sessionTouchInterval = time.Minute
+
+// TokenDenylist rejects access tokens before their natural expiry. Lookups
+// are served from memory and fall back to the database once the cached
+// answer is older than denylistRecheckInterval.
+type TokenDenylist interface {
+	RevokeToken(jti string, userID uuid.UUID, expiresAt time.Time) error
+	RevokeAllForUser(userID uuid.UUID) error
+	IsRevoked(jti string, userID uuid.UUID, issuedAt time.Time) bool
+
+	// RevokeSession rejects every access token carrying the session's sid.
+	RevokeSession(sessionID uuid.UUID, revokedBy *uuid.UUID) error
+	// IsSessionRevoked also records the session as recently seen.
+	IsSessionRevoked(sessionID uuid.UUID) bool
+
+	// PermissionState returns the user's current role and permission
+	// version. ok is false when the user cannot be loaded.
+	PermissionState(userID uuid.UUID) (state PermissionState, ok bool)
+}
+
+// PermissionState is the authoritative role of a user, used to re-evaluate
+// tokens issued before a role change.
+type PermissionState struct {
+	Role    string
+	Version int
+}
+
+type denylistEntry struct {
+	revoked   bool
+	checkedAt time.Time
+	expiresAt time.Time
+}
+
+type sessionState struct {
+	revoked   bool
+	checkedAt time.Time
+}
+
+type userTokenState struct {
+	active      bool
+	revokedAt   *time.Time
+	permissions PermissionState
+	checkedAt   time.Time
+}
+
+type tokenDenylist struct {
+	repo        repositories.RevokedTokenRepository
+	userRepo    repositories.UserRepository
+	sessionRepo repositories.SessionRepository
+
+	mu       sync.Mutex
+	tokens   map[string]denylistEntry
+	users    map[uuid.UUID]userTokenState
+	sessions map[uuid.UUID]sessionState
+}
+
+func NewTokenDenylist(
+	repo repositories.RevokedTokenRepository,
+	userRepo repositories.UserRepository,
+	sessionRepo repositories.SessionRepository,
+) TokenDenylist {
+	d := &tokenDenylist{
+		repo:        repo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		tokens:      make(map[string]denylistEntry),
+		users:       make(map[uuid.UUID]userTokenState),
+		sessions:    make(map[uuid.UUID]sessionState),
+	}
+	go d.pruneLoop(10 * time.Minute)
+	return d
+}
+
+func (d *tokenDenylist) RevokeToken(jti string, userID uuid.UUID, expiresAt time.Time) error {
+	if jti == "" {
+		return nil
+	}
+
+	if err := d.repo.Create(&models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.tokens[jti] = denylistEntry{revoked: true, checkedAt: time.Now(), expiresAt: expiresAt}
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *tokenDenylist) RevokeAllForUser(userID uuid.UUID) error {
+	now := time.Now().UTC()
+	if err := d.userRepo.RevokeTokens(userID, now); err != nil {
+		return err
+	}
+	if err := d.sessionRepo.RevokeAllForUser(userID, now); err != nil {
+		return err
+	}
+
+	// Drop the cached state so the next check reloads the new cutoff.
+	d.mu.Lock()
+	delete(d.users, userID)
+	d.mu.Unlock()
+	return nil
+}
+
+// IsRevoked also rejects every token of a deactivated user. It fails closed:
+// if the database cannot be consulted the token is treated as revoked.
+func (d *tokenDenylist) IsRevoked(jti string, userID uuid.UUID, issuedAt time.Time) bool {
+	state, ok := d.userState(userID)
+	if !ok || !state.active {
+		return true
+	}
+	// iat has whole-second precision, so a token issued in the second of the
+	// cutoff, such as on a login right after "logout everywhere", survives.
+	// Tokens issued earlier in that second still die with their session.
+	if state.revokedAt != nil && issuedAt.Before(state.revokedAt.Truncate(time.Second)) {
+		return true
+	}
+
+	if jti == "" {
+		return false
+	}
+
+	d.mu.Lock()
+	entry, cached := d.tokens[jti]
+	d.mu.Unlock()
+	if cached && (entry.revoked || time.Since(entry.checkedAt) < denylistRecheckInterval) {
+		return entry.revoked
+	}
+
+	revoked, err := d.repo.Exists(jti)
+	if err != nil {
+		return true
+	}
+
+	d.mu.Lock()
+	d.tokens[jti] = denylistEntry{revoked: revoked, checkedAt: time.Now(), expiresAt: time.Now().Add(accessTokenTTL)}
+	d.mu.Unlock()
+	return revoked
+}
+
+func (d *tokenDenylist) RevokeSession(sessionID uuid.UUID, revokedBy *uuid.UUID) error {
+	if err := d.sessionRepo.Revoke(sessionID, revokedBy, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.sessions[sessionID] = sessionState{revoked: true, checkedAt: time.Now()}
+	d.mu.Unlock()
+	return nil
+}
+
+// IsSessionRevoked fails closed on database errors. Sessions without a
+// record (tokens issued before sessions were tracked) are not revoked.
+func (d *tokenDenylist) IsSessionRevoked(sessionID uuid.UUID) bool {
+	d.mu.Lock()
+	entry, cached := d.sessions[sessionID]
+	d.mu.Unlock()
+	if cached && (entry.revoked || time.Since(entry.checkedAt) < denylistRecheckInterval) {
+		return entry.revoked
+	}
+
+	now := time.Now().UTC()
+	session, err := d.sessionRepo.FindByID(sessionID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		entry = sessionState{checkedAt: now}
+	case err != nil:
+		return true
+	default:
+		entry = sessionState{revoked: session.RevokedAt != nil, checkedAt: now}
+		if !entry.revoked && now.Sub(session.LastSeenAt) > sessionTouchInterval {
+			_ = d.sessionRepo.Touch(sessionID, now, "", "", nil)
+		}
+	}
+
+	d.mu.Lock()
+	d.sessions[sessionID] = entry
+	d.mu.Unlock()
+	return entry.revoked
+}
+
+func (d *tokenDenylist) PermissionState(userID uuid.UUID) (PermissionState, bool) {
+	state, ok := d.userState(userID)
+	if !ok {
+		return PermissionState{}, false
+	}
+	return state.permissions, true
+}
+
+func (d *tokenDenylist) userState(userID uuid.UUID) (userTokenState, bool) {
+	d.mu.Lock()
+	entry, cached := d.users[userID]
+	d.mu.Unlock()
+	if cached && time.Since(entry.checkedAt) < denylistRecheckInterval {
+		return entry, true
+	}
+
+	user, err := d.userRepo.FindByID(userID)
+	if err != nil {
+		return userTokenState{}, false
+	}
+
+	entry = userTokenState{
+		active:      user.IsActive,
+		revokedAt:   user.TokensRevokedAt,
+		permissions: PermissionState{Role: user.Role, Version: user.PermissionVersion},
+		checkedAt:   time.Now(),
+	}
+
+	d.mu.Lock()
+	d.users[userID] = entry
+	d.mu.Unlock()
+	return entry, true
+}
+
+func (d *tokenDenylist) pruneLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+
+		d.mu.Lock()
+		for jti, entry := range d.tokens {
+			if now.After(entry.expiresAt) {
+				delete(d.tokens, jti)
+			}
+		}
+		for userID, entry := range d.users {
+			if now.Sub(entry.checkedAt) > interval {
+				delete(d.users, userID)
+			}
+		}
+		for sessionID, entry := range d.sessions {
+			if now.Sub(entry.checkedAt) > interval {
+				delete(d.sessions, sessionID)
+			}
+		}
+		d.mu.Unlock()
+
+		_ = d.repo.DeleteExpired(now.UTC())
+	}
+}
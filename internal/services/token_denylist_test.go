@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+)
+
+func TestIsRevokedComparesCutoffToTheSecond(t *testing.T) {
+	cutoff := time.Date(2026, 3, 2, 9, 30, 15, 700_000_000, time.UTC)
+	user := &models.User{IsActive: true, TokensRevokedAt: &cutoff}
+	d := &tokenDenylist{
+		userRepo: newMemoryUserRepo(user),
+		tokens:   map[string]denylistEntry{},
+		users:    map[uuid.UUID]userTokenState{},
+		sessions: map[uuid.UUID]sessionState{},
+	}
+
+	// iat of a token issued at 09:30:15.900, after the cutoff
+	if d.IsRevoked("", user.ID, cutoff.Truncate(time.Second)) {
+		t.Fatal("a token issued in the same second as the cutoff should be accepted")
+	}
+	if !d.IsRevoked("", user.ID, cutoff.Add(-time.Second).Truncate(time.Second)) {
+		t.Fatal("a token issued before the cutoff second should be revoked")
+	}
+}
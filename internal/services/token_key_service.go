@@ -0,0 +1,293 @@
+package services
+
+import (
+	"crypto"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"go-backend/internal/models"
+	"go-backend/internal/repositories"
+	"go-backend/pkg/utils"
+)
+
+// unknownKIDReloadInterval limits how often an unknown kid triggers a reload,
+// so tokens signed by a key another instance just created still verify.
+const unknownKIDReloadInterval = 10 * time.Second
+
+// TokenKeyConfig controls asymmetric JWT signing.
+type TokenKeyConfig struct {
+	Algorithm        string
+	RotationInterval time.Duration
+	EncryptionKey    string
+
+	// LegacySecret verifies HS256 tokens issued before asymmetric signing was
+	// introduced, for LegacyGrace after the first signing key was created.
+	LegacySecret string
+	LegacyGrace  time.Duration
+}
+
+func TokenKeyConfigFromEnv(jwtSecret string) TokenKeyConfig {
+	return TokenKeyConfig{
+		Algorithm:        utils.GetEnv("JWT_SIGNING_ALG", utils.AlgRS256),
+		RotationInterval: utils.GetEnvDuration("JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+		EncryptionKey:    utils.GetEnv("JWT_KEY_ENCRYPTION_KEY", jwtSecret),
+		LegacySecret:     jwtSecret,
+		LegacyGrace:      utils.GetEnvDuration("JWT_HS256_GRACE", refreshTokenTTL),
+	}
+}
+
+// TokenKeyService signs tokens with the current key, verifies them against
+// every published key and exposes the public keys as a JWKS document.
+type TokenKeyService interface {
+	utils.TokenSigner
+	utils.TokenVerifier
+	JWKS() utils.JWKS
+	Rotate() error
+}
+
+type loadedKey struct {
+	kid       string
+	alg       string
+	signer    crypto.Signer
+	method    jwt.SigningMethod
+	createdAt time.Time
+}
+
+type tokenKeyService struct {
+	repo repositories.SigningKeyRepository
+	cfg  TokenKeyConfig
+
+	mu          sync.RWMutex
+	signing     *loadedKey
+	keys        map[string]*loadedKey
+	legacyUntil time.Time
+	lastReload  time.Time
+}
+
+// NewTokenKeyService loads the stored keys, creating the first one if none
+// exist, and starts the background rotation check.
+func NewTokenKeyService(repo repositories.SigningKeyRepository, cfg TokenKeyConfig) (TokenKeyService, error) {
+	if _, err := utils.SigningMethod(cfg.Algorithm); err != nil {
+		return nil, err
+	}
+
+	s := &tokenKeyService{repo: repo, cfg: cfg, keys: make(map[string]*loadedKey)}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	if s.rotationDue() {
+		if err := s.rotate(s.staleBefore()); err != nil {
+			return nil, err
+		}
+	}
+
+	go s.maintainLoop(time.Minute)
+	return s, nil
+}
+
+func (s *tokenKeyService) Sign(claims *utils.JWTClaims) (string, error) {
+	s.mu.RLock()
+	key := s.signing
+	s.mu.RUnlock()
+	if key == nil {
+		return "", errors.New("no active signing key")
+	}
+
+	utils.StampClaims(claims)
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.signer)
+}
+
+func (s *tokenKeyService) Verify(tokenStr string) (*utils.JWTClaims, error) {
+	claims := &utils.JWTClaims{}
+	token, err := jwt.ParseWithClaims(
+		tokenStr,
+		claims,
+		s.keyFunc,
+		jwt.WithValidMethods([]string{utils.AlgRS256, utils.AlgEdDSA, jwt.SigningMethodHS256.Alg()}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func (s *tokenKeyService) JWKS() utils.JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc := utils.JWKS{Keys: make([]utils.JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		jwk, err := utils.PublicJWK(key.kid, key.alg, key.signer)
+		if err == nil {
+			doc.Keys = append(doc.Keys, jwk)
+		}
+	}
+	return doc
+}
+
+// Rotate creates a new signing key and retires the previous ones. Retired
+// keys keep verifying until the longest-lived token they signed expires.
+func (s *tokenKeyService) Rotate() error {
+	return s.rotate(time.Now().UTC())
+}
+
+// rotate creates a key unless another instance already created one after
+// staleBefore, then reloads so either key is picked up.
+func (s *tokenKeyService) rotate(staleBefore time.Time) error {
+	signer, err := utils.GenerateSigningKey(s.cfg.Algorithm)
+	if err != nil {
+		return err
+	}
+	pemKey, err := utils.MarshalPrivateKeyPEM(signer)
+	if err != nil {
+		return err
+	}
+	encrypted, err := utils.EncryptString(pemKey, s.cfg.EncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	record := &models.SigningKey{
+		KID:                 uuid.NewString(),
+		Algorithm:           s.cfg.Algorithm,
+		PrivateKeyEncrypted: encrypted,
+	}
+	if _, err := s.repo.Rotate(record, staleBefore); err != nil {
+		return err
+	}
+
+	return s.reload()
+}
+
+func (s *tokenKeyService) keyFunc(t *jwt.Token) (interface{}, error) {
+	alg := t.Method.Alg()
+	if alg == jwt.SigningMethodHS256.Alg() {
+		s.mu.RLock()
+		legacyUntil := s.legacyUntil
+		s.mu.RUnlock()
+		if s.cfg.LegacySecret == "" || time.Now().After(legacyUntil) {
+			return nil, errors.New("HS256 tokens are no longer accepted")
+		}
+		return []byte(s.cfg.LegacySecret), nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key := s.lookup(kid)
+	if key == nil {
+		return nil, errors.New("unknown signing key")
+	}
+	if key.alg != alg {
+		return nil, errors.New("signing algorithm mismatch")
+	}
+	return key.signer.Public(), nil
+}
+
+func (s *tokenKeyService) lookup(kid string) *loadedKey {
+	s.mu.RLock()
+	key := s.keys[kid]
+	lastReload := s.lastReload
+	s.mu.RUnlock()
+
+	if key == nil && kid != "" && time.Since(lastReload) > unknownKIDReloadInterval {
+		if err := s.reload(); err == nil {
+			s.mu.RLock()
+			key = s.keys[kid]
+			s.mu.RUnlock()
+		}
+	}
+	return key
+}
+
+func (s *tokenKeyService) reload() error {
+	now := time.Now().UTC()
+	records, err := s.repo.ListUsable(now.Add(-refreshTokenTTL))
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*loadedKey, len(records))
+	var signing *loadedKey
+	for _, record := range records {
+		pemKey, err := utils.DecryptString(record.PrivateKeyEncrypted, s.cfg.EncryptionKey)
+		if err != nil {
+			return err
+		}
+		signer, err := utils.ParsePrivateKeyPEM(pemKey)
+		if err != nil {
+			return err
+		}
+		method, err := utils.SigningMethod(record.Algorithm)
+		if err != nil {
+			return err
+		}
+
+		key := &loadedKey{
+			kid:       record.KID,
+			alg:       record.Algorithm,
+			signer:    signer,
+			method:    method,
+			createdAt: record.CreatedAt,
+		}
+		keys[key.kid] = key
+		// Records are newest first; the newest unretired key signs.
+		if signing == nil && record.RetiredAt == nil {
+			signing = key
+		}
+	}
+
+	legacyUntil := now.Add(s.cfg.LegacyGrace)
+	if oldest, err := s.repo.FindOldest(); err == nil {
+		legacyUntil = oldest.CreatedAt.Add(s.cfg.LegacyGrace)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.signing = signing
+	s.legacyUntil = legacyUntil
+	s.lastReload = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *tokenKeyService) rotationDue() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.signing == nil {
+		return true
+	}
+	return s.cfg.RotationInterval > 0 && time.Since(s.signing.createdAt) > s.cfg.RotationInterval
+}
+
+// staleBefore is the creation time an active key must be newer than for a
+// due rotation to be skipped; without an interval only a missing key is due.
+func (s *tokenKeyService) staleBefore() time.Time {
+	if s.cfg.RotationInterval <= 0 {
+		return time.Time{}
+	}
+	return time.Now().UTC().Add(-s.cfg.RotationInterval)
+}
+
+// maintainLoop picks up keys created by other instances and rotates the
+// signing key once it is older than the rotation interval.
+func (s *tokenKeyService) maintainLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.reload(); err != nil {
+			continue
+		}
+		if s.rotationDue() {
+			_ = s.rotate(s.staleBefore())
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go-backend/internal/models"
+	"go-backend/pkg/utils"
+)
+
+type memorySigningKeyRepo struct {
+	keys []models.SigningKey
+}
+
+func (r *memorySigningKeyRepo) Rotate(key *models.SigningKey, staleBefore time.Time) (bool, error) {
+	for _, existing := range r.keys {
+		if existing.RetiredAt == nil && existing.CreatedAt.After(staleBefore) {
+			return false, nil
+		}
+	}
+
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now().UTC().Add(time.Duration(len(r.keys)) * time.Millisecond)
+	now := time.Now().UTC()
+	for i := range r.keys {
+		if r.keys[i].RetiredAt == nil {
+			r.keys[i].RetiredAt = &now
+		}
+	}
+	r.keys = append(r.keys, *key)
+	return true, nil
+}
+
+func (r *memorySigningKeyRepo) ListUsable(retiredAfter time.Time) ([]models.SigningKey, error) {
+	out := make([]models.SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		if key.RetiredAt == nil || key.RetiredAt.After(retiredAfter) {
+			out = append(out, key)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *memorySigningKeyRepo) FindOldest() (*models.SigningKey, error) {
+	if len(r.keys) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	oldest := r.keys[0]
+	return &oldest, nil
+}
+
+func newTestClaims() *utils.JWTClaims {
+	return &utils.JWTClaims{
+		UserID:    uuid.NewString(),
+		TokenType: utils.TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestTokenKeyServiceRotationKeepsOldTokensValid(t *testing.T) {
+	for _, alg := range []string{utils.AlgRS256, utils.AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			repo := &memorySigningKeyRepo{}
+			svc, err := NewTokenKeyService(repo, TokenKeyConfig{
+				Algorithm:     alg,
+				EncryptionKey: "test-key",
+				LegacySecret:  "legacy-secret",
+				LegacyGrace:   time.Hour,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			before, err := svc.Sign(newTestClaims())
+			if err != nil {
+				t.Fatalf("sign failed: %v", err)
+			}
+			if err := svc.Rotate(); err != nil {
+				t.Fatalf("rotate failed: %v", err)
+			}
+			after, err := svc.Sign(newTestClaims())
+			if err != nil {
+				t.Fatalf("sign failed: %v", err)
+			}
+
+			for _, token := range []string{before, after} {
+				if _, err := svc.Verify(token); err != nil {
+					t.Fatalf("expected token to verify: %v", err)
+				}
+			}
+			if got := len(svc.JWKS().Keys); got != 2 {
+				t.Fatalf("expected 2 published keys, got %d", got)
+			}
+
+			legacy, _ := utils.HMACKey("legacy-secret").Sign(newTestClaims())
+			if _, err := svc.Verify(legacy); err != nil {
+				t.Fatalf("expected HS256 token to verify during grace: %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenKeyServiceRejectsHS256AfterGrace(t *testing.T) {
+	repo := &memorySigningKeyRepo{}
+	svc, err := NewTokenKeyService(repo, TokenKeyConfig{
+		Algorithm:     utils.AlgEdDSA,
+		EncryptionKey: "test-key",
+		LegacySecret:  "legacy-secret",
+		LegacyGrace:   -time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	legacy, _ := utils.HMACKey("legacy-secret").Sign(newTestClaims())
+	if _, err := svc.Verify(legacy); err == nil {
+		t.Fatal("expected HS256 token to be rejected after grace period")
+	}
+}
+
+func TestTokenKeyServiceSkipsRotationAnotherInstanceAlreadyDid(t *testing.T) {
+	repo := &memorySigningKeyRepo{}
+	cfg := TokenKeyConfig{Algorithm: utils.AlgEdDSA, EncryptionKey: "test-key", RotationInterval: time.Hour}
+	first, err := NewTokenKeyService(repo, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := NewTokenKeyService(repo, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.keys) != 1 {
+		t.Fatalf("expected the second instance to reuse the first key, got %d keys", len(repo.keys))
+	}
+
+	// Both instances saw the key go stale; only the first rotation creates one.
+	stale := time.Now().UTC()
+	for _, svc := range []TokenKeyService{first, second} {
+		if err := svc.(*tokenKeyService).rotate(stale); err != nil {
+			t.Fatalf("rotate failed: %v", err)
+		}
+	}
+	if len(repo.keys) != 2 {
+		t.Fatalf("expected exactly one new key, got %d keys", len(repo.keys))
+	}
+
+	token, err := second.Sign(newTestClaims())
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if _, err := first.Verify(token); err != nil {
+		t.Fatalf("expected both instances to sign with the same key: %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+// Package spreadsheet reads tabular uploads as rows of strings. It supports
+// CSV and the first worksheet of an XLSX workbook, which is enough for
+// imports without pulling in a full Office library.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Row is one non-blank row and its 1-based line or row number in the file,
+// so callers can point users at the offending row.
+type Row struct {
+	Number int
+	Cells  []string
+}
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format; upload a .csv or .xlsx file")
+	errNoWorksheet       = errors.New("workbook has no worksheet")
+)
+
+// Read parses data according to the extension of filename. Blank rows are
+// dropped and every row is trimmed of surrounding whitespace.
+func Read(filename string, data []byte) ([]Row, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return ReadCSV(bytes.NewReader(data))
+	case ".xlsx":
+		return ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ReadCSV parses comma-separated rows; rows may have differing lengths.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if line == 1 && len(record) > 0 {
+			// Excel prefixes UTF-8 CSV exports with a byte order mark
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		rows = appendRow(rows, line, record)
+	}
+	return rows, nil
+}
+
+// ReadXLSX parses the first worksheet of a workbook. Cells are returned as
+// they are stored: numbers and dates come back in their raw form.
+func ReadXLSX(r io.ReaderAt, size int64) ([]Row, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sharedStrings, err := readSharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := firstWorksheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var worksheet struct {
+		Rows []struct {
+			Number int `xml:"r,attr"`
+			Cells  []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string `xml:",innerxml"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeXML(sheet, &worksheet); err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	number := 0
+	for _, row := range worksheet.Rows {
+		number++
+		if row.Number > 0 {
+			number = row.Number
+		}
+
+		var record []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			for len(record) < column {
+				record = append(record, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, errors.New("workbook references a missing shared string")
+				}
+				value = sharedStrings[index]
+			case "inlineStr":
+				value = textContent(cell.Inline.Text)
+			}
+			record = append(record, value)
+		}
+		rows = appendRow(rows, number, record)
+	}
+	return rows, nil
+}
+
+// firstWorksheet follows the workbook relationships to the first sheet,
+// falling back to the conventional sheet1.xml.
+func firstWorksheet(files map[string]*zip.File) (*zip.File, error) {
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	if files["xl/workbook.xml"] != nil && files["xl/_rels/workbook.xml.rels"] != nil {
+		if err := decodeXML(files["xl/workbook.xml"], &workbook); err != nil {
+			return nil, err
+		}
+		if err := decodeXML(files["xl/_rels/workbook.xml.rels"], &relationships); err != nil {
+			return nil, err
+		}
+		if len(workbook.Sheets) > 0 {
+			for _, rel := range relationships.Items {
+				if rel.ID != workbook.Sheets[0].RelID {
+					continue
+				}
+				target := strings.TrimPrefix(rel.Target, "/")
+				if !strings.HasPrefix(target, "xl/") {
+					target = path.Join("xl", target)
+				}
+				if file := files[target]; file != nil {
+					return file, nil
+				}
+			}
+		}
+	}
+
+	if file := files["xl/worksheets/sheet1.xml"]; file != nil {
+		return file, nil
+	}
+	return nil, errNoWorksheet
+}
+
+func readSharedStrings(file *zip.File) ([]string, error) {
+	if file == nil {
+		return nil, nil
+	}
+	var table struct {
+		Items []struct {
+			Text string `xml:",innerxml"`
+		} `xml:"si"`
+	}
+	if err := decodeXML(file, &table); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(table.Items))
+	for _, item := range table.Items {
+		values = append(values, textContent(item.Text))
+	}
+	return values, nil
+}
+
+// textContent concatenates the <t> runs of a string item, skipping phonetic
+// guides, so rich text reads as plain text.
+func textContent(innerXML string) string {
+	decoder := xml.NewDecoder(strings.NewReader(innerXML))
+	var text strings.Builder
+	depth := 0
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return text.String()
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "rPh" {
+				depth++
+			}
+			inText = t.Name.Local == "t" && depth == 0
+		case xml.EndElement:
+			if t.Name.Local == "rPh" {
+				depth--
+			}
+			inText = false
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+func decodeXML(file *zip.File, target interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return xml.NewDecoder(reader).Decode(target)
+}
+
+// columnIndex converts the letters of a cell reference such as "AB12" to a
+// zero-based column number.
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+	}
+	return index - 1
+}
+
+func appendRow(rows []Row, number int, record []string) []Row {
+	blank := true
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+		if record[i] != "" {
+			blank = false
+		}
+	}
+	if blank {
+		return rows
+	}
+	return append(rows, Row{Number: number, Cells: record})
+}
@@ -0,0 +1,64 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReadXLSXResolvesSharedStringsAndGaps(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="People" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="worksheet" Target="worksheets/people.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>email</t></si><si><r><t>Ada</t></r><r><t> Lovelace</t></r></si></sst>`,
+		"xl/worksheets/people.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="inlineStr"><is><t>name</t></is></c></row>
+<row r="2"><c r="A2"><v></v></c></row>
+<row r="3"><c r="B3" t="s"><v>1</v></c><c r="D3"><v>42</v></c></row>
+</sheetData></worksheet>`,
+	}
+	for name, body := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := Read("people.XLSX", buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Row{
+		{Number: 1, Cells: []string{"email", "name"}},
+		{Number: 3, Cells: []string{"", "Ada Lovelace", "", "42"}},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %q, got %q", expected, rows)
+	}
+}
+
+func TestReadCSVStripsByteOrderMark(t *testing.T) {
+	rows, err := Read("people.csv", []byte("\ufeffemail, name\n\n a@example.com ,Ada\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Row{
+		{Number: 1, Cells: []string{"email", "name"}},
+		{Number: 3, Cells: []string{"a@example.com", "Ada"}},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %q, got %q", expected, rows)
+	}
+}
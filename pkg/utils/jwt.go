@@ -1,9 +1,18 @@
 package utils
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	TokenTypeAccess         = "access"
+	TokenTypeRefresh        = "refresh"
+	TokenTypeMFA            = "mfa"
+	TokenTypePasswordChange = "password_change"
 )
 
 type JWTClaims struct {
@@ -11,21 +20,90 @@ type JWTClaims struct {
 	Role        string   `json:"role"`
 	EmployeeID  string   `json:"employee_id"`
 	Permissions []string `json:"permissions,omitempty"`
+	TokenType   string   `json:"typ,omitempty"`
+	SessionID   string   `json:"sid,omitempty"`
+
+	// PermissionVersion is the user's permission version at issue time.
+	PermissionVersion int `json:"pv,omitempty"`
+
+	// ImpersonatorID is the real actor behind an impersonation token; UserID
+	// is then the user being viewed as.
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
 func GenerateToken(userID, employeeID, role string, permissions []string, secret string, ttl time.Duration) (string, error) {
-	claims := JWTClaims{
+	claims := &JWTClaims{
 		UserID:      userID,
 		Role:        role,
 		EmployeeID:  employeeID,
 		Permissions: permissions,
+		TokenType:   TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
+	return SignToken(claims, secret)
+}
+
+// TokenSigner issues signed tokens.
+type TokenSigner interface {
+	Sign(claims *JWTClaims) (string, error)
+}
+
+// TokenVerifier checks a token's signature and standard claims.
+type TokenVerifier interface {
+	Verify(tokenStr string) (*JWTClaims, error)
+}
+
+// HMACKey signs and verifies HS256 tokens with a shared secret.
+type HMACKey string
+
+func (k HMACKey) Sign(claims *JWTClaims) (string, error) {
+	return SignToken(claims, string(k))
+}
+
+func (k HMACKey) Verify(tokenStr string) (*JWTClaims, error) {
+	return ParseToken(tokenStr, string(k))
+}
+
+// SignToken signs claims with HS256, filling in a random jti and the issue
+// time when the caller has not set them. The claims are updated in place so
+// callers can persist the generated jti.
+func SignToken(claims *JWTClaims, secret string) (string, error) {
+	StampClaims(claims)
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
+
+// StampClaims fills in a random jti and the issue time when unset.
+func StampClaims(claims *JWTClaims) {
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
+	if claims.IssuedAt == nil {
+		claims.IssuedAt = jwt.NewNumericDate(time.Now())
+	}
+}
+
+// ParseToken verifies an HS256 token and returns its claims.
+func ParseToken(tokenStr, secret string) (*JWTClaims, error) {
+	claims := &JWTClaims{}
+	token, err := jwt.ParseWithClaims(
+		tokenStr,
+		claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// bcryptMaxBytes is the longest password bcrypt will hash.
+const bcryptMaxBytes = 72
+
+//go:embed common_passwords.txt
+var embeddedCommonPasswords string
+
+// PasswordPolicy is the single set of rules every password must satisfy,
+// whoever sets it.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	// HistorySize is how many previous passwords may not be reused.
+	HistorySize int
+	// MaxAge forces a change at login once a password is older; zero
+	// disables expiry.
+	MaxAge time.Duration
+	// BlocklistFile optionally extends the built-in common-password list,
+	// e.g. with a local copy of a breached-password corpus. One per line.
+	BlocklistFile string
+}
+
+// PasswordPolicyFromEnv reads the PASSWORD_* variables.
+func PasswordPolicyFromEnv() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     GetEnvInt("PASSWORD_MIN_LENGTH", 10),
+		RequireUpper:  strings.EqualFold(GetEnv("PASSWORD_REQUIRE_UPPER", "true"), "true"),
+		RequireLower:  strings.EqualFold(GetEnv("PASSWORD_REQUIRE_LOWER", "true"), "true"),
+		RequireDigit:  strings.EqualFold(GetEnv("PASSWORD_REQUIRE_DIGIT", "true"), "true"),
+		RequireSymbol: strings.EqualFold(GetEnv("PASSWORD_REQUIRE_SYMBOL", "false"), "true"),
+		HistorySize:   GetEnvInt("PASSWORD_HISTORY_SIZE", 5),
+		MaxAge:        GetEnvDuration("PASSWORD_MAX_AGE", 0),
+		BlocklistFile: GetEnv("PASSWORD_BLOCKLIST_FILE", ""),
+	}
+}
+
+// PasswordPolicyError lists every rule a password broke, so clients can show
+// them all at once.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet policy: " + strings.Join(e.Violations, "; ")
+}
+
+// Validate checks password against the policy. email, when given, must not
+// appear in the password.
+func (p PasswordPolicy) Validate(password, email string) error {
+	var violations []string
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if len(password) > bcryptMaxBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes", bcryptMaxBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if local, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@"); len(local) >= 3 &&
+		strings.Contains(strings.ToLower(password), local) {
+		violations = append(violations, "must not contain your email address")
+	}
+
+	if p.isCommon(password) {
+		violations = append(violations, "is too common")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// IsExpired reports whether a password set at changedAt must be changed.
+func (p PasswordPolicy) IsExpired(changedAt time.Time) bool {
+	return p.MaxAge > 0 && time.Since(changedAt) > p.MaxAge
+}
+
+// isCommon matches the password, and the password with its leading and
+// trailing digits and symbols removed ("Password123!" -> "password"),
+// against the blocklist.
+func (p PasswordPolicy) isCommon(password string) bool {
+	blocklist := loadPasswordBlocklist(p.BlocklistFile)
+
+	lowered := strings.ToLower(password)
+	if blocklist[lowered] {
+		return true
+	}
+	core := strings.TrimFunc(lowered, func(r rune) bool { return !unicode.IsLetter(r) })
+	return core != "" && blocklist[core]
+}
+
+var (
+	blocklistMu    sync.Mutex
+	blocklistCache = map[string]map[string]bool{}
+)
+
+func loadPasswordBlocklist(file string) map[string]bool {
+	blocklistMu.Lock()
+	defer blocklistMu.Unlock()
+
+	if cached, ok := blocklistCache[file]; ok {
+		return cached
+	}
+
+	blocklist := make(map[string]bool)
+	addPasswordLines(blocklist, bufio.NewScanner(strings.NewReader(embeddedCommonPasswords)))
+	if file != "" {
+		if f, err := os.Open(file); err == nil {
+			addPasswordLines(blocklist, bufio.NewScanner(f))
+			f.Close()
+		}
+	}
+
+	blocklistCache[file] = blocklist
+	return blocklist
+}
+
+func addPasswordLines(blocklist map[string]bool, scanner *bufio.Scanner) {
+	for scanner.Scan() {
+		if line := strings.ToLower(strings.TrimSpace(scanner.Text())); line != "" {
+			blocklist[line] = true
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true}
+
+	cases := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{"compliant", "Blue-Harbor-42", true},
+		{"too short", "Ab1cdef", false},
+		{"no uppercase", "blue-harbor-42", false},
+		{"no digit", "Blue-Harbor-X", false},
+		{"common with decoration", "Password1234!", false},
+		{"contains email", "Jdoe-Secure-2024", false},
+	}
+
+	for _, tc := range cases {
+		err := policy.Validate(tc.password, "jdoe@example.com")
+		if (err == nil) != tc.valid {
+			t.Errorf("%s: Validate(%q) = %v, want valid=%v", tc.name, tc.password, err, tc.valid)
+		}
+	}
+}
+
+func TestPasswordPolicyIsExpired(t *testing.T) {
+	if (PasswordPolicy{}).IsExpired(time.Now().Add(-365 * 24 * time.Hour)) {
+		t.Fatal("expiry must be disabled when MaxAge is zero")
+	}
+
+	policy := PasswordPolicy{MaxAge: 90 * 24 * time.Hour}
+	if !policy.IsExpired(time.Now().Add(-91 * 24 * time.Hour)) {
+		t.Fatal("expected a 91 day old password to be expired")
+	}
+	if policy.IsExpired(time.Now().Add(-time.Hour)) {
+		t.Fatal("expected a fresh password to be valid")
+	}
+}
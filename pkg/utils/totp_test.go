@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B SHA-1 vectors, truncated to six digits.
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range cases {
+		code, err := TOTPCode(secret, time.Unix(tc.unix, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if code != tc.code {
+			t.Fatalf("at %d expected %s, got %s", tc.unix, tc.code, code)
+		}
+	}
+}
+
+func TestValidateTOTPAllowsSkew(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	previous, _ := TOTPCode(secret, now.Add(-30*time.Second))
+
+	if _, ok := ValidateTOTP(secret, previous, now, 1); !ok {
+		t.Fatal("expected previous step to validate with skew 1")
+	}
+	if _, ok := ValidateTOTP(secret, previous, now, 0); ok {
+		t.Fatal("expected previous step to fail without skew")
+	}
+}
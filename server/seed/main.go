@@ -7,11 +7,11 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	"go-backend/internal/models"
+	"go-backend/pkg/utils"
 )
 
 func main() {
@@ -28,7 +28,14 @@ func main() {
 	}
 
 	email := getEnv("ADMIN_SEED_EMAIL", "admin@company.com")
-	password := getEnv("ADMIN_SEED_PASSWORD", "Admin@123")
+	policy := utils.PasswordPolicyFromEnv()
+	password := os.Getenv("ADMIN_SEED_PASSWORD")
+	if password == "" {
+		password, err = generateSeedPassword(policy, email)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	var user models.User
 	err = db.Where("email = ?", email).First(&user).Error
@@ -37,28 +44,47 @@ func main() {
 	}
 
 	if err == gorm.ErrRecordNotFound {
-		hashedPassword, hashErr := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if policyErr := policy.Validate(password, email); policyErr != nil {
+			log.Fatalf("ADMIN_SEED_PASSWORD rejected: %v", policyErr)
+		}
+
+		hashedPassword, hashErr := utils.HashPassword(password)
 		if hashErr != nil {
 			log.Fatal(hashErr)
 		}
 
+		now := time.Now().UTC()
 		user = models.User{
-			Email:        email,
-			PasswordHash: string(hashedPassword),
-			Role:         "admin",
-			IsActive:     true,
+			Email:             email,
+			PasswordHash:      hashedPassword,
+			Role:              "admin",
+			IsActive:          true,
+			PasswordChangedAt: &now,
 		}
 
 		if createErr := db.Create(&user).Error; createErr != nil {
 			log.Fatal(createErr)
 		}
+
+		history := models.PasswordHistory{UserID: user.ID, PasswordHash: hashedPassword}
+		if historyErr := db.Create(&history).Error; historyErr != nil {
+			log.Fatal(historyErr)
+		}
 	} else {
 		if user.Role != "admin" || !user.IsActive {
+			// Bump the permission version in the same statement so tokens
+			// issued before the promotion pick up the new role.
+			updateErr := db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+				"role":               "admin",
+				"is_active":          true,
+				"permission_version": gorm.Expr("permission_version + 1"),
+			}).Error
+			if updateErr != nil {
+				log.Fatal(updateErr)
+			}
 			user.Role = "admin"
 			user.IsActive = true
-			if saveErr := db.Save(&user).Error; saveErr != nil {
-				log.Fatal(saveErr)
-			}
+			user.PermissionVersion++
 		}
 	}
 
@@ -79,11 +105,49 @@ func main() {
 		if createEmpErr := db.Create(&employee).Error; createEmpErr != nil {
 			log.Fatal(createEmpErr)
 		}
+
+		role, status := user.Role, employee.Status
+		now := time.Now().UTC()
+		hire := models.EmploymentEvent{
+			EmployeeID:    employee.ID,
+			Type:          models.EmploymentEventHire,
+			EffectiveDate: employee.HireDate,
+			Role:          &role,
+			Status:        &status,
+			AppliedAt:     &now,
+		}
+		if createEventErr := db.Create(&hire).Error; createEventErr != nil {
+			log.Fatal(createEventErr)
+		}
 	}
 
 	fmt.Println("Admin user is ready:")
 	fmt.Printf("Email: %s\n", email)
-	fmt.Printf("Password: %s\n", password)
+	if err == gorm.ErrRecordNotFound {
+		fmt.Printf("Password: %s\n", password)
+	} else {
+		fmt.Println("Password: unchanged (user already existed)")
+	}
+}
+
+// generateSeedPassword returns a random password that satisfies policy, used
+// when ADMIN_SEED_PASSWORD is not set.
+func generateSeedPassword(policy utils.PasswordPolicy, email string) (string, error) {
+	size := policy.MinLength + 8
+	if size > 48 {
+		size = 48
+	}
+	for attempt := 0; attempt < 100; attempt++ {
+		random, err := utils.GenerateOpaqueToken(size)
+		if err != nil {
+			return "", err
+		}
+		password := random + "!"
+		if policy.Validate(password, email) == nil {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("could not generate a password for this policy; set ADMIN_SEED_PASSWORD")
 }
 
 func getEnv(key, fallback string) string {